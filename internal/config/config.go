@@ -7,75 +7,190 @@ type Options struct {
 	// Target
 	URL             string
 	URLsFile        string   // -l: file with one URL per line
-	WordlistPath    string   // empty = use embedded
+	TargetsJSON     bool     // -l holds JSON Lines targets with per-target headers (implied by .jsonl)
+	WordlistPaths   []string // merged in order; empty = use embedded
+	WordlistName    string   // built-in list (see wordlist.Names) merged before WordlistPaths
+	WordlistKeyword string   // placeholder in wordlist entries, e.g. FUZZ
+	KeywordWordlist string   // values substituted for WordlistKeyword
+	Mutate          bool     // add case/dot/slash variations of each entry
+	Prioritize      bool     // move high-value entries (PriorityWords or wordlist.PriorityWords) to the front
+	PriorityWords   []string // names moved to the front by Prioritize; empty = built-in list
+	NoURLEncode     bool     // send entries as-is instead of percent-encoding unsafe characters
+	WordlistOffset  int      // skip this many entries (for sharding across machines)
+	WordlistLimit   int      // use at most this many entries after the offset (0 = all)
 	Extensions      []string
+	ExtensionsFile  string // file of extensions, one per line, merged into Extensions
 	ForceExtensions bool
+	Prefix          string // prepended to every entry (e.g. api/v2/)
+	Suffix          string // appended to every entry, after extensions
 
 	// Performance
 	Threads          int
 	Timeout          time.Duration
 	Delay            time.Duration
-	AdaptiveThrottle bool // auto back-off on 429/rate limits
+	DelayJitter      string // random ± offset per request: duration ("200ms") or percent of Delay ("20%")
+	AdaptiveThrottle bool   // auto back-off on 429/rate limits
+	RateLimit        int    // max requests per second across all threads (0 = unlimited)
 
 	// Smart filter
 	SmartFilter          bool
-	SmartFilterThreshold int  // bytes tolerance
-	SmartFilterPerDir    bool // re-calibrate per subdirectory
-	DuplicateThreshold   int  // identical responses allowed before filtering (0 = disabled)
+	SmartFilterThreshold int    // bytes tolerance
+	SmartFilterProbes    int    // calibration requests per baseline (min 2)
+	CalibrateURL         string // known-missing path to calibrate against instead of random probes
+	SmartFilterPerDir    bool   // re-calibrate per subdirectory
+	ShareCalibration     bool   // reuse a calibration for targets answering unknown paths the same way
+	DuplicateThreshold   int    // identical responses allowed before filtering (0 = disabled)
+	GlobalDuplicate      bool   // share one duplicate filter across recursion instead of one per directory
 
 	// Status filtering
-	IncludeStatus []int
-	ExcludeStatus []int
-	ExcludeSize   []int
+	IncludeStatus       []int
+	ExcludeStatus       []int
+	ExcludeSize         []int
+	ExcludeLengthRanges []string // inclusive size ranges, e.g. "1200-1260"
+	MinSize             int64    // hide non-redirect responses smaller than this (0 = off)
+
+	// Word/line count filtering
+	MatchWords   []int
+	ExcludeWords []int
+	MatchLines   []int
+	ExcludeLines []int
 
 	// Body filtering
-	MatchBody   string // only show responses containing this string
-	ExcludeBody string // hide responses containing this string
+	MatchBody        string // only show responses containing this string
+	ExcludeBody      string // hide responses containing this string
+	FilterLoginPages bool   // hide pages showing a login form (a form with a password input)
+
+	// Content-type filtering ("text/" matches every text subtype)
+	MatchContentType   []string // only show responses with these content types
+	ExcludeContentType []string // hide responses with these content types
+
+	// Redirect filtering
+	ExcludeRedirectTo string // hide redirects whose Location contains this ("re:" prefix = regex)
+
+	// Expression filtering
+	FilterExpr string // only show results matching this expression (see filter.ExprFilter)
 
 	// Output
-	OutputFile   string
-	OutputFormat string // "text", "json", "csv"
-	Silent       bool
-	NoColor      bool
-	FullURL      bool // show full URL instead of path only
+	OutputFile     string
+	OutputDir      string // write one file per target here, named after its host and port
+	OutputFormat   string // "text", "json", "csv", "html", "md"
+	OutputAppend   bool   // append to OutputFile instead of truncating (JSON becomes JSON Lines)
+	HARFile        string // record every request/response to this HAR file
+	BaselineFile   string // previous JSON results to diff this run against
+	SummaryFile    string // write a JSON summary of each target to this file
+	SaveBodies     string // directory to save the body of every reported result to
+	MaxBodySize    int64  // bytes read per response body (0 = no cap)
+	Silent         bool
+	Verbose        bool          // log every response, including filtered ones and why, to stderr
+	NoProgress     bool          // hide the progress bar but keep results and the summary
+	Heartbeat      time.Duration // with the bar hidden, print a status line at this interval (0 = off)
+	NoColor        bool
+	ColorScheme    string        // per-status color overrides, e.g. "200:green,403:red"
+	FullURL        bool          // show full URL instead of path only
+	ShowHeaders    []string      // response headers to capture and display with each result
+	SlowThreshold  time.Duration // flag results slower than this and list them in the footer (0 = off)
+	Fields         []string      // CSV/JSON fields and their order (see output.OutputFields; nil = all)
+	ExtractTitle   bool          // show the HTML <title> of each result
+	DetectListings bool          // flag open directory listings (autoindex pages) and list them in the footer
+	TruncateURL    int           // elide terminal paths/URLs beyond this width (0 = fit terminal, -1 = never)
 
 	// Recursion
-	Recursive bool
-	MaxDepth  int
+	Recursive         bool
+	MaxDepth          int
+	RecursionWordlist string   // separate wordlist for recursive passes (empty = main wordlist)
+	RecursionStatus   []int    // status codes eligible for recursion (empty = any)
+	NoRecurse         []string // directory names never recursed into, on top of the static asset defaults
+	NoRecurseReset    bool     // drop the static asset defaults, keeping only NoRecurse
+	RecurseOnly       []string // when set, only recurse into directories with these names (and their subdirectories)
 
 	// Resume
-	ResumeFile string // path to save/load scan state
+	ResumeFile     string        // path to save/load scan state
+	ResumeInterval time.Duration // autosave period for the resume file (0 = only on exit/between phases)
+	SkipFile       string        // ffuf JSON, gobuster, or plain list of paths already found, skipped this run
 
 	// HTTP
 	RequestFile     string // path to raw HTTP request file (e.g. Burp export)
 	Headers         map[string]string
 	UserAgent       string
+	HostHeader      string // Host header for every request (vhost items override it)
+	SNI             string // TLS server name override (default: URL host, or HostHeader for IP targets)
+	TLSVerify       bool   // verify server certificates (default: skip verification)
+	CACert          string // PEM bundle of trusted CAs; implies TLSVerify
+	ClientCert      string // PEM client certificate for mutual TLS
+	ClientKey       string // PEM private key for ClientCert
+	HTTP1           bool   // advertise only http/1.1 via ALPN
+	HTTP2           bool   // negotiate HTTP/2 with TLS targets (default: HTTP/1.1 only)
 	Proxy           string
+	ProxyFile       string // file of proxy URLs, rotated round-robin per request
+	ReplayProxy     string // re-send every reported result through this proxy
 	FollowRedirects bool
+	TraceRedirects  int    // follow up to N redirects manually, keeping the chain (0 = off)
+	BasicAuth       string // user:pass for HTTP Basic auth
+	BearerToken     string // token for Bearer auth
+	CookieJar       bool   // keep cookies set by the target and send them back
+	SpoofIP         string // "random" or "sequential" X-Forwarded-For style headers ("" = off)
 
 	// Network
-	CIDRTargets string // CIDR range (e.g. 192.168.1.0/24)
-	Ports       string // comma-separated ports to scan
+	CIDRTargets  string   // CIDR range (e.g. 192.168.1.0/24)
+	Ports        string   // comma-separated ports to scan
+	MaxHosts     int      // refuse CIDR ranges with more addresses than this (0 = no limit)
+	ExcludeHosts []string // IPs or CIDR sub-ranges dropped from CIDR targets
+	Resolvers    []string // custom DNS servers (host[:port]), used round-robin
+
+	// TargetConcurrency is the number of targets scanned in parallel
+	// (-l / --cidr). Each target still uses Threads workers.
+	TargetConcurrency int
 
 	// Method fuzzing
-	Methods []string // HTTP methods to try per path (default: GET only)
+	Methods      []string // HTTP methods to try per path (default: GET only)
+	ProbeMethods bool     // send OPTIONS for each result and record its Allow header
+	ProbeRange   bool     // re-request each result with a Range header and flag 206 answers
+	DetectWS     bool     // send WebSocket upgrade headers and flag 101 responses
 
 	// Virtual host fuzzing
-	VHost         bool   // enable vhost fuzzing mode
-	VHostWordlist string // path to hostname wordlist
+	VHost             bool   // enable vhost fuzzing mode
+	VHostWordlist     string // path to hostname wordlist
+	IgnoreWildcardDNS bool   // --vhost: hide repeated responses when every host name gets the same page
 
 	// Crawl
 	Crawl      bool // crawl discovered pages for additional paths
 	CrawlDepth int  // maximum link-following hops
+	SeedRobots bool // seed paths from robots.txt and sitemap.xml
+
+	// Regexes matched against crawled paths ("/account/logout"): only
+	// paths matching an include (if any) and no exclude are scanned.
+	ScopeInclude []string
+	ScopeExclude []string
+
+	// FaviconHash prints the Shodan-style hash of each target's
+	// /favicon.ico for fingerprinting.
+	FaviconHash bool
+
+	// OpenAPISpec is a Swagger/OpenAPI JSON file or URL whose paths and
+	// methods are added to the scan.
+	OpenAPISpec string
+
+	// Bypass403 retries every 403 result with path mutations (trailing
+	// dots, encoded segments, case changes) and reports variants that get
+	// through.
+	Bypass403 bool
 
 	// Hooks
 	OnResultCmd string // command to run for each result (receives JSON on stdin)
 
 	// Skip
-	MaxETA time.Duration // skip target if ETA exceeds this duration (0 = disabled)
+	MaxETA        time.Duration // skip target if ETA exceeds this duration (0 = disabled)
+	StopOnFirst   bool          // stop the target's scan after the first unfiltered result
+	AbortOnErrors int           // skip the target after this many consecutive errors (0 = never)
+	MaxResults    int           // stop the target's scan after this many results (0 = unlimited)
+	TimeLimit     time.Duration // stop the whole scan after this long (0 = no limit)
+
+	// Dedup
+	UniqueBy []string // show only the first result per combination of these fields (see output.UniqueKeys)
 
 	// Sort
-	SortBy string // sort results by: status, path, size (empty = no sorting)
+	SortBy  string // sort results by: status, path, size (empty = no sorting)
+	Ordered bool   // stream results in wordlist order instead of completion order
 
 	// Tree
 	Tree bool // print directory tree summary after scan
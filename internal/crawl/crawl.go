@@ -6,14 +6,47 @@ import (
 	"strings"
 )
 
+// linkPatterns capture URL attributes on any element, which covers <a>,
+// <link>, <script>, <img>, <iframe>, and <form>.
 var linkPatterns = []*regexp.Regexp{
 	regexp.MustCompile(`(?i)href\s*=\s*["']([^"']+)["']`),
 	regexp.MustCompile(`(?i)src\s*=\s*["']([^"']+)["']`),
 	regexp.MustCompile(`(?i)action\s*=\s*["']([^"']+)["']`),
 }
 
+// metaTagPattern matches whole <meta> elements; metaRefreshPattern and
+// metaContentPattern then pick out <meta http-equiv="refresh"> and its
+// content attribute, whatever the attribute order.
+var (
+	metaTagPattern     = regexp.MustCompile(`(?is)<meta\b[^>]*>`)
+	metaRefreshPattern = regexp.MustCompile(`(?i)http-equiv\s*=\s*["']?refresh\b`)
+	metaContentPattern = regexp.MustCompile(`(?i)content\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+)
+
+// refreshURLPattern extracts the target from a refresh content value such
+// as "0;url=/next" or "5; URL='/next'".
+var refreshURLPattern = regexp.MustCompile(`(?i)^\s*[\d.]*\s*[;,]\s*url\s*=\s*['"]?([^'"]+)`)
+
+// scriptBlockPattern captures the contents of inline <script> elements.
+var scriptBlockPattern = regexp.MustCompile(`(?is)<script[^>]*>(.*?)</script>`)
+
+// jsPathPattern matches quoted string literals in JavaScript that look like
+// paths: either starting with a slash (e.g. fetch("/api/v2/users")) or
+// ending in a well-known web file extension (e.g. "static/app.json").
+// Strings containing whitespace or regex/template syntax never match.
+var jsPathPattern = regexp.MustCompile(
+	"[\"'`]" +
+		`(/[A-Za-z0-9_\-./~%?=&:+@,;]*[A-Za-z0-9_\-/~%]` +
+		`|[A-Za-z0-9_\-./~%]+\.(?:php|aspx?|jsp|json|js|html?|xml|txt|cgi|pl|do|action))` +
+		"[\"'`]")
+
+// jsHints are tokens whose presence suggests a non-HTML body is JavaScript.
+var jsHints = []string{"function", "=>", "var ", "const ", "let ", "fetch(", "import ", "export "}
+
 // ExtractPaths parses HTML body and returns de-duplicated same-origin paths
-// found in href, src, and action attributes.
+// found in href, src, and action attributes, plus path-like string literals
+// inside inline <script> blocks. If the body itself looks like JavaScript
+// (e.g. a linked .js file), its string literals are scanned as well.
 func ExtractPaths(body []byte, baseURL string) []string {
 	base, err := url.Parse(baseURL)
 	if err != nil {
@@ -23,6 +56,17 @@ func ExtractPaths(body []byte, baseURL string) []string {
 	seen := make(map[string]struct{})
 	var paths []string
 
+	add := func(raw string) {
+		path, ok := resolvePath(base, raw)
+		if !ok {
+			return
+		}
+		if _, ok := seen[path]; !ok {
+			seen[path] = struct{}{}
+			paths = append(paths, path)
+		}
+	}
+
 	content := string(body)
 	for _, re := range linkPatterns {
 		matches := re.FindAllStringSubmatch(content, -1)
@@ -30,43 +74,91 @@ func ExtractPaths(body []byte, baseURL string) []string {
 			if len(m) < 2 {
 				continue
 			}
-			raw := strings.TrimSpace(m[1])
-
-			// Skip non-HTTP URIs and anchors.
-			lower := strings.ToLower(raw)
-			if strings.HasPrefix(lower, "javascript:") ||
-				strings.HasPrefix(lower, "mailto:") ||
-				strings.HasPrefix(lower, "data:") ||
-				strings.HasPrefix(raw, "#") {
-				continue
-			}
+			add(m[1])
+		}
+	}
+	for _, tag := range metaTagPattern.FindAllString(content, -1) {
+		if target, ok := metaRefreshTarget(tag); ok {
+			add(target)
+		}
+	}
 
-			ref, err := url.Parse(raw)
-			if err != nil {
-				continue
-			}
-			resolved := base.ResolveReference(ref)
+	if looksLikeJS(content) {
+		extractJSPaths(content, add)
+	} else {
+		for _, m := range scriptBlockPattern.FindAllStringSubmatch(content, -1) {
+			extractJSPaths(m[1], add)
+		}
+	}
 
-			// Same-origin check.
-			if resolved.Host != "" && resolved.Host != base.Host {
-				continue
-			}
+	return paths
+}
 
-			path := strings.TrimRight(resolved.Path, "/")
-			if path == "" {
-				continue
-			}
-			path = strings.TrimPrefix(path, "/")
-			if path == "" {
-				continue
-			}
+// resolvePath resolves a raw link against base and returns the same-origin
+// path without leading or trailing slashes. ok is false for non-HTTP URIs,
+// anchors, cross-origin links, and the site root.
+func resolvePath(base *url.URL, raw string) (path string, ok bool) {
+	raw = strings.TrimSpace(raw)
 
-			if _, ok := seen[path]; !ok {
-				seen[path] = struct{}{}
-				paths = append(paths, path)
-			}
-		}
+	// Skip non-HTTP URIs and anchors.
+	lower := strings.ToLower(raw)
+	if strings.HasPrefix(lower, "javascript:") ||
+		strings.HasPrefix(lower, "mailto:") ||
+		strings.HasPrefix(lower, "data:") ||
+		strings.HasPrefix(raw, "#") {
+		return "", false
 	}
 
-	return paths
+	ref, err := url.Parse(raw)
+	if err != nil {
+		return "", false
+	}
+	resolved := base.ResolveReference(ref)
+
+	// Same-origin check.
+	if resolved.Host != "" && resolved.Host != base.Host {
+		return "", false
+	}
+
+	path = strings.Trim(resolved.Path, "/")
+	return path, path != ""
+}
+
+// metaRefreshTarget returns the redirect URL of a <meta http-equiv="refresh">
+// tag. ok is false for other meta tags and refreshes without a URL.
+func metaRefreshTarget(tag string) (target string, ok bool) {
+	if !metaRefreshPattern.MatchString(tag) {
+		return "", false
+	}
+	m := metaContentPattern.FindStringSubmatch(tag)
+	if m == nil {
+		return "", false
+	}
+	u := refreshURLPattern.FindStringSubmatch(m[1] + m[2])
+	if u == nil {
+		return "", false
+	}
+	return strings.TrimSpace(u[1]), true
+}
+
+// extractJSPaths calls add for every path-like string literal in script.
+func extractJSPaths(script string, add func(string)) {
+	for _, m := range jsPathPattern.FindAllStringSubmatch(script, -1) {
+		add(m[1])
+	}
+}
+
+// looksLikeJS reports whether a body appears to be JavaScript rather than
+// HTML or another document type.
+func looksLikeJS(content string) bool {
+	trimmed := strings.TrimSpace(content)
+	if trimmed == "" || strings.HasPrefix(trimmed, "<") {
+		return false
+	}
+	for _, hint := range jsHints {
+		if strings.Contains(trimmed, hint) {
+			return true
+		}
+	}
+	return false
 }
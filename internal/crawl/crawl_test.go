@@ -59,3 +59,98 @@ func TestExtractPaths_FormAction(t *testing.T) {
 		t.Errorf("expected [submit], got %v", paths)
 	}
 }
+
+func TestExtractPaths_InlineScriptFetch(t *testing.T) {
+	body := []byte(`<html><script>
+fetch("/api/v2/users").then(r => r.json());
+axios.get('/api/orders?page=1');
+const health = ` + "`/internal/health`" + `;
+</script></html>`)
+	paths := ExtractPaths(body, "http://example.com")
+	sort.Strings(paths)
+	expected := []string{"api/orders", "api/v2/users", "internal/health"}
+	if len(paths) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, paths)
+	}
+	for i, p := range paths {
+		if p != expected[i] {
+			t.Errorf("path[%d] = %q, want %q", i, p, expected[i])
+		}
+	}
+}
+
+func TestExtractPaths_ScriptStringWithExtension(t *testing.T) {
+	body := []byte(`<script>var cfg = "static/config.json"; loadScript('vendor/app.js');</script>`)
+	paths := ExtractPaths(body, "http://example.com")
+	sort.Strings(paths)
+	if len(paths) != 2 || paths[0] != "static/config.json" || paths[1] != "vendor/app.js" {
+		t.Errorf("expected [static/config.json vendor/app.js], got %v", paths)
+	}
+}
+
+func TestExtractPaths_ScriptFalsePositives(t *testing.T) {
+	body := []byte(`<script>
+var msg = "hello world";
+var mime = "text/html";
+var root = "/";
+var re = "/\\d+/";
+var date = "12/05/2020";
+var ext = "//cdn.other.com/lib.js";
+</script>`)
+	paths := ExtractPaths(body, "http://example.com")
+	if len(paths) != 0 {
+		t.Errorf("expected no paths from non-path strings, got %v", paths)
+	}
+}
+
+func TestExtractPaths_StandaloneJavaScript(t *testing.T) {
+	body := []byte(`(function () {
+  const routes = { users: "/api/users", login: "/auth/login" };
+  fetch(routes.users);
+})();`)
+	paths := ExtractPaths(body, "http://example.com")
+	sort.Strings(paths)
+	if len(paths) != 2 || paths[0] != "api/users" || paths[1] != "auth/login" {
+		t.Errorf("expected [api/users auth/login], got %v", paths)
+	}
+}
+
+func TestExtractPaths_ScriptDedupWithAttributes(t *testing.T) {
+	body := []byte(`<a href="/api/users">Users</a><script>fetch("/api/users")</script>`)
+	paths := ExtractPaths(body, "http://example.com")
+	if len(paths) != 1 || paths[0] != "api/users" {
+		t.Errorf("expected [api/users], got %v", paths)
+	}
+}
+
+func TestExtractPaths_LinkAndIframe(t *testing.T) {
+	body := []byte(`<link rel="stylesheet" href="/css/site.css"><iframe src="/embed/widget"></iframe>`)
+	paths := ExtractPaths(body, "http://example.com")
+	sort.Strings(paths)
+	if len(paths) != 2 || paths[0] != "css/site.css" || paths[1] != "embed/widget" {
+		t.Errorf("expected [css/site.css embed/widget], got %v", paths)
+	}
+}
+
+func TestExtractPaths_MetaRefresh(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{"basic", `<meta http-equiv="refresh" content="0;url=/dashboard">`, []string{"dashboard"}},
+		{"content first", `<meta content="5; URL='/login?next=1'" http-equiv="Refresh">`, []string{"login"}},
+		{"relative", `<meta http-equiv=refresh content="0; url=next.html">`, []string{"app/next.html"}},
+		{"cross origin", `<meta http-equiv="refresh" content="0;url=https://other.com/x">`, nil},
+		{"no url", `<meta http-equiv="refresh" content="30">`, nil},
+		{"other meta", `<meta name="description" content="0;url=/nope">`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExtractPaths([]byte(tt.body), "http://example.com/app/")
+			if len(got) != len(tt.want) || (len(got) > 0 && got[0] != tt.want[0]) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
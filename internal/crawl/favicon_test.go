@@ -0,0 +1,34 @@
+package crawl
+
+import "testing"
+
+func TestMurmur3(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int32
+	}{
+		{"", 0},
+		{"hello", 613153351},
+		{"The quick brown fox jumps over the lazy dog", 776992547},
+	}
+	for _, tt := range tests {
+		if got := int32(murmur3([]byte(tt.in), 0)); got != tt.want {
+			t.Errorf("murmur3(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFaviconHash(t *testing.T) {
+	// 512 bytes encode to several 76-character base64 lines, so this also
+	// covers the line wrapping Shodan hashes.
+	icon := make([]byte, 512)
+	for i := range icon {
+		icon[i] = byte(i)
+	}
+	if got, want := FaviconHash(icon), int32(-1173581353); got != want {
+		t.Errorf("FaviconHash = %d, want %d", got, want)
+	}
+	if got, want := FaviconHash([]byte{0, 0, 1, 0}), int32(-216455174); got != want {
+		t.Errorf("FaviconHash(short) = %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,76 @@
+package crawl
+
+import (
+	"bufio"
+	"bytes"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var sitemapLocPattern = regexp.MustCompile(`(?i)<loc>\s*([^<]+?)\s*</loc>`)
+
+// ParseRobots returns de-duplicated same-origin paths listed in Allow and
+// Disallow rules of a robots.txt body. Wildcard rules are truncated at the
+// first '*' or '$' so "/admin/*" seeds "admin".
+func ParseRobots(body []byte, baseURL string) []string {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil
+	}
+
+	seen := make(map[string]struct{})
+	var paths []string
+
+	sc := bufio.NewScanner(bytes.NewReader(body))
+	for sc.Scan() {
+		line := sc.Text()
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		if key != "allow" && key != "disallow" {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if idx := strings.IndexAny(value, "*$"); idx >= 0 {
+			value = value[:idx]
+		}
+		path, ok := resolvePath(base, value)
+		if !ok {
+			continue
+		}
+		if _, ok := seen[path]; !ok {
+			seen[path] = struct{}{}
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// ParseSitemap returns de-duplicated same-origin paths from the <loc>
+// entries of a sitemap.xml body.
+func ParseSitemap(body []byte, baseURL string) []string {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil
+	}
+
+	seen := make(map[string]struct{})
+	var paths []string
+	for _, m := range sitemapLocPattern.FindAllSubmatch(body, -1) {
+		path, ok := resolvePath(base, string(m[1]))
+		if !ok {
+			continue
+		}
+		if _, ok := seen[path]; !ok {
+			seen[path] = struct{}{}
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
@@ -0,0 +1,44 @@
+package crawl
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestParseRobots(t *testing.T) {
+	body := []byte(`User-agent: *
+Disallow: /admin/
+Disallow: /private/*.php$
+Allow: /public # comment
+Disallow: /
+Disallow:
+Sitemap: https://example.com/sitemap.xml
+Disallow: https://other.com/elsewhere
+`)
+	paths := ParseRobots(body, "https://example.com")
+	sort.Strings(paths)
+	expected := []string{"admin", "private", "public"}
+	if len(paths) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, paths)
+	}
+	for i, p := range paths {
+		if p != expected[i] {
+			t.Errorf("path[%d] = %q, want %q", i, p, expected[i])
+		}
+	}
+}
+
+func TestParseSitemap(t *testing.T) {
+	body := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>https://example.com/blog/post-1</loc></url>
+  <url><loc> https://example.com/about </loc></url>
+  <url><loc>https://example.com/about</loc></url>
+  <url><loc>https://other.com/offsite</loc></url>
+</urlset>`)
+	paths := ParseSitemap(body, "https://example.com")
+	sort.Strings(paths)
+	if len(paths) != 2 || paths[0] != "about" || paths[1] != "blog/post-1" {
+		t.Errorf("expected [about blog/post-1], got %v", paths)
+	}
+}
@@ -0,0 +1,81 @@
+package crawl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Scope limits which crawled paths get scanned (--scope-include and
+// --scope-exclude). Patterns are matched against the path with a leading
+// slash, e.g. "/account/logout". A nil Scope allows everything.
+type Scope struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+// NewScope compiles the include and exclude patterns. It returns nil if
+// both are empty.
+func NewScope(include, exclude []string) (*Scope, error) {
+	if len(include) == 0 && len(exclude) == 0 {
+		return nil, nil
+	}
+	s := &Scope{}
+	var err error
+	if s.include, err = compileAll(include); err != nil {
+		return nil, err
+	}
+	if s.exclude, err = compileAll(exclude); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func compileAll(patterns []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+// Allows reports whether path is in scope: it matches an include pattern
+// (if any are set) and no exclude pattern. Exclusions win.
+func (s *Scope) Allows(path string) bool {
+	if s == nil {
+		return true
+	}
+	path = "/" + strings.TrimLeft(path, "/")
+	for _, re := range s.exclude {
+		if re.MatchString(path) {
+			return false
+		}
+	}
+	if len(s.include) == 0 {
+		return true
+	}
+	for _, re := range s.include {
+		if re.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns the paths s allows.
+func (s *Scope) Filter(paths []string) []string {
+	if s == nil {
+		return paths
+	}
+	var kept []string
+	for _, p := range paths {
+		if s.Allows(p) {
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,57 @@
+package crawl
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScope(t *testing.T) {
+	s, err := NewScope([]string{"^/app/", `\.js$`}, []string{"logout"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]bool{
+		"app/users":      true,
+		"/app/settings":  true,
+		"static/main.js": true,
+		"app/logout":     false,
+		"account/logout": false,
+		"blog/post":      false,
+		"app/logout.js":  false,
+	}
+	for path, want := range tests {
+		if got := s.Allows(path); got != want {
+			t.Errorf("Allows(%q) = %v, want %v", path, got, want)
+		}
+	}
+	got := s.Filter([]string{"app/a", "logout", "other"})
+	if strings.Join(got, ",") != "app/a" {
+		t.Errorf("Filter = %v, want [app/a]", got)
+	}
+}
+
+func TestScope_ExcludeOnly(t *testing.T) {
+	s, err := NewScope(nil, []string{"^/logout$"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Allows("anything") || s.Allows("logout") {
+		t.Error("exclude-only scope should allow everything but /logout")
+	}
+}
+
+func TestScope_Nil(t *testing.T) {
+	s, err := NewScope(nil, nil)
+	if err != nil || s != nil {
+		t.Fatalf("NewScope(nil, nil) = %v, %v; want nil, nil", s, err)
+	}
+	if !s.Allows("logout") || len(s.Filter([]string{"a", "b"})) != 2 {
+		t.Error("nil scope should allow everything")
+	}
+}
+
+func TestScope_InvalidPattern(t *testing.T) {
+	if _, err := NewScope([]string{"("}, nil); err == nil {
+		t.Error("expected error for invalid regex")
+	}
+}
@@ -0,0 +1,27 @@
+package crawl
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var titlePattern = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+
+// maxTitleLen caps extracted titles so a huge <title> can't flood output.
+const maxTitleLen = 200
+
+// ExtractTitle returns the text of the first <title> element in an HTML
+// body, with entities decoded and whitespace collapsed. It returns "" if
+// the body has no (or an empty) title.
+func ExtractTitle(body []byte) string {
+	m := titlePattern.FindSubmatch(body)
+	if m == nil {
+		return ""
+	}
+	title := strings.Join(strings.Fields(html.UnescapeString(string(m[1]))), " ")
+	if runes := []rune(title); len(runes) > maxTitleLen {
+		title = string(runes[:maxTitleLen])
+	}
+	return title
+}
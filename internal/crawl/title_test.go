@@ -0,0 +1,37 @@
+package crawl
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExtractTitle(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"simple", `<html><head><title>Admin Login</title></head></html>`, "Admin Login"},
+		{"multi-line", "<TITLE>\n  Welcome to\n\tnginx!\n</TITLE>", "Welcome to nginx!"},
+		{"attributes", `<title data-x="1">Dashboard</title>`, "Dashboard"},
+		{"entities", `<title>Tom &amp; Jerry&#39;s</title>`, "Tom & Jerry's"},
+		{"first wins", `<title>One</title><title>Two</title>`, "One"},
+		{"absent", `<html><body>no title</body></html>`, ""},
+		{"empty", `<title>   </title>`, ""},
+		{"unclosed", `<title>Broken`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractTitle([]byte(tt.body)); got != tt.want {
+				t.Errorf("ExtractTitle() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractTitle_Capped(t *testing.T) {
+	body := "<title>" + strings.Repeat("x", 1000) + "</title>"
+	if got := ExtractTitle([]byte(body)); len(got) != maxTitleLen {
+		t.Errorf("title length = %d, want %d", len(got), maxTitleLen)
+	}
+}
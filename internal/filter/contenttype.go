@@ -0,0 +1,69 @@
+package filter
+
+import (
+	"strings"
+
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+// ContentTypeFilter includes or excludes results by the media type of their
+// Content-Type header, ignoring parameters such as charset. A pattern
+// ending in "/" matches every subtype ("text/" matches "text/html");
+// other patterns must match the media type exactly. Both lists may be
+// set: a result must match an include pattern and no exclude pattern.
+type ContentTypeFilter struct {
+	include []string
+	exclude []string
+}
+
+// NewContentTypeFilter creates a content type filter. If include is
+// non-empty, only results matching one of its patterns pass; results
+// matching an exclude pattern are filtered.
+func NewContentTypeFilter(include, exclude []string) *ContentTypeFilter {
+	return &ContentTypeFilter{include: normalizeTypes(include), exclude: normalizeTypes(exclude)}
+}
+
+func (f *ContentTypeFilter) Name() string { return "content-type" }
+
+func (f *ContentTypeFilter) ShouldFilter(result *scanner.ScanResult) bool {
+	mediaType := normalizeType(result.ContentType)
+	if len(f.include) > 0 && !matchesType(mediaType, f.include) {
+		return true
+	}
+	return matchesType(mediaType, f.exclude)
+}
+
+// normalizeType returns the lower-cased media type of a Content-Type
+// value, without parameters.
+func normalizeType(contentType string) string {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
+
+func normalizeTypes(patterns []string) []string {
+	out := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		if p = normalizeType(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// matchesType reports whether mediaType matches any of patterns. Results
+// without a Content-Type match nothing.
+func matchesType(mediaType string, patterns []string) bool {
+	if mediaType == "" {
+		return false
+	}
+	for _, p := range patterns {
+		if strings.HasSuffix(p, "/") {
+			if strings.HasPrefix(mediaType, p) {
+				return true
+			}
+		} else if mediaType == p {
+			return true
+		}
+	}
+	return false
+}
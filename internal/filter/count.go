@@ -0,0 +1,64 @@
+package filter
+
+import "github.com/maxvaer/dirfuzz/internal/scanner"
+
+// countFilter includes or excludes results based on an integer metric of
+// the response. Word and line counts are always populated by the
+// requester, so these filters don't require the body to be retained.
+type countFilter struct {
+	name    string
+	metric  func(*scanner.ScanResult) int
+	include map[int]struct{}
+	exclude map[int]struct{}
+}
+
+func newCountFilter(name string, metric func(*scanner.ScanResult) int, include, exclude []int) *countFilter {
+	f := &countFilter{
+		name:    name,
+		metric:  metric,
+		include: make(map[int]struct{}, len(include)),
+		exclude: make(map[int]struct{}, len(exclude)),
+	}
+	for _, n := range include {
+		f.include[n] = struct{}{}
+	}
+	for _, n := range exclude {
+		f.exclude[n] = struct{}{}
+	}
+	return f
+}
+
+func (f *countFilter) Name() string { return f.name }
+
+func (f *countFilter) ShouldFilter(result *scanner.ScanResult) bool {
+	n := f.metric(result)
+	if len(f.include) > 0 {
+		_, ok := f.include[n]
+		return !ok // filter if NOT in include list
+	}
+	if len(f.exclude) > 0 {
+		_, ok := f.exclude[n]
+		return ok // filter if in exclude list
+	}
+	return false
+}
+
+// WordCountFilter includes or excludes results by response word count.
+type WordCountFilter struct{ *countFilter }
+
+// NewWordCountFilter creates a word count filter. If include is non-empty,
+// only results with those word counts pass. If exclude is non-empty, those
+// word counts are filtered.
+func NewWordCountFilter(include, exclude []int) *WordCountFilter {
+	return &WordCountFilter{newCountFilter("words", func(r *scanner.ScanResult) int { return r.WordCount }, include, exclude)}
+}
+
+// LineCountFilter includes or excludes results by response line count.
+type LineCountFilter struct{ *countFilter }
+
+// NewLineCountFilter creates a line count filter. If include is non-empty,
+// only results with those line counts pass. If exclude is non-empty, those
+// line counts are filtered.
+func NewLineCountFilter(include, exclude []int) *LineCountFilter {
+	return &LineCountFilter{newCountFilter("lines", func(r *scanner.ScanResult) int { return r.LineCount }, include, exclude)}
+}
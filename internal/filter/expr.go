@@ -0,0 +1,309 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+// ExprFilter shows only results matching a boolean expression such as
+//
+//	status==200 || (status==301 && redirect~=login)
+//
+// Fields are status, size, words, lines (numbers) and redirect (string).
+// Numeric fields support ==, !=, <, <=, >, >=; redirect supports ==, !=
+// and ~= (regex match). Terms combine with &&, ||, ! and parentheses;
+// && binds tighter than ||. String values may be bare or quoted.
+type ExprFilter struct {
+	root exprNode
+}
+
+// NewExprFilter parses expr. Syntax errors include the offending position.
+func NewExprFilter(expr string) (*ExprFilter, error) {
+	p := &exprParser{src: expr}
+	root, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	return &ExprFilter{root: root}, nil
+}
+
+func (f *ExprFilter) Name() string { return "expr" }
+
+// ShouldFilter hides results for which the expression is false.
+func (f *ExprFilter) ShouldFilter(result *scanner.ScanResult) bool {
+	return !f.root.eval(result)
+}
+
+type exprNode interface {
+	eval(r *scanner.ScanResult) bool
+}
+
+type andNode struct{ left, right exprNode }
+
+func (n andNode) eval(r *scanner.ScanResult) bool { return n.left.eval(r) && n.right.eval(r) }
+
+type orNode struct{ left, right exprNode }
+
+func (n orNode) eval(r *scanner.ScanResult) bool { return n.left.eval(r) || n.right.eval(r) }
+
+type notNode struct{ inner exprNode }
+
+func (n notNode) eval(r *scanner.ScanResult) bool { return !n.inner.eval(r) }
+
+type numCompare struct {
+	field func(*scanner.ScanResult) int64
+	op    string
+	value int64
+}
+
+func (n numCompare) eval(r *scanner.ScanResult) bool {
+	v := n.field(r)
+	switch n.op {
+	case "==":
+		return v == n.value
+	case "!=":
+		return v != n.value
+	case "<":
+		return v < n.value
+	case "<=":
+		return v <= n.value
+	case ">":
+		return v > n.value
+	case ">=":
+		return v >= n.value
+	}
+	return false
+}
+
+type strCompare struct {
+	field func(*scanner.ScanResult) string
+	op    string
+	value string
+	re    *regexp.Regexp // set for ~=
+}
+
+func (n strCompare) eval(r *scanner.ScanResult) bool {
+	v := n.field(r)
+	switch n.op {
+	case "==":
+		return v == n.value
+	case "!=":
+		return v != n.value
+	case "~=":
+		return n.re.MatchString(v)
+	}
+	return false
+}
+
+var numericFields = map[string]func(*scanner.ScanResult) int64{
+	"status": func(r *scanner.ScanResult) int64 { return int64(r.StatusCode) },
+	"size":   func(r *scanner.ScanResult) int64 { return r.ContentLength },
+	"words":  func(r *scanner.ScanResult) int64 { return int64(r.WordCount) },
+	"lines":  func(r *scanner.ScanResult) int64 { return int64(r.LineCount) },
+}
+
+var stringFields = map[string]func(*scanner.ScanResult) string{
+	"redirect": func(r *scanner.ScanResult) string { return r.RedirectURL },
+}
+
+// exprOperators is ordered so two-character operators match first.
+var exprOperators = []string{"==", "!=", "<=", ">=", "~=", "<", ">"}
+
+// exprParser is a recursive-descent parser over the raw source string:
+//
+//	or      = and { "||" and }
+//	and     = unary { "&&" unary }
+//	unary   = "!" unary | "(" or ")" | compare
+//	compare = field op value
+type exprParser struct {
+	src string
+	pos int
+}
+
+func (p *exprParser) parse() (exprNode, error) {
+	if strings.TrimSpace(p.src) == "" {
+		return nil, fmt.Errorf("empty expression")
+	}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.src) {
+		return nil, p.errorf("unexpected %q", p.src[p.pos:])
+	}
+	return node, nil
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.consume("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.consume("&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	p.skipSpace()
+	if p.pos < len(p.src) && p.src[p.pos] == '!' && !strings.HasPrefix(p.src[p.pos:], "!=") {
+		p.pos++
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{inner}, nil
+	}
+	if p.consume("(") {
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume(")") {
+			return nil, p.errorf("expected )")
+		}
+		return node, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *exprParser) parseCompare() (exprNode, error) {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.src) && isIdentByte(p.src[p.pos]) {
+		p.pos++
+	}
+	name := strings.ToLower(p.src[start:p.pos])
+	if name == "" {
+		p.pos = start
+		return nil, p.errorf("expected field name")
+	}
+
+	p.skipSpace()
+	opPos := p.pos
+	op := ""
+	for _, candidate := range exprOperators {
+		if strings.HasPrefix(p.src[p.pos:], candidate) {
+			op = candidate
+			p.pos += len(op)
+			break
+		}
+	}
+	if op == "" {
+		return nil, p.errorf("expected comparison operator after %q", name)
+	}
+
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+
+	if field, ok := numericFields[name]; ok {
+		if op == "~=" {
+			p.pos = opPos
+			return nil, p.errorf("~= is only supported for redirect")
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, p.errorf("%s expects a number, got %q", name, value)
+		}
+		return numCompare{field: field, op: op, value: n}, nil
+	}
+	if field, ok := stringFields[name]; ok {
+		node := strCompare{field: field, op: op, value: value}
+		switch op {
+		case "==", "!=":
+		case "~=":
+			re, err := regexp.Compile(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regex %q: %w", value, err)
+			}
+			node.re = re
+		default:
+			p.pos = opPos
+			return nil, p.errorf("operator %s is not supported for %s", op, name)
+		}
+		return node, nil
+	}
+	p.pos = start
+	return nil, p.errorf("unknown field %q (want status, size, words, lines, or redirect)", name)
+}
+
+// parseValue reads a quoted string or a bare token ending at whitespace,
+// a parenthesis, or a boolean operator.
+func (p *exprParser) parseValue() (string, error) {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return "", p.errorf("expected value")
+	}
+	if q := p.src[p.pos]; q == '"' || q == '\'' {
+		end := strings.IndexByte(p.src[p.pos+1:], q)
+		if end < 0 {
+			return "", p.errorf("unterminated string")
+		}
+		value := p.src[p.pos+1 : p.pos+1+end]
+		p.pos += end + 2
+		return value, nil
+	}
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if c == ' ' || c == '\t' || c == '(' || c == ')' ||
+			strings.HasPrefix(p.src[p.pos:], "&&") || strings.HasPrefix(p.src[p.pos:], "||") {
+			break
+		}
+		p.pos++
+	}
+	if p.pos == start {
+		return "", p.errorf("expected value")
+	}
+	return p.src[start:p.pos], nil
+}
+
+func (p *exprParser) consume(tok string) bool {
+	p.skipSpace()
+	if strings.HasPrefix(p.src[p.pos:], tok) {
+		p.pos += len(tok)
+		return true
+	}
+	return false
+}
+
+func (p *exprParser) skipSpace() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func (p *exprParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("position %d: %s", p.pos+1, fmt.Sprintf(format, args...))
+}
+
+func isIdentByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
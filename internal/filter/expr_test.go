@@ -0,0 +1,81 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+func TestExprFilter_Eval(t *testing.T) {
+	ok200 := &scanner.ScanResult{StatusCode: 200, ContentLength: 512, WordCount: 40, LineCount: 12}
+	toLogin := &scanner.ScanResult{StatusCode: 301, RedirectURL: "https://example.com/login?next=/admin"}
+	toHome := &scanner.ScanResult{StatusCode: 301, RedirectURL: "https://example.com/"}
+	forbidden := &scanner.ScanResult{StatusCode: 403, ContentLength: 0}
+
+	tests := []struct {
+		expr string
+		show map[*scanner.ScanResult]bool
+	}{
+		{"status==200", map[*scanner.ScanResult]bool{ok200: true, toLogin: false, forbidden: false}},
+		{"status != 403", map[*scanner.ScanResult]bool{ok200: true, forbidden: false}},
+		{"size>=512 && size<1024", map[*scanner.ScanResult]bool{ok200: true, forbidden: false}},
+		{"words>10 && lines<=12", map[*scanner.ScanResult]bool{ok200: true, forbidden: false}},
+		{"status==200 || (status==301 && redirect~=login)",
+			map[*scanner.ScanResult]bool{ok200: true, toLogin: true, toHome: false, forbidden: false}},
+		// && binds tighter than ||: parsed as status==403 || (status==301 && redirect~=login).
+		{"status==403 || status==301 && redirect~=login",
+			map[*scanner.ScanResult]bool{forbidden: true, toLogin: true, toHome: false, ok200: false}},
+		// Parentheses override precedence.
+		{"(status==403 || status==301) && redirect~=login",
+			map[*scanner.ScanResult]bool{forbidden: false, toLogin: true, toHome: false}},
+		{"!(status==301)", map[*scanner.ScanResult]bool{ok200: true, toLogin: false}},
+		{`redirect == "https://example.com/"`, map[*scanner.ScanResult]bool{toHome: true, toLogin: false}},
+		{"redirect~='/login\\?next='", map[*scanner.ScanResult]bool{toLogin: true, toHome: false}},
+		{"STATUS==200", map[*scanner.ScanResult]bool{ok200: true}},
+	}
+
+	for _, tt := range tests {
+		f, err := NewExprFilter(tt.expr)
+		if err != nil {
+			t.Errorf("NewExprFilter(%q): %v", tt.expr, err)
+			continue
+		}
+		for r, wantShow := range tt.show {
+			if got := !f.ShouldFilter(r); got != wantShow {
+				t.Errorf("%q on status=%d redirect=%q: show=%v, want %v",
+					tt.expr, r.StatusCode, r.RedirectURL, got, wantShow)
+			}
+		}
+	}
+}
+
+func TestExprFilter_SyntaxErrors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{"", "empty expression"},
+		{"status", "expected comparison operator"},
+		{"status==", "expected value"},
+		{"status==abc", "expects a number"},
+		{"code==200", "unknown field"},
+		{"size~=100", "only supported for redirect"},
+		{"redirect>5", "not supported for redirect"},
+		{"redirect~='('", "invalid regex"},
+		{"(status==200", "expected )"},
+		{"status==200 &&", "expected field name"},
+		{"status==200 status==301", "unexpected"},
+		{`redirect=="login`, "unterminated string"},
+	}
+	for _, tt := range tests {
+		_, err := NewExprFilter(tt.expr)
+		if err == nil {
+			t.Errorf("NewExprFilter(%q): expected error", tt.expr)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("NewExprFilter(%q) error = %q, want it to contain %q", tt.expr, err, tt.wantErr)
+		}
+	}
+}
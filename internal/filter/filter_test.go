@@ -63,3 +63,219 @@ func TestChain_ShortCircuits(t *testing.T) {
 		t.Errorf("expected reason 'status', got %q", reason)
 	}
 }
+
+func TestSizeRangeFilter_Boundaries(t *testing.T) {
+	f, err := NewSizeRangeFilter([]string{"1200-1260"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		size int64
+		want bool
+	}{
+		{1199, false},
+		{1200, true}, // inclusive lower bound
+		{1230, true},
+		{1260, true}, // inclusive upper bound
+		{1261, false},
+	}
+	for _, tt := range tests {
+		r := &scanner.ScanResult{ContentLength: tt.size}
+		if got := f.ShouldFilter(r); got != tt.want {
+			t.Errorf("size %d: ShouldFilter = %v, want %v", tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestSizeRangeFilter_MultipleRanges(t *testing.T) {
+	f, err := NewSizeRangeFilter([]string{"0-10", " 500 - 510 "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, size := range []int64{0, 10, 500, 510} {
+		if !f.ShouldFilter(&scanner.ScanResult{ContentLength: size}) {
+			t.Errorf("size %d should be filtered", size)
+		}
+	}
+	for _, size := range []int64{11, 250, 499, 511} {
+		if f.ShouldFilter(&scanner.ScanResult{ContentLength: size}) {
+			t.Errorf("size %d should pass", size)
+		}
+	}
+}
+
+func TestSizeRangeFilter_Invalid(t *testing.T) {
+	for _, spec := range []string{"1200", "a-b", "10-5", "-5-10"} {
+		if _, err := NewSizeRangeFilter([]string{spec}); err == nil {
+			t.Errorf("expected error for %q", spec)
+		}
+	}
+}
+
+func TestMinSizeFilter_Threshold(t *testing.T) {
+	f := NewMinSizeFilter(20)
+	tests := []struct {
+		size int64
+		want bool
+	}{
+		{0, true},
+		{19, true},
+		{20, false}, // threshold itself passes
+		{21, false},
+	}
+	for _, tt := range tests {
+		r := &scanner.ScanResult{StatusCode: 200, ContentLength: tt.size}
+		if got := f.ShouldFilter(r); got != tt.want {
+			t.Errorf("size %d: ShouldFilter = %v, want %v", tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestMinSizeFilter_IgnoresRedirects(t *testing.T) {
+	f := NewMinSizeFilter(20)
+	if f.ShouldFilter(&scanner.ScanResult{StatusCode: 301, ContentLength: 0}) {
+		t.Error("empty 301 should pass min-size filter")
+	}
+	if !f.ShouldFilter(&scanner.ScanResult{StatusCode: 404, ContentLength: 9}) {
+		t.Error("9-byte 404 should be filtered")
+	}
+}
+
+func TestWordCountFilter(t *testing.T) {
+	match := NewWordCountFilter([]int{12}, nil)
+	if match.ShouldFilter(&scanner.ScanResult{WordCount: 12}) {
+		t.Error("12 words should pass match filter")
+	}
+	if !match.ShouldFilter(&scanner.ScanResult{WordCount: 13}) {
+		t.Error("13 words should be filtered by match filter")
+	}
+
+	exclude := NewWordCountFilter(nil, []int{0, 12})
+	if !exclude.ShouldFilter(&scanner.ScanResult{WordCount: 12}) {
+		t.Error("12 words should be filtered by exclude filter")
+	}
+	if exclude.ShouldFilter(&scanner.ScanResult{WordCount: 40}) {
+		t.Error("40 words should pass exclude filter")
+	}
+	if exclude.Name() != "words" {
+		t.Errorf("Name() = %q, want words", exclude.Name())
+	}
+}
+
+func TestLineCountFilter(t *testing.T) {
+	match := NewLineCountFilter([]int{1, 2}, nil)
+	if match.ShouldFilter(&scanner.ScanResult{LineCount: 2}) {
+		t.Error("2 lines should pass match filter")
+	}
+	if !match.ShouldFilter(&scanner.ScanResult{LineCount: 3}) {
+		t.Error("3 lines should be filtered by match filter")
+	}
+
+	exclude := NewLineCountFilter(nil, []int{7})
+	if !exclude.ShouldFilter(&scanner.ScanResult{LineCount: 7}) {
+		t.Error("7 lines should be filtered by exclude filter")
+	}
+	// Word count is irrelevant to a line filter.
+	if exclude.ShouldFilter(&scanner.ScanResult{LineCount: 8, WordCount: 7}) {
+		t.Error("8 lines should pass exclude filter")
+	}
+	if exclude.Name() != "lines" {
+		t.Errorf("Name() = %q, want lines", exclude.Name())
+	}
+}
+
+func TestContentTypeFilter(t *testing.T) {
+	match := NewContentTypeFilter([]string{"application/json", "text/"}, nil)
+	for _, ct := range []string{"application/json", "Application/JSON; charset=utf-8", "text/html", "text/plain; charset=utf-8"} {
+		if match.ShouldFilter(&scanner.ScanResult{ContentType: ct}) {
+			t.Errorf("%q should pass match filter", ct)
+		}
+	}
+	for _, ct := range []string{"application/json-patch+json", "application/javascript", "image/png", ""} {
+		if !match.ShouldFilter(&scanner.ScanResult{ContentType: ct}) {
+			t.Errorf("%q should be filtered by match filter", ct)
+		}
+	}
+
+	exclude := NewContentTypeFilter(nil, []string{"image/", "text/css"})
+	if !exclude.ShouldFilter(&scanner.ScanResult{ContentType: "image/svg+xml"}) {
+		t.Error("image/svg+xml should be filtered by the image/ prefix")
+	}
+	if !exclude.ShouldFilter(&scanner.ScanResult{ContentType: "text/css; charset=utf-8"}) {
+		t.Error("text/css should be filtered")
+	}
+	for _, ct := range []string{"text/html", "text/csv", ""} {
+		if exclude.ShouldFilter(&scanner.ScanResult{ContentType: ct}) {
+			t.Errorf("%q should pass exclude filter", ct)
+		}
+	}
+
+	both := NewContentTypeFilter([]string{"text/"}, []string{"text/css"})
+	if both.ShouldFilter(&scanner.ScanResult{ContentType: "text/html"}) || !both.ShouldFilter(&scanner.ScanResult{ContentType: "text/css"}) {
+		t.Error("exclusions should apply on top of matches")
+	}
+	if both.Name() != "content-type" {
+		t.Errorf("Name() = %q, want content-type", both.Name())
+	}
+}
+
+func TestRedirectFilter(t *testing.T) {
+	substr, err := NewRedirectFilter("/login")
+	if err != nil {
+		t.Fatal(err)
+	}
+	regex, err := NewRedirectFilter(`re:^https?://sso\.example\.com/`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		f      *RedirectFilter
+		result scanner.ScanResult
+		want   bool
+	}{
+		{"substring hit", substr, scanner.ScanResult{StatusCode: 302, RedirectURL: "/login?next=/admin"}, true},
+		{"substring absolute", substr, scanner.ScanResult{StatusCode: 302, RedirectURL: "https://target.com/login"}, true},
+		{"substring miss", substr, scanner.ScanResult{StatusCode: 301, RedirectURL: "/admin/"}, false},
+		{"regex hit", regex, scanner.ScanResult{StatusCode: 302, RedirectURL: "https://sso.example.com/auth"}, true},
+		{"regex miss", regex, scanner.ScanResult{StatusCode: 302, RedirectURL: "https://target.com/?r=https://sso.example.com/"}, false},
+		{"not a redirect", substr, scanner.ScanResult{StatusCode: 200, Path: "login"}, false},
+		{"not a redirect, regex", regex, scanner.ScanResult{StatusCode: 200}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.f.ShouldFilter(&tt.result); got != tt.want {
+				t.Errorf("ShouldFilter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := NewRedirectFilter("re:("); err == nil {
+		t.Error("expected an error for an invalid regex")
+	}
+}
+
+func TestLoginPageFilter(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want bool
+	}{
+		{"login form", `<html><form method="post" action="/login?next=/admin"><input name="user"><input type="password" name="pass"></form></html>`, true},
+		{"unquoted, upper case", `<FORM action=/auth><INPUT TYPE=PASSWORD NAME=pw></FORM>`, true},
+		{"normal page", `<html><h1>Admin dashboard</h1><form action="/search"><input type="text" name="q"></form></html>`, false},
+		{"password input outside a form", `<input type="password"><p>no form here</p>`, false},
+		{"password mentioned in text", `<form><label>Forgot your password?</label><input type="email"></form>`, false},
+		{"empty body", ``, false},
+	}
+	f := NewLoginPageFilter()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &scanner.ScanResult{StatusCode: 200, Body: []byte(tt.body)}
+			if got := f.ShouldFilter(result); got != tt.want {
+				t.Errorf("ShouldFilter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package filter
+
+import (
+	"regexp"
+
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+var (
+	formOpenPattern      = regexp.MustCompile(`(?i)<form\b`)
+	passwordInputPattern = regexp.MustCompile(`(?i)<input\b[^>]*\btype\s*=\s*["']?password\b`)
+)
+
+// LoginPageFilter hides pages that show a login form: a <form> followed
+// by a password input. Applications behind an auth wall often answer
+// every protected path with 200 and the same login page, slightly
+// different each time (CSRF tokens, a "next" parameter), so size and hash
+// heuristics miss it. The body must be retained for this filter to see it.
+type LoginPageFilter struct{}
+
+// NewLoginPageFilter creates a filter that hides login pages.
+func NewLoginPageFilter() *LoginPageFilter {
+	return &LoginPageFilter{}
+}
+
+func (f *LoginPageFilter) Name() string { return "login-page" }
+
+func (f *LoginPageFilter) ShouldFilter(result *scanner.ScanResult) bool {
+	return isLoginPage(result.Body)
+}
+
+// isLoginPage reports whether body has a password input after the start
+// of a form.
+func isLoginPage(body []byte) bool {
+	loc := formOpenPattern.FindIndex(body)
+	return loc != nil && passwordInputPattern.Match(body[loc[0]:])
+}
@@ -0,0 +1,48 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+// RedirectRegexPrefix marks a RedirectFilter pattern as a regular
+// expression rather than a plain substring.
+const RedirectRegexPrefix = "re:"
+
+// RedirectFilter hides redirects whose Location points at a known page,
+// such as the login form every unknown path is sent to. Results that are
+// not redirects always pass.
+type RedirectFilter struct {
+	substr string
+	re     *regexp.Regexp // nil = substring match
+}
+
+// NewRedirectFilter creates a filter for redirects whose target contains
+// pattern. A pattern starting with "re:" is a regular expression matched
+// against the target instead.
+func NewRedirectFilter(pattern string) (*RedirectFilter, error) {
+	expr, isRegex := strings.CutPrefix(pattern, RedirectRegexPrefix)
+	if !isRegex {
+		return &RedirectFilter{substr: pattern}, nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex %q: %w", expr, err)
+	}
+	return &RedirectFilter{re: re}, nil
+}
+
+func (f *RedirectFilter) Name() string { return "redirect-to" }
+
+func (f *RedirectFilter) ShouldFilter(result *scanner.ScanResult) bool {
+	if result.RedirectURL == "" {
+		return false
+	}
+	if f.re != nil {
+		return f.re.MatchString(result.RedirectURL)
+	}
+	return strings.Contains(result.RedirectURL, f.substr)
+}
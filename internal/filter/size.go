@@ -1,6 +1,12 @@
 package filter
 
-import "github.com/maxvaer/dirfuzz/internal/scanner"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
 
 // SizeFilter excludes results matching specific response body sizes.
 type SizeFilter struct {
@@ -22,3 +28,72 @@ func (f *SizeFilter) ShouldFilter(result *scanner.ScanResult) bool {
 	_, ok := f.sizes[result.ContentLength]
 	return ok
 }
+
+// sizeRange is an inclusive [min, max] interval of body sizes.
+type sizeRange struct {
+	min, max int64
+}
+
+// SizeRangeFilter excludes results whose body size falls within any of a
+// set of inclusive ranges. Useful for soft-404s whose length jitters by a
+// few bytes (e.g. embedded timestamps).
+type SizeRangeFilter struct {
+	ranges []sizeRange
+}
+
+// NewSizeRangeFilter parses ranges of the form "min-max" (e.g. "1200-1260")
+// and returns a filter that drops results whose size is within any of them.
+func NewSizeRangeFilter(specs []string) (*SizeRangeFilter, error) {
+	f := &SizeRangeFilter{ranges: make([]sizeRange, 0, len(specs))}
+	for _, spec := range specs {
+		lo, hi, ok := strings.Cut(strings.TrimSpace(spec), "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid size range %q, expected min-max", spec)
+		}
+		minSize, err := strconv.ParseInt(strings.TrimSpace(lo), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid size range %q: %w", spec, err)
+		}
+		maxSize, err := strconv.ParseInt(strings.TrimSpace(hi), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid size range %q: %w", spec, err)
+		}
+		if minSize < 0 || maxSize < minSize {
+			return nil, fmt.Errorf("invalid size range %q: min must be >= 0 and <= max", spec)
+		}
+		f.ranges = append(f.ranges, sizeRange{min: minSize, max: maxSize})
+	}
+	return f, nil
+}
+
+func (f *SizeRangeFilter) Name() string { return "size-range" }
+
+func (f *SizeRangeFilter) ShouldFilter(result *scanner.ScanResult) bool {
+	for _, r := range f.ranges {
+		if result.ContentLength >= r.min && result.ContentLength <= r.max {
+			return true
+		}
+	}
+	return false
+}
+
+// MinSizeFilter excludes results whose body is smaller than a threshold,
+// catching one-line "Not Found" snippets that aren't quite empty. Redirects
+// are exempt since their bodies are usually tiny or empty by design.
+type MinSizeFilter struct {
+	min int64
+}
+
+// NewMinSizeFilter creates a filter that drops results smaller than min bytes.
+func NewMinSizeFilter(min int64) *MinSizeFilter {
+	return &MinSizeFilter{min: min}
+}
+
+func (f *MinSizeFilter) Name() string { return "min-size" }
+
+func (f *MinSizeFilter) ShouldFilter(result *scanner.ScanResult) bool {
+	if result.StatusCode >= 300 && result.StatusCode < 400 {
+		return false
+	}
+	return result.ContentLength < f.min
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"math"
+	"net/url"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/maxvaer/dirfuzz/internal/scanner"
 )
@@ -32,58 +35,116 @@ type baseline struct {
 // random non-existent paths before the scan starts.
 type SmartFilter struct {
 	baselines []baseline
-	threshold int // byte tolerance for fuzzy length matching
+	threshold int  // byte tolerance for fuzzy length matching
+	wildcard  bool // vhost calibration: every host name got the same page
 }
 
+// minProbes is the fewest calibration probes that can establish a baseline.
+const minProbes = 2
+
 // NewSmartFilter performs calibration against the target and returns a filter
 // that can detect soft-404 responses during scanning. basePath is the
 // directory prefix for probes (e.g. "" for root, "Home" for /Home/).
+// probeCount is the number of random paths requested (minimum 2); more
+// probes give a steadier baseline at the cost of extra requests. If
+// calibratePath is set (--calibrate-url), it replaces the random probes:
+// that path and random variations of it are requested instead. At most
+// workers probes (capped at maxProbeWorkers) are in flight at once.
 // Returns an error if calibration fails entirely.
-func NewSmartFilter(ctx context.Context, req *scanner.Requester, basePath string, threshold int) (*SmartFilter, error) {
-	probes := generateProbes(5)
+func NewSmartFilter(ctx context.Context, req *scanner.Requester, basePath, calibratePath string, threshold, probeCount, workers int) (*SmartFilter, error) {
+	var probes []string
+	if calibratePath != "" {
+		probes = generateSeededProbes(calibratePath, max(probeCount, minProbes))
+	} else {
+		probes = generateProbes(max(probeCount, minProbes))
+	}
 
-	var results []probeResult
-	for _, probe := range probes {
+	results, err := runProbes(ctx, len(probes), workers, func(i int) (*scanner.Response, error) {
+		probe := probes[i]
 		if basePath != "" {
 			probe = strings.TrimRight(basePath, "/") + "/" + probe
 		}
-		resp, err := req.Do(ctx, "GET", probe, "")
-		if err != nil {
-			continue
-		}
-		results = append(results, probeResult{
-			statusCode:    resp.StatusCode,
-			contentLength: resp.ContentLength,
-			bodyHash:      resp.BodyHash,
-			wordCount:     resp.WordCount,
-			lineCount:     resp.LineCount,
-		})
+		return req.Do(ctx, "GET", probe, "")
+	})
+	if err != nil {
+		return nil, err
 	}
-
 	return buildSmartFilter(results, len(probes), threshold)
 }
 
 // NewSmartFilterVHost performs calibration for virtual host fuzzing by
-// sending requests with random subdomain Host headers.
-func NewSmartFilterVHost(ctx context.Context, req *scanner.Requester, targetURL string, threshold int) (*SmartFilter, error) {
-	probeHosts := generateVHostProbes(5)
+// sending probeCount requests with random subdomain Host headers, at most
+// workers at a time.
+//
+// If every probe and the target's own host name get the identical page,
+// the server ignores the Host header altogether (wildcard DNS or a single
+// catch-all vhost); Wildcard then reports true.
+func NewSmartFilterVHost(ctx context.Context, req *scanner.Requester, targetURL string, threshold, probeCount, workers int) (*SmartFilter, error) {
+	probeHosts := generateVHostProbes(max(probeCount, minProbes))
+
+	results, err := runProbes(ctx, len(probeHosts), workers, func(i int) (*scanner.Response, error) {
+		return req.Do(ctx, "GET", "/", probeHosts[i])
+	})
+	if err != nil {
+		return nil, err
+	}
+	sf, err := buildSmartFilter(results, len(probeHosts), threshold)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == len(probeHosts) && allIdentical(results) {
+		if resp, err := req.Do(ctx, "GET", "/", ""); err == nil {
+			sf.wildcard = resp.StatusCode == results[0].statusCode && resp.BodyHash == results[0].bodyHash
+		}
+	}
+	return sf, nil
+}
 
-	var results []probeResult
-	for _, host := range probeHosts {
-		resp, err := req.Do(ctx, "GET", "/", host)
-		if err != nil {
-			continue
+// Wildcard reports whether vhost calibration found the target answering
+// every host name, its own included, with the same page.
+func (sf *SmartFilter) Wildcard() bool { return sf.wildcard }
+
+// FindCalibrated requests one random non-existent path and returns the
+// first of filters whose baseline the answer matches. A target that
+// answers unknown paths like an already calibrated one (e.g. another
+// node of a load-balanced cluster) can then reuse its filter instead of
+// being calibrated again (--share-calibration). It returns nil if none
+// fits or the probe fails.
+func FindCalibrated(ctx context.Context, req *scanner.Requester, calibratePath string, filters []*SmartFilter) *SmartFilter {
+	if len(filters) == 0 {
+		return nil
+	}
+	probe := generateProbes(1)[0]
+	if calibratePath != "" {
+		probe = generateSeededProbes(calibratePath, 1)[0]
+	}
+	resp, err := req.Do(ctx, "GET", probe, "")
+	if err != nil {
+		return nil
+	}
+	result := &scanner.ScanResult{
+		StatusCode:    resp.StatusCode,
+		ContentLength: resp.ContentLength,
+		BodyHash:      resp.BodyHash,
+		WordCount:     resp.WordCount,
+		LineCount:     resp.LineCount,
+	}
+	for _, sf := range filters {
+		if sf.matchesBaseline(result) {
+			return sf
 		}
-		results = append(results, probeResult{
-			statusCode:    resp.StatusCode,
-			contentLength: resp.ContentLength,
-			bodyHash:      resp.BodyHash,
-			wordCount:     resp.WordCount,
-			lineCount:     resp.LineCount,
-		})
 	}
+	return nil
+}
 
-	return buildSmartFilter(results, len(probeHosts), threshold)
+// allIdentical reports whether all results share one status and body.
+func allIdentical(results []probeResult) bool {
+	for _, r := range results[1:] {
+		if r.statusCode != results[0].statusCode || r.bodyHash != results[0].bodyHash {
+			return false
+		}
+	}
+	return true
 }
 
 type probeResult struct {
@@ -94,6 +155,69 @@ type probeResult struct {
 	lineCount     int
 }
 
+// maxProbeWorkers bounds the calibration probes in flight at once, however
+// many --threads there are.
+const maxProbeWorkers = 8
+
+// runProbes sends n calibration probes and returns the results of those
+// that succeeded, in probe order. The first probe goes out alone, so it
+// opens the connection (and with --calibrate-url is the known-missing
+// path itself); the rest run on up to workers goroutines (at least one,
+// at most maxProbeWorkers). Failed probes are skipped; if ctx ends,
+// calibration is aborted with its error.
+func runProbes(ctx context.Context, n, workers int, probe func(i int) (*scanner.Response, error)) ([]probeResult, error) {
+	slots := make([]*probeResult, n)
+	send := func(i int) {
+		resp, err := probe(i)
+		if err != nil {
+			return
+		}
+		slots[i] = &probeResult{
+			statusCode:    resp.StatusCode,
+			contentLength: resp.ContentLength,
+			bodyHash:      resp.BodyHash,
+			wordCount:     resp.WordCount,
+			lineCount:     resp.LineCount,
+		}
+	}
+	if n > 0 {
+		send(0)
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range min(n-1, max(workers, 1), maxProbeWorkers) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				send(i)
+			}
+		}()
+	}
+feed:
+	for i := 1; i < n; i++ {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var results []probeResult
+	for _, r := range slots {
+		if r != nil {
+			results = append(results, *r)
+		}
+	}
+	return results, nil
+}
+
 func buildSmartFilter(results []probeResult, probeCount, threshold int) (*SmartFilter, error) {
 	if len(results) < 2 {
 		return nil, fmt.Errorf("only %d/%d calibration probes succeeded, need at least 2", len(results), probeCount)
@@ -170,6 +294,85 @@ func buildSmartFilter(results []probeResult, probeCount, threshold int) (*SmartF
 	return sf, nil
 }
 
+// smartFilterJSON is the serialized form of a SmartFilter, used to persist
+// a calibrated baseline in the resume file.
+type smartFilterJSON struct {
+	Threshold int            `json:"threshold"`
+	Baselines []baselineJSON `json:"baselines"`
+	Wildcard  bool           `json:"wildcard,omitempty"`
+}
+
+type baselineJSON struct {
+	StatusCode    int    `json:"status"`
+	ContentLength int64  `json:"size"`
+	BodyHash      string `json:"hash,omitempty"`
+	WordCount     int    `json:"words"`
+	LineCount     int    `json:"lines"`
+	Mode          string `json:"mode"` // "hash" or "fuzzy"
+}
+
+// MarshalJSON serializes the calibrated baselines and threshold.
+func (sf *SmartFilter) MarshalJSON() ([]byte, error) {
+	out := smartFilterJSON{
+		Threshold: sf.threshold,
+		Baselines: make([]baselineJSON, len(sf.baselines)),
+		Wildcard:  sf.wildcard,
+	}
+	for i, b := range sf.baselines {
+		bj := baselineJSON{
+			StatusCode:    b.statusCode,
+			ContentLength: b.contentLength,
+			WordCount:     b.wordCount,
+			LineCount:     b.lineCount,
+			Mode:          "fuzzy",
+		}
+		if b.mode == matchHashExact {
+			bj.Mode = "hash"
+			bj.BodyHash = hex.EncodeToString(b.bodyHash[:])
+		}
+		out.Baselines[i] = bj
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON restores a SmartFilter previously written by MarshalJSON.
+func (sf *SmartFilter) UnmarshalJSON(data []byte) error {
+	var in smartFilterJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	baselines := make([]baseline, len(in.Baselines))
+	for i, bj := range in.Baselines {
+		b := baseline{
+			statusCode:    bj.StatusCode,
+			contentLength: bj.ContentLength,
+			wordCount:     bj.WordCount,
+			lineCount:     bj.LineCount,
+		}
+		switch bj.Mode {
+		case "hash":
+			b.mode = matchHashExact
+			hash, err := hex.DecodeString(bj.BodyHash)
+			if err != nil || len(hash) != len(b.bodyHash) {
+				return fmt.Errorf("invalid baseline hash %q", bj.BodyHash)
+			}
+			copy(b.bodyHash[:], hash)
+		case "fuzzy":
+			b.mode = matchFuzzyLength
+		default:
+			return fmt.Errorf("unknown baseline mode %q", bj.Mode)
+		}
+		baselines[i] = b
+	}
+	if len(baselines) == 0 {
+		return fmt.Errorf("smart filter has no baselines")
+	}
+	sf.threshold = in.Threshold
+	sf.baselines = baselines
+	sf.wildcard = in.Wildcard
+	return nil
+}
+
 func (sf *SmartFilter) Name() string { return "smart-404" }
 
 func (sf *SmartFilter) ShouldFilter(result *scanner.ScanResult) bool {
@@ -177,7 +380,12 @@ func (sf *SmartFilter) ShouldFilter(result *scanner.ScanResult) bool {
 	if result.StatusCode == 200 && result.ContentLength == 0 {
 		return true
 	}
+	return sf.matchesBaseline(result)
+}
 
+// matchesBaseline reports whether result looks like one of the calibrated
+// soft-404 pages.
+func (sf *SmartFilter) matchesBaseline(result *scanner.ScanResult) bool {
 	for _, b := range sf.baselines {
 		if result.StatusCode != b.statusCode {
 			continue
@@ -227,6 +435,24 @@ func generateProbes(n int) []string {
 	return probes
 }
 
+// generateSeededProbes returns seed itself followed by n-1 variations of it
+// with a random suffix, for calibrating against a path known not to exist.
+// seed may be a path or a full URL; only its path is used.
+func generateSeededProbes(seed string, n int) []string {
+	if u, err := url.Parse(seed); err == nil && u.IsAbs() {
+		seed = u.Path
+	}
+	seed = strings.Trim(seed, "/")
+	probes := make([]string, n)
+	probes[0] = seed
+	for i := 1; i < n; i++ {
+		buf := make([]byte, 4)
+		_, _ = rand.Read(buf)
+		probes[i] = seed + "-" + hex.EncodeToString(buf)
+	}
+	return probes
+}
+
 // generateVHostProbes creates random subdomain strings for vhost calibration.
 func generateVHostProbes(n int) []string {
 	probes := make([]string, n)
@@ -1,13 +1,18 @@
 package filter
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"crypto/md5"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -235,6 +240,73 @@ func TestSmartFilter_MultipleBaselines(t *testing.T) {
 	}
 }
 
+func TestSmartFilter_JSONRoundTrip(t *testing.T) {
+	orig := &SmartFilter{
+		baselines: []baseline{
+			{
+				statusCode:    200,
+				contentLength: 1000,
+				bodyHash:      md5.Sum([]byte("soft 404 page")),
+				wordCount:     3,
+				lineCount:     1,
+				mode:          matchHashExact,
+			},
+			{
+				statusCode:    302,
+				contentLength: 200,
+				wordCount:     10,
+				lineCount:     4,
+				mode:          matchFuzzyLength,
+			},
+		},
+		threshold: 50,
+		wildcard:  true,
+	}
+
+	data, err := json.Marshal(orig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var restored SmartFilter
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []*scanner.ScanResult{
+		{StatusCode: 200, ContentLength: 1000, BodyHash: md5.Sum([]byte("soft 404 page"))},
+		{StatusCode: 200, ContentLength: 1000, BodyHash: md5.Sum([]byte("real page"))},
+		{StatusCode: 302, ContentLength: 210, WordCount: 10, LineCount: 4},
+		{StatusCode: 302, ContentLength: 900, WordCount: 80, LineCount: 30},
+		{StatusCode: 200, ContentLength: 0},
+		{StatusCode: 404, ContentLength: 1000},
+	}
+	for i, r := range cases {
+		if got, want := restored.ShouldFilter(r), orig.ShouldFilter(r); got != want {
+			t.Errorf("case %d: restored ShouldFilter = %v, original = %v", i, got, want)
+		}
+	}
+	if restored.threshold != orig.threshold {
+		t.Errorf("threshold = %d, want %d", restored.threshold, orig.threshold)
+	}
+	if !restored.Wildcard() {
+		t.Error("wildcard flag lost in round trip")
+	}
+}
+
+func TestSmartFilter_UnmarshalInvalid(t *testing.T) {
+	inputs := []string{
+		`{"threshold":50,"baselines":[]}`,
+		`{"threshold":50,"baselines":[{"status":200,"mode":"bogus"}]}`,
+		`{"threshold":50,"baselines":[{"status":200,"mode":"hash","hash":"zz"}]}`,
+	}
+	for _, in := range inputs {
+		var sf SmartFilter
+		if err := json.Unmarshal([]byte(in), &sf); err == nil {
+			t.Errorf("expected error for %s", in)
+		}
+	}
+}
+
 func TestNewSmartFilter_BasePathProbesCorrectDirectory(t *testing.T) {
 	// Track which paths were requested.
 	var mu sync.Mutex
@@ -269,7 +341,7 @@ func TestNewSmartFilter_BasePathProbesCorrectDirectory(t *testing.T) {
 
 	// Test 1: empty basePath probes root paths.
 	requestedPaths = nil
-	_, err = NewSmartFilter(ctx, req, "", 50)
+	_, err = NewSmartFilter(ctx, req, "", "", 50, 5, 8)
 	if err != nil {
 		t.Fatalf("root smart filter: %v", err)
 	}
@@ -293,7 +365,7 @@ func TestNewSmartFilter_BasePathProbesCorrectDirectory(t *testing.T) {
 	requestedPaths = nil
 	mu.Unlock()
 
-	_, err = NewSmartFilter(ctx, req, "subdir", 50)
+	_, err = NewSmartFilter(ctx, req, "subdir", "", 50, 5, 8)
 	if err != nil {
 		t.Fatalf("subdir smart filter: %v", err)
 	}
@@ -337,12 +409,12 @@ func TestNewSmartFilter_BasePathDifferentBaselines(t *testing.T) {
 
 	ctx := context.Background()
 
-	rootSF, err := NewSmartFilter(ctx, req, "", 50)
+	rootSF, err := NewSmartFilter(ctx, req, "", "", 50, 5, 8)
 	if err != nil {
 		t.Fatalf("root smart filter: %v", err)
 	}
 
-	subdirSF, err := NewSmartFilter(ctx, req, "subdir", 50)
+	subdirSF, err := NewSmartFilter(ctx, req, "subdir", "", 50, 5, 8)
 	if err != nil {
 		t.Fatalf("subdir smart filter: %v", err)
 	}
@@ -403,7 +475,7 @@ func TestNewSmartFilter_NestedBasePath(t *testing.T) {
 		t.Fatalf("creating requester: %v", err)
 	}
 
-	sf, err := NewSmartFilter(context.Background(), req, "a/b/c", 50)
+	sf, err := NewSmartFilter(context.Background(), req, "a/b/c", "", 50, 5, 8)
 	if err != nil {
 		t.Fatalf("nested smart filter: %v", err)
 	}
@@ -419,3 +491,320 @@ func TestNewSmartFilter_NestedBasePath(t *testing.T) {
 		t.Error("nested filter should filter its own 404 page")
 	}
 }
+
+func TestNewSmartFilter_ProbeCount(t *testing.T) {
+	var mu sync.Mutex
+	probes := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "dirfuzz_probe_") {
+			mu.Lock()
+			probes++
+			mu.Unlock()
+		}
+		fmt.Fprint(w, "not found")
+	}))
+	defer server.Close()
+
+	req, err := scanner.NewRequester(&config.Options{
+		URL:     server.URL,
+		Timeout: 5 * time.Second,
+		Threads: 1,
+	})
+	if err != nil {
+		t.Fatalf("creating requester: %v", err)
+	}
+
+	for _, n := range []int{2, 9} {
+		mu.Lock()
+		probes = 0
+		mu.Unlock()
+		if _, err := NewSmartFilter(context.Background(), req, "", "", 50, n, 8); err != nil {
+			t.Fatalf("probes=%d: %v", n, err)
+		}
+		mu.Lock()
+		got := probes
+		mu.Unlock()
+		if got != n {
+			t.Errorf("probes=%d: server received %d probe requests", n, got)
+		}
+	}
+}
+
+func TestNewSmartFilter_GzipResponses(t *testing.T) {
+	gz := func(s string) []byte {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		_, _ = zw.Write([]byte(s))
+		_ = zw.Close()
+		return buf.Bytes()
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		if r.URL.Path == "/admin" {
+			_, _ = w.Write(gz("welcome to the admin panel, this page has different content"))
+			return
+		}
+		_, _ = w.Write(gz("page not found"))
+	}))
+	defer server.Close()
+
+	req, err := scanner.NewRequester(&config.Options{
+		URL:     server.URL,
+		Timeout: 5 * time.Second,
+		Threads: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sf, err := NewSmartFilter(context.Background(), req, "", "", 50, 5, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sf.baselines) != 1 || sf.baselines[0].mode != matchHashExact {
+		t.Fatalf("expected a single hash baseline, got %+v", sf.baselines)
+	}
+	if sf.baselines[0].bodyHash != md5.Sum([]byte("page not found")) {
+		t.Error("expected baseline hash over the decoded body")
+	}
+
+	for path, want := range map[string]bool{"/missing": true, "/admin": false} {
+		resp, err := req.Do(context.Background(), "GET", path, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := &scanner.ScanResult{
+			StatusCode:    resp.StatusCode,
+			ContentLength: resp.ContentLength,
+			BodyHash:      resp.BodyHash,
+			WordCount:     resp.WordCount,
+			LineCount:     resp.LineCount,
+		}
+		if got := sf.ShouldFilter(r); got != want {
+			t.Errorf("%s: ShouldFilter = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestNewSmartFilter_CalibratePath(t *testing.T) {
+	var mu sync.Mutex
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.URL.Path)
+		mu.Unlock()
+		if r.URL.Path == "/admin" {
+			fmt.Fprint(w, "welcome to the admin panel, lots of real content here")
+			return
+		}
+		fmt.Fprint(w, "<html>Sorry, nothing here</html>")
+	}))
+	defer server.Close()
+
+	req, err := scanner.NewRequester(&config.Options{
+		URL:     server.URL,
+		Timeout: 5 * time.Second,
+		Threads: 1,
+	})
+	if err != nil {
+		t.Fatalf("creating requester: %v", err)
+	}
+
+	sf, err := NewSmartFilter(context.Background(), req, "", "/definitely-missing", 50, 3, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	got := append([]string(nil), requested...)
+	mu.Unlock()
+	if len(got) != 3 || got[0] != "/definitely-missing" {
+		t.Fatalf("requested %v, want /definitely-missing first and 3 probes", got)
+	}
+	for _, p := range got {
+		if !strings.HasPrefix(p, "/definitely-missing") || strings.Contains(p, "dirfuzz_probe_") {
+			t.Errorf("probe %s is not a variation of the calibrate path", p)
+		}
+	}
+
+	miss := &scanner.ScanResult{StatusCode: 200, ContentLength: 32, BodyHash: md5.Sum([]byte("<html>Sorry, nothing here</html>")), WordCount: 4, LineCount: 1}
+	if !sf.ShouldFilter(miss) {
+		t.Error("response matching the calibrated 404 should be filtered")
+	}
+	body := []byte("welcome to the admin panel, lots of real content here")
+	hit := &scanner.ScanResult{StatusCode: 200, ContentLength: int64(len(body)), BodyHash: md5.Sum(body), WordCount: 10, LineCount: 1}
+	if sf.ShouldFilter(hit) {
+		t.Error("real content should not be filtered")
+	}
+}
+
+func TestGenerateSeededProbes(t *testing.T) {
+	probes := generateSeededProbes("https://example.com/missing/page", 3)
+	if probes[0] != "missing/page" {
+		t.Errorf("first probe = %q, want the seed path", probes[0])
+	}
+	if probes[1] == probes[2] || !strings.HasPrefix(probes[1], "missing/page-") {
+		t.Errorf("variations = %v, want distinct suffixed copies of the seed", probes[1:])
+	}
+}
+
+func TestNewSmartFilterVHost_Wildcard(t *testing.T) {
+	tests := []struct {
+		name         string
+		ownHostBody  string
+		wantWildcard bool
+	}{
+		{"every host identical", "catch-all", true},
+		{"own host differs", "real site", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.HasPrefix(r.Host, "dirfuzz-") {
+					fmt.Fprint(w, "catch-all")
+					return
+				}
+				fmt.Fprint(w, tt.ownHostBody)
+			}))
+			defer server.Close()
+
+			req, err := scanner.NewRequester(&config.Options{
+				URL:     server.URL,
+				Timeout: 5 * time.Second,
+				Threads: 1,
+			})
+			if err != nil {
+				t.Fatalf("creating requester: %v", err)
+			}
+			sf, err := NewSmartFilterVHost(context.Background(), req, server.URL, 50, 3, 8)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sf.Wildcard() != tt.wantWildcard {
+				t.Errorf("Wildcard() = %v, want %v", sf.Wildcard(), tt.wantWildcard)
+			}
+		})
+	}
+}
+
+func TestFindCalibrated(t *testing.T) {
+	newTarget := func(body string) *scanner.Requester {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, body)
+		}))
+		t.Cleanup(srv.Close)
+		req, err := scanner.NewRequester(&config.Options{URL: srv.URL, Timeout: 5 * time.Second, Threads: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return req
+	}
+	ctx := context.Background()
+
+	sf, err := NewSmartFilter(ctx, newTarget("shop: page not found"), "", "", 50, 3, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := FindCalibrated(ctx, newTarget("shop: page not found"), "", []*SmartFilter{sf}); got != sf {
+		t.Error("an identical target should reuse the filter")
+	}
+	if got := FindCalibrated(ctx, newTarget("blog: nothing here, sorry"), "", []*SmartFilter{sf}); got != nil {
+		t.Error("a target with another soft-404 page should not reuse the filter")
+	}
+	if got := FindCalibrated(ctx, newTarget("shop: page not found"), "", nil); got != nil {
+		t.Error("expected nil without cached filters")
+	}
+}
+
+func TestNewSmartFilter_ConcurrentProbes(t *testing.T) {
+	const probes, delay = 8, 100 * time.Millisecond
+	var seen sync.Map
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen.Store(r.URL.Path, true)
+		time.Sleep(delay)
+		fmt.Fprint(w, "custom not found page")
+	}))
+	defer srv.Close()
+	req, err := scanner.NewRequester(&config.Options{URL: srv.URL, Timeout: 5 * time.Second, Threads: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	sf, err := NewSmartFilter(context.Background(), req, "", "", 50, probes, probes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// One probe goes out first, the other seven together.
+	if elapsed := time.Since(start); elapsed >= probes*delay/2 {
+		t.Errorf("calibration took %s, want well under the sequential %s", elapsed, probes*delay)
+	}
+	n := 0
+	seen.Range(func(any, any) bool { n++; return true })
+	if n != probes {
+		t.Errorf("server saw %d probe paths, want %d", n, probes)
+	}
+
+	// Same baseline as a single exact-hash group would give.
+	got, err := json.Marshal(sf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := md5.Sum([]byte("custom not found page"))
+	want, err := json.Marshal(&SmartFilter{threshold: 50, baselines: []baseline{{
+		statusCode: 200, contentLength: 21, bodyHash: hash, wordCount: 4, lineCount: 1, mode: matchHashExact,
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("baseline = %s, want %s", got, want)
+	}
+}
+
+func TestNewSmartFilter_ProbeWorkersBounded(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		fmt.Fprint(w, "custom not found page")
+	}))
+	defer srv.Close()
+	req, err := scanner.NewRequester(&config.Options{URL: srv.URL, Timeout: 5 * time.Second, Threads: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, workers := range []int{1, 2} {
+		peak.Store(0)
+		if _, err := NewSmartFilter(context.Background(), req, "", "", 50, 6, workers); err != nil {
+			t.Fatal(err)
+		}
+		if got := peak.Load(); got > int32(workers) {
+			t.Errorf("workers=%d: %d probes in flight at once", workers, got)
+		}
+	}
+}
+
+func TestNewSmartFilter_Cancelled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+	}))
+	defer srv.Close()
+	req, err := scanner.NewRequester(&config.Options{URL: srv.URL, Timeout: 5 * time.Second, Threads: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := NewSmartFilter(ctx, req, "", "", 50, 20, 8); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+}
@@ -2,13 +2,36 @@ package netutil
 
 import (
 	"fmt"
+	"iter"
 	"net"
+	"slices"
 	"strings"
 )
 
+// maxIPv6HostBits is the largest IPv6 host part (i.e. a /96) that
+// ExpandTargets will enumerate regardless of maxHosts.
+const maxIPv6HostBits = 32
+
 // ExpandTargets takes a CIDR range and a set of ports, and returns a list
-// of base URLs (scheme://host:port) to scan.
-func ExpandTargets(cidr string, portsStr string, scheme string) ([]string, error) {
+// of base URLs (scheme://host:port) to scan. Ranges with more than maxHosts
+// addresses are rejected (0 = no limit). Addresses matching any entry of
+// exclude (bare IPs or CIDR sub-ranges) are left out.
+func ExpandTargets(cidr string, portsStr string, scheme string, maxHosts int, exclude []string) ([]string, error) {
+	seq, err := Targets(cidr, portsStr, scheme, maxHosts, exclude)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Collect(seq), nil
+}
+
+// Targets is the streaming form of ExpandTargets: it validates the range
+// up front and then yields one URL at a time without building the list.
+func Targets(cidr string, portsStr string, scheme string, maxHosts int, exclude []string) (iter.Seq[string], error) {
+	excluded, err := parseExclusions(exclude)
+	if err != nil {
+		return nil, err
+	}
+
 	ip, ipnet, err := net.ParseCIDR(cidr)
 	if err != nil {
 		// Maybe it's a single IP, not a CIDR.
@@ -23,6 +46,15 @@ func ExpandTargets(cidr string, portsStr string, scheme string) ([]string, error
 		ipnet = &net.IPNet{IP: ip, Mask: mask}
 	}
 
+	ones, bits := ipnet.Mask.Size()
+	hostBits := bits - ones
+	if bits == 128 && hostBits > maxIPv6HostBits {
+		return nil, fmt.Errorf("IPv6 range %s is too large to enumerate: use a /%d or longer prefix", cidr, 128-maxIPv6HostBits)
+	}
+	if maxHosts > 0 && (hostBits >= 63 || uint64(1)<<hostBits > uint64(maxHosts)) {
+		return nil, fmt.Errorf("range %s has more than %d hosts: narrow it or raise --max-hosts", cidr, maxHosts)
+	}
+
 	ports := parsePorts(portsStr)
 	if len(ports) == 0 {
 		if scheme == "https" {
@@ -32,32 +64,69 @@ func ExpandTargets(cidr string, portsStr string, scheme string) ([]string, error
 		}
 	}
 
-	var urls []string
-	for ip := ip.Mask(ipnet.Mask); ipnet.Contains(ip); inc(ip) {
-		// Skip network and broadcast addresses for /24 and larger.
-		ones, bits := ipnet.Mask.Size()
-		if bits-ones > 1 {
-			if ip.Equal(ipnet.IP) {
-				continue // network address
+	return func(yield func(string) bool) {
+		bcast := broadcastAddr(ipnet)
+		for ip := ip.Mask(ipnet.Mask); ipnet.Contains(ip); inc(ip) {
+			// Skip network and broadcast addresses for /24 and larger.
+			if hostBits > 1 && (ip.Equal(ipnet.IP) || ip.Equal(bcast)) {
+				continue
 			}
-			bcast := broadcastAddr(ipnet)
-			if ip.Equal(bcast) {
-				continue // broadcast address
+			if isExcluded(ip, excluded) {
+				continue
 			}
-		}
 
-		for _, port := range ports {
-			host := ip.String()
-			// Skip default port in URL for cleanliness.
-			if (scheme == "http" && port == "80") || (scheme == "https" && port == "443") {
-				urls = append(urls, fmt.Sprintf("%s://%s", scheme, host))
-			} else {
-				urls = append(urls, fmt.Sprintf("%s://%s:%s", scheme, host, port))
+			for _, port := range ports {
+				host := ip.String()
+				if ip.To4() == nil {
+					host = "[" + host + "]"
+				}
+				// Skip default port in URL for cleanliness.
+				var u string
+				if (scheme == "http" && port == "80") || (scheme == "https" && port == "443") {
+					u = fmt.Sprintf("%s://%s", scheme, host)
+				} else {
+					u = fmt.Sprintf("%s://%s:%s", scheme, host, port)
+				}
+				if !yield(u) {
+					return
+				}
 			}
 		}
+	}, nil
+}
+
+// parseExclusions parses bare IPs and CIDR ranges into networks.
+func parseExclusions(specs []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(specs))
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		if _, n, err := net.ParseCIDR(spec); err == nil {
+			nets = append(nets, n)
+			continue
+		}
+		ip := net.ParseIP(spec)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid excluded host %q: expected an IP or CIDR", spec)
+		}
+		bits := 128
+		if v4 := ip.To4(); v4 != nil {
+			ip, bits = v4, 32
+		}
+		nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
 	}
+	return nets, nil
+}
 
-	return urls, nil
+func isExcluded(ip net.IP, excluded []*net.IPNet) bool {
+	for _, n := range excluded {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
 }
 
 func parsePorts(s string) []string {
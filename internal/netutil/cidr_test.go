@@ -0,0 +1,106 @@
+package netutil
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExpandTargets_SmallRange(t *testing.T) {
+	urls, err := ExpandTargets("192.168.1.0/30", "80,8080", "http", 65536, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// /30 has 4 addresses; network and broadcast are skipped.
+	want := []string{
+		"http://192.168.1.1", "http://192.168.1.1:8080",
+		"http://192.168.1.2", "http://192.168.1.2:8080",
+	}
+	if strings.Join(urls, " ") != strings.Join(want, " ") {
+		t.Errorf("got %v, want %v", urls, want)
+	}
+}
+
+func TestExpandTargets_SmallIPv6Range(t *testing.T) {
+	urls, err := ExpandTargets("2001:db8::/126", "", "https", 65536, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"https://[2001:db8::1]", "https://[2001:db8::2]"}
+	if strings.Join(urls, " ") != strings.Join(want, " ") {
+		t.Errorf("got %v, want %v", urls, want)
+	}
+}
+
+func TestExpandTargets_RejectsLargeRanges(t *testing.T) {
+	tests := []struct {
+		cidr     string
+		maxHosts int
+	}{
+		{"10.0.0.0/8", 65536},
+		{"10.0.0.0/23", 256},
+		{"2001:db8::/64", 0}, // IPv6 prefix too short even without a limit
+		{"2001:db8::/64", 65536},
+		{"2001:db8::/112", 1000},
+	}
+	for _, tt := range tests {
+		if _, err := ExpandTargets(tt.cidr, "", "http", tt.maxHosts, nil); err == nil {
+			t.Errorf("ExpandTargets(%q, max %d) should fail", tt.cidr, tt.maxHosts)
+		}
+	}
+}
+
+func TestExpandTargets_NoLimit(t *testing.T) {
+	urls, err := ExpandTargets("10.0.0.0/15", "", "http", 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(urls) != 1<<17-2 {
+		t.Errorf("got %d URLs, want %d", len(urls), 1<<17-2)
+	}
+}
+
+func TestTargets_StopsEarly(t *testing.T) {
+	seq, err := Targets("10.0.0.0/16", "", "http", 65536, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for u := range seq {
+		got = append(got, u)
+		if len(got) == 3 {
+			break
+		}
+	}
+	if len(got) != 3 || got[0] != "http://10.0.0.1" {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestExpandTargets_ExcludeHosts(t *testing.T) {
+	exclude := []string{"192.168.1.1", " 192.168.1.254 ", "192.168.1.16/28"}
+	urls, err := ExpandTargets("192.168.1.0/24", "", "http", 65536, exclude)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 254 usable hosts, minus .1, .254, and .16-.31.
+	if len(urls) != 254-2-16 {
+		t.Errorf("got %d URLs, want %d", len(urls), 254-2-16)
+	}
+	for _, u := range urls {
+		host := strings.TrimPrefix(u, "http://192.168.1.")
+		switch host {
+		case "1", "254", "16", "20", "31":
+			t.Errorf("excluded host %s in targets", u)
+		}
+	}
+	if !slices.Contains(urls, "http://192.168.1.15") || !slices.Contains(urls, "http://192.168.1.32") {
+		t.Error("hosts just outside the excluded sub-range should remain")
+	}
+}
+
+func TestExpandTargets_InvalidExclusion(t *testing.T) {
+	if _, err := ExpandTargets("192.168.1.0/24", "", "http", 65536, []string{"gateway"}); err == nil {
+		t.Error("expected error for non-IP exclusion")
+	}
+}
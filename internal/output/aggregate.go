@@ -0,0 +1,42 @@
+package output
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// Aggregate tracks overall progress of a multi-target scan: targets
+// finished out of the total and requests sent across all of them. Each
+// target's Progress reports into it (see SetParent) and shows it beside
+// the target's own counts. It is safe for concurrent use.
+type Aggregate struct {
+	targets  int
+	done     atomic.Int64
+	requests atomic.Int64
+}
+
+// NewAggregate creates an aggregate for a scan of targets targets.
+func NewAggregate(targets int) *Aggregate {
+	return &Aggregate{targets: targets}
+}
+
+// TargetDone records that a target finished, successfully or not.
+func (a *Aggregate) TargetDone() {
+	a.done.Add(1)
+}
+
+// TargetsDone returns the number of finished targets.
+func (a *Aggregate) TargetsDone() int {
+	return int(a.done.Load())
+}
+
+// Requests returns the number of requests completed across all targets.
+func (a *Aggregate) Requests() int64 {
+	return a.requests.Load()
+}
+
+// tag is the progress line suffix, e.g. " [target 2/5, 1234 req total]".
+func (a *Aggregate) tag() string {
+	current := min(a.TargetsDone()+1, a.targets)
+	return fmt.Sprintf(" [target %d/%d, %d req total]", current, a.targets, a.Requests())
+}
@@ -0,0 +1,179 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+// BaselineEntry is one result of a previous run, as read back from its
+// JSON output (--baseline).
+type BaselineEntry struct {
+	Method        string `json:"method"`
+	Host          string `json:"host,omitempty"`
+	URL           string `json:"url"`
+	StatusCode    int    `json:"status"`
+	ContentLength int64  `json:"size"`
+}
+
+// NewBaselineEntry returns the comparable part of result.
+func NewBaselineEntry(result *scanner.ScanResult) BaselineEntry {
+	return BaselineEntry{
+		Method:        result.Method,
+		Host:          result.Host,
+		URL:           result.URL,
+		StatusCode:    result.StatusCode,
+		ContentLength: result.ContentLength,
+	}
+}
+
+// key identifies the request behind an entry.
+func (e BaselineEntry) key() string {
+	return e.method() + " " + e.Host + " " + e.URL
+}
+
+func (e BaselineEntry) method() string {
+	if e.Method == "" {
+		return "GET"
+	}
+	return e.Method
+}
+
+// Baseline holds the results of a previous run to diff a new one against.
+type Baseline struct {
+	entries []BaselineEntry
+}
+
+// LoadBaseline reads a previous run's JSON output: either the array
+// written by -f json or the JSON Lines written with --append.
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var entries []BaselineEntry
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &entries); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+	} else {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		for {
+			var e BaselineEntry
+			if err := dec.Decode(&e); err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, fmt.Errorf("parsing %s: %w", path, err)
+			}
+			entries = append(entries, e)
+		}
+	}
+	for _, e := range entries {
+		if e.URL == "" {
+			return nil, fmt.Errorf("%s: results need a \"url\" field", path)
+		}
+	}
+	return &Baseline{entries: entries}, nil
+}
+
+// Change is a result reported by both runs with a different status or size.
+type Change struct {
+	Old, New BaselineEntry
+}
+
+// Diff lists how a run's results differ from the baseline.
+type Diff struct {
+	Added   []BaselineEntry // reported now, not before
+	Removed []BaselineEntry // reported before, not now
+	Changed []Change
+}
+
+// Empty reports whether the runs reported the same results.
+func (d Diff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Compare diffs current, the results of a scan of target, against the
+// baseline entries under target. Each list is sorted by method and URL.
+func (b *Baseline) Compare(target string, current []BaselineEntry) Diff {
+	before := make(map[string]BaselineEntry)
+	for _, e := range b.entries {
+		if underTarget(e.URL, target) {
+			before[e.key()] = e
+		}
+	}
+
+	var d Diff
+	seen := make(map[string]bool, len(current))
+	for _, e := range current {
+		k := e.key()
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		old, ok := before[k]
+		switch {
+		case !ok:
+			d.Added = append(d.Added, e)
+		case old.StatusCode != e.StatusCode || old.ContentLength != e.ContentLength:
+			d.Changed = append(d.Changed, Change{Old: old, New: e})
+		}
+	}
+	for k, e := range before {
+		if !seen[k] {
+			d.Removed = append(d.Removed, e)
+		}
+	}
+
+	sortEntries := func(entries []BaselineEntry) {
+		sort.Slice(entries, func(i, j int) bool { return entries[i].key() < entries[j].key() })
+	}
+	sortEntries(d.Added)
+	sortEntries(d.Removed)
+	sort.Slice(d.Changed, func(i, j int) bool { return d.Changed[i].New.key() < d.Changed[j].New.key() })
+	return d
+}
+
+// underTarget reports whether rawURL lies under the scan target, so one
+// baseline can cover a multi-target run.
+func underTarget(rawURL, target string) bool {
+	base := strings.TrimRight(target, "/")
+	return rawURL == base || strings.HasPrefix(rawURL, base+"/") || strings.HasPrefix(rawURL, base+"?")
+}
+
+// WriteDiff prints d as the diff section of target's footer; source names
+// the baseline file.
+func WriteDiff(w io.Writer, source, target string, d Diff) error {
+	var b strings.Builder
+	if d.Empty() {
+		fmt.Fprintf(&b, "\n[*] No changes since %s for %s\n", source, target)
+	} else {
+		fmt.Fprintf(&b, "\n[*] Changes since %s for %s: %d added, %d removed, %d changed\n",
+			source, target, len(d.Added), len(d.Removed), len(d.Changed))
+	}
+	for _, e := range d.Added {
+		fmt.Fprintf(&b, "    + %-7s %d %8dB  %s\n", e.method(), e.StatusCode, e.ContentLength, diffTarget(e))
+	}
+	for _, e := range d.Removed {
+		fmt.Fprintf(&b, "    - %-7s %d %8dB  %s\n", e.method(), e.StatusCode, e.ContentLength, diffTarget(e))
+	}
+	for _, c := range d.Changed {
+		fmt.Fprintf(&b, "    ~ %-7s %d -> %d, %dB -> %dB  %s\n", c.New.method(),
+			c.Old.StatusCode, c.New.StatusCode, c.Old.ContentLength, c.New.ContentLength, diffTarget(c.New))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func diffTarget(e BaselineEntry) string {
+	if e.Host != "" {
+		return "[" + e.Host + "] " + e.URL
+	}
+	return e.URL
+}
@@ -0,0 +1,106 @@
+package output
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+func TestBaselineCompare(t *testing.T) {
+	b := &Baseline{entries: []BaselineEntry{
+		{Method: "GET", URL: "https://a.test/admin", StatusCode: 200, ContentLength: 100},
+		{Method: "GET", URL: "https://a.test/old", StatusCode: 403, ContentLength: 50},
+		{Method: "GET", URL: "https://a.test/login", StatusCode: 200, ContentLength: 300},
+		{Method: "GET", URL: "https://a.test/api", StatusCode: 401, ContentLength: 20},
+		{Method: "GET", URL: "https://b.test/other", StatusCode: 200, ContentLength: 1},
+	}}
+	current := []BaselineEntry{
+		{Method: "GET", URL: "https://a.test/admin", StatusCode: 200, ContentLength: 100},
+		{Method: "GET", URL: "https://a.test/login", StatusCode: 200, ContentLength: 320},
+		{Method: "GET", URL: "https://a.test/api", StatusCode: 200, ContentLength: 20},
+		{Method: "GET", URL: "https://a.test/backup.zip", StatusCode: 200, ContentLength: 9000},
+		{Method: "POST", URL: "https://a.test/admin", StatusCode: 405, ContentLength: 0},
+	}
+
+	d := b.Compare("https://a.test/", current)
+
+	var added []string
+	for _, e := range d.Added {
+		added = append(added, e.method()+" "+e.URL)
+	}
+	if got, want := strings.Join(added, ","), "GET https://a.test/backup.zip,POST https://a.test/admin"; got != want {
+		t.Errorf("added = %s, want %s", got, want)
+	}
+	// b.test/other belongs to another target and must not show as removed.
+	if len(d.Removed) != 1 || d.Removed[0].URL != "https://a.test/old" {
+		t.Errorf("removed = %+v, want only /old", d.Removed)
+	}
+	if len(d.Changed) != 2 {
+		t.Fatalf("changed = %+v, want /api and /login", d.Changed)
+	}
+	if c := d.Changed[0]; c.New.URL != "https://a.test/api" || c.Old.StatusCode != 401 || c.New.StatusCode != 200 {
+		t.Errorf("status change = %+v", c)
+	}
+	if c := d.Changed[1]; c.New.URL != "https://a.test/login" || c.Old.ContentLength != 300 || c.New.ContentLength != 320 {
+		t.Errorf("size change = %+v", c)
+	}
+}
+
+func TestBaselineCompareUnchanged(t *testing.T) {
+	b := &Baseline{entries: []BaselineEntry{{URL: "https://a.test/admin", StatusCode: 200, ContentLength: 100}}}
+	d := b.Compare("https://a.test", []BaselineEntry{{Method: "GET", URL: "https://a.test/admin", StatusCode: 200, ContentLength: 100}})
+	if !d.Empty() {
+		t.Errorf("expected no changes, got %+v", d)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteDiff(&buf, "prev.json", "https://a.test", d); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "No changes since prev.json") {
+		t.Errorf("unexpected diff output: %q", buf.String())
+	}
+}
+
+func TestLoadBaseline(t *testing.T) {
+	dir := t.TempDir()
+	result := &scanner.ScanResult{Method: "GET", URL: "https://a.test/admin", Path: "admin", StatusCode: 200, ContentLength: 42}
+
+	for _, appendMode := range []bool{false, true} {
+		path := filepath.Join(dir, "prev.json")
+		w, err := NewJSONWriter(path, appendMode, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for range 2 {
+			if err := w.WriteResult(result); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.WriteFooter(Stats{}); err != nil {
+			t.Fatal(err)
+		}
+		w.Close()
+
+		b, err := LoadBaseline(path)
+		if err != nil {
+			t.Fatalf("append=%v: %v", appendMode, err)
+		}
+		if len(b.entries) != 2 || b.entries[0] != NewBaselineEntry(result) {
+			t.Errorf("append=%v: entries = %+v", appendMode, b.entries)
+		}
+		os.Remove(path)
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(bad, []byte(`[{"path":"admin","status":200}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadBaseline(bad); err == nil {
+		t.Error("expected an error for results without a url")
+	}
+}
@@ -0,0 +1,50 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+// BodyStore writes response bodies of reported results to a directory
+// (--save-bodies), one file per result named by a hash of its URL.
+type BodyStore struct {
+	dir     string
+	maxSize int64 // bytes written per body; larger bodies are truncated (0 = no cap)
+}
+
+// NewBodyStore creates dir if needed and returns a store writing into it.
+// Each saved body is capped at maxSize bytes (0 = no cap).
+func NewBodyStore(dir string, maxSize int64) (*BodyStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating body directory: %w", err)
+	}
+	return &BodyStore{dir: dir, maxSize: maxSize}, nil
+}
+
+// Save writes result.Body and records the file in result.BodyFile. The
+// file name is the SHA-256 of the URL, plus the method and Host header
+// when they differ from a plain GET, so method and vhost fuzzing don't
+// overwrite each other.
+func (s *BodyStore) Save(result *scanner.ScanResult) error {
+	key := result.URL
+	if (result.Method != "" && result.Method != "GET") || result.Host != "" {
+		key = result.Method + " " + result.Host + " " + key
+	}
+	sum := sha256.Sum256([]byte(key))
+	path := filepath.Join(s.dir, hex.EncodeToString(sum[:])+".body")
+
+	body := result.Body
+	if s.maxSize > 0 && int64(len(body)) > s.maxSize {
+		body = body[:s.maxSize]
+	}
+	if err := os.WriteFile(path, body, 0644); err != nil {
+		return fmt.Errorf("saving body of %s: %w", result.URL, err)
+	}
+	result.BodyFile = path
+	return nil
+}
@@ -0,0 +1,39 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+func TestBodyStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "bodies")
+	store, err := NewBodyStore(dir, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	get := &scanner.ScanResult{Method: "GET", URL: "http://x/admin", Body: []byte("hello world")}
+	post := &scanner.ScanResult{Method: "POST", URL: "http://x/admin", Body: []byte("post")}
+	for _, r := range []*scanner.ScanResult{get, post} {
+		if err := store.Save(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if get.BodyFile == "" || get.BodyFile == post.BodyFile {
+		t.Fatalf("BodyFile = %q / %q, want distinct files per method", get.BodyFile, post.BodyFile)
+	}
+	if filepath.Dir(get.BodyFile) != dir {
+		t.Errorf("body saved to %s, want inside %s", get.BodyFile, dir)
+	}
+	data, err := os.ReadFile(get.BodyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "hello" {
+		t.Errorf("saved body = %q, want it truncated to 5 bytes", data)
+	}
+}
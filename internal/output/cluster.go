@@ -0,0 +1,78 @@
+package output
+
+import (
+	"sort"
+
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+// clusterKey groups results by status and structural shape, the same way
+// the duplicate filter's fuzzy key does: exact line count and word count
+// in buckets of 5. Pages that embed the requested path differ in size and
+// hash but keep this shape.
+type clusterKey struct {
+	statusCode int
+	lineCount  int
+	wordBucket int
+}
+
+// Cluster is a group of similar results.
+type Cluster struct {
+	StatusCode int
+	Count      int
+	Size       int64 // mean content length
+}
+
+// Clusterer groups written results into clusters of similar responses for
+// the footer, so a soft-404 the filters missed shows up as one large
+// cluster. Only a count and size total per cluster are kept. The zero
+// value is ready to use; it is not safe for concurrent use.
+type Clusterer struct {
+	groups map[clusterKey]*clusterTotals
+}
+
+type clusterTotals struct {
+	count int
+	size  int64
+}
+
+// Add records result in its cluster.
+func (c *Clusterer) Add(result *scanner.ScanResult) {
+	if c.groups == nil {
+		c.groups = make(map[clusterKey]*clusterTotals)
+	}
+	key := clusterKey{
+		statusCode: result.StatusCode,
+		lineCount:  result.LineCount,
+		wordBucket: result.WordCount / 5,
+	}
+	g, ok := c.groups[key]
+	if !ok {
+		g = &clusterTotals{}
+		c.groups[key] = g
+	}
+	g.count++
+	g.size += result.ContentLength
+}
+
+// Clusters returns the clusters with at least minCount results, largest
+// first, then by status and size.
+func (c *Clusterer) Clusters(minCount int) []Cluster {
+	var out []Cluster
+	for key, g := range c.groups {
+		if g.count < minCount {
+			continue
+		}
+		out = append(out, Cluster{StatusCode: key.statusCode, Count: g.count, Size: g.size / int64(g.count)})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		if out[i].StatusCode != out[j].StatusCode {
+			return out[i].StatusCode < out[j].StatusCode
+		}
+		return out[i].Size < out[j].Size
+	})
+	return out
+}
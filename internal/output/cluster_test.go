@@ -0,0 +1,45 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+func TestClusterer(t *testing.T) {
+	var c Clusterer
+	add := func(n, status, lines, words int, size int64) {
+		for i := 0; i < n; i++ {
+			c.Add(&scanner.ScanResult{StatusCode: status, LineCount: lines, WordCount: words + i%3, ContentLength: size + int64(i)})
+		}
+	}
+	add(6, 200, 40, 300, 4200) // catch-all page embedding the path
+	add(4, 403, 7, 20, 150)
+	add(2, 200, 12, 50, 900)   // too small to report
+	add(3, 200, 40, 800, 9000) // same lines, different word bucket
+
+	got := c.Clusters(3)
+	want := []Cluster{
+		{StatusCode: 200, Count: 6, Size: 4202},
+		{StatusCode: 403, Count: 4, Size: 151},
+		{StatusCode: 200, Count: 3, Size: 9001},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d clusters %v, want %v", len(got), got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("cluster %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if n := len(c.Clusters(1)); n != 4 {
+		t.Errorf("Clusters(1) returned %d clusters, want 4", n)
+	}
+}
+
+func TestClustererEmpty(t *testing.T) {
+	var c Clusterer
+	if got := c.Clusters(1); len(got) != 0 {
+		t.Errorf("expected no clusters, got %v", got)
+	}
+}
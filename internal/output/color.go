@@ -0,0 +1,55 @@
+package output
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// colorNames are the colors accepted by --color-scheme.
+var colorNames = map[string]string{
+	"green":   colorGreen,
+	"cyan":    colorCyan,
+	"yellow":  colorYellow,
+	"red":     colorRed,
+	"blue":    colorBlue,
+	"magenta": colorMagenta,
+	"white":   colorWhite,
+	"none":    "",
+}
+
+// ParseColorScheme parses a --color-scheme value such as "200:green,403:red"
+// into a map from status code to ANSI color. Codes not listed keep the
+// default color of their class. An empty value yields a nil map.
+func ParseColorScheme(s string) (map[int]string, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	scheme := make(map[int]string)
+	for _, entry := range strings.Split(s, ",") {
+		code, name, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid entry %q (want code:color)", entry)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(code))
+		if err != nil || n < 100 || n > 599 {
+			return nil, fmt.Errorf("invalid status code %q", code)
+		}
+		color, ok := colorNames[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("unknown color %q (want one of: %s)", name, strings.Join(knownColors(), ", "))
+		}
+		scheme[n] = color
+	}
+	return scheme, nil
+}
+
+func knownColors() []string {
+	names := make([]string, 0, len(colorNames))
+	for name := range colorNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
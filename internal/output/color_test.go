@@ -0,0 +1,53 @@
+package output
+
+import "testing"
+
+func TestParseColorScheme(t *testing.T) {
+	scheme, err := ParseColorScheme("200:green, 403:RED,500:none")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[int]string{200: colorGreen, 403: colorRed, 500: ""}
+	if len(scheme) != len(want) {
+		t.Fatalf("got %v, want %v", scheme, want)
+	}
+	for code, color := range want {
+		if got, ok := scheme[code]; !ok || got != color {
+			t.Errorf("scheme[%d] = %q, want %q", code, got, color)
+		}
+	}
+
+	if scheme, err := ParseColorScheme(""); err != nil || scheme != nil {
+		t.Errorf("empty value = %v, %v; want nil, nil", scheme, err)
+	}
+	for _, bad := range []string{"200", "abc:red", "42:red", "200:purple", "200:green,"} {
+		if _, err := ParseColorScheme(bad); err == nil {
+			t.Errorf("ParseColorScheme(%q) should fail", bad)
+		}
+	}
+}
+
+func TestColorForStatusScheme(t *testing.T) {
+	w := &TextWriter{scheme: map[int]string{403: colorRed, 200: ""}}
+	tests := []struct {
+		code int
+		want string
+	}{
+		{403, colorRed},    // overridden
+		{401, colorYellow}, // same class, default
+		{200, ""},          // overridden to none
+		{204, colorGreen},
+		{301, colorCyan},
+		{503, colorRed},
+	}
+	for _, tt := range tests {
+		if got := w.colorForStatus(tt.code); got != tt.want {
+			t.Errorf("colorForStatus(%d) = %q, want %q", tt.code, got, tt.want)
+		}
+	}
+
+	w.noColor = true
+	if got := w.colorForStatus(403); got != "" {
+		t.Errorf("--no-color should win over the scheme, got %q", got)
+	}
+}
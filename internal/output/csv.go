@@ -4,38 +4,58 @@ import (
 	"encoding/csv"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
+	"slices"
 
 	"github.com/maxvaer/dirfuzz/internal/scanner"
 )
 
 // CSVWriter writes results in CSV format.
 type CSVWriter struct {
-	w      *csv.Writer
-	closer io.Closer
+	w          *csv.Writer
+	closer     io.Closer
+	skipHeader bool     // appending to a file that already has one
+	headers    []string // --show-headers names, one extra column each
+	fields     []string // --fields selection; nil = the standard columns
 }
 
-// NewCSVWriter creates a CSV output writer.
-func NewCSVWriter(outputFile string) (*CSVWriter, error) {
+// NewCSVWriter creates a CSV output writer. appendMode keeps an existing
+// outputFile and adds rows to it. If fields is non-empty, the columns are
+// just those fields (from OutputFields), in that order. Each of headers
+// gets its own column after them.
+func NewCSVWriter(outputFile string, appendMode bool, headers, fields []string) (*CSVWriter, error) {
 	var w io.Writer = os.Stdout
 	var closer io.Closer
+	var nonEmpty bool
 	if outputFile != "" {
-		f, err := os.Create(outputFile)
+		f, existing, err := openOutput(outputFile, appendMode)
 		if err != nil {
 			return nil, err
 		}
 		w = f
 		closer = f
+		nonEmpty = existing
 	}
-	return &CSVWriter{w: csv.NewWriter(w), closer: closer}, nil
+	return &CSVWriter{w: csv.NewWriter(w), closer: closer, skipHeader: nonEmpty, headers: headers, fields: fields}, nil
 }
 
 func (c *CSVWriter) WriteHeader() error {
-	return c.w.Write([]string{"method", "host", "url", "path", "status", "size", "redirect"})
+	if c.skipHeader {
+		return nil
+	}
+	columns := []string{"method", "host", "url", "path", "status", "size", "redirect", "duration"}
+	if len(c.fields) > 0 {
+		columns = slices.Clone(c.fields)
+	}
+	return c.w.Write(append(columns, c.headers...))
 }
 
 func (c *CSVWriter) WriteResult(result *scanner.ScanResult) error {
-	return c.w.Write([]string{
+	if len(c.fields) > 0 {
+		return c.w.Write(c.withHeaders(fieldRecord(c.fields, result), result))
+	}
+	record := []string{
 		result.Method,
 		result.Host,
 		result.URL,
@@ -43,7 +63,17 @@ func (c *CSVWriter) WriteResult(result *scanner.ScanResult) error {
 		fmt.Sprintf("%d", result.StatusCode),
 		fmt.Sprintf("%d", result.ContentLength),
 		result.RedirectURL,
-	})
+		fmt.Sprintf("%d", result.Duration.Milliseconds()),
+	}
+	return c.w.Write(c.withHeaders(record, result))
+}
+
+// withHeaders appends the --show-headers columns to record.
+func (c *CSVWriter) withHeaders(record []string, result *scanner.ScanResult) []string {
+	for _, name := range c.headers {
+		record = append(record, result.Headers[http.CanonicalHeaderKey(name)])
+	}
+	return record
 }
 
 func (c *CSVWriter) WriteFooter(_ Stats) error {
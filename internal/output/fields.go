@@ -0,0 +1,93 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+// OutputFields are the result fields --fields can select for CSV and JSON
+// output.
+var OutputFields = []string{"method", "host", "url", "path", "status", "size", "words", "lines", "redirect", "title", "content_type", "duration"}
+
+// fieldValues extracts each of OutputFields from a result. Durations are
+// in milliseconds.
+var fieldValues = map[string]func(*scanner.ScanResult) any{
+	"method":       func(r *scanner.ScanResult) any { return r.Method },
+	"host":         func(r *scanner.ScanResult) any { return r.Host },
+	"url":          func(r *scanner.ScanResult) any { return r.URL },
+	"path":         func(r *scanner.ScanResult) any { return r.Path },
+	"status":       func(r *scanner.ScanResult) any { return r.StatusCode },
+	"size":         func(r *scanner.ScanResult) any { return r.ContentLength },
+	"words":        func(r *scanner.ScanResult) any { return r.WordCount },
+	"lines":        func(r *scanner.ScanResult) any { return r.LineCount },
+	"redirect":     func(r *scanner.ScanResult) any { return r.RedirectURL },
+	"title":        func(r *scanner.ScanResult) any { return r.Title },
+	"content_type": func(r *scanner.ScanResult) any { return r.ContentType },
+	"duration":     func(r *scanner.ScanResult) any { return r.Duration.Milliseconds() },
+}
+
+// ParseFields normalizes --fields names and checks them against
+// OutputFields.
+func ParseFields(names []string) ([]string, error) {
+	fields := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(OutputFields, name) {
+			return nil, fmt.Errorf("unknown field %q (use %s)", name, strings.Join(OutputFields, ", "))
+		}
+		fields = append(fields, name)
+	}
+	return fields, nil
+}
+
+// fieldRecord returns the CSV cells of fields for result.
+func fieldRecord(fields []string, result *scanner.ScanResult) []string {
+	record := make([]string, len(fields))
+	for i, name := range fields {
+		record[i] = fmt.Sprint(fieldValues[name](result))
+	}
+	return record
+}
+
+// fieldEntry is a JSON object holding the selected fields of a result, in
+// the order they were selected.
+type fieldEntry struct {
+	names  []string
+	values []any
+}
+
+func newFieldEntry(fields []string, result *scanner.ScanResult) fieldEntry {
+	values := make([]any, len(fields))
+	for i, name := range fields {
+		values[i] = fieldValues[name](result)
+	}
+	return fieldEntry{names: fields, values: values}
+}
+
+func (e fieldEntry) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, name := range e.names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(e.values[i])
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
@@ -0,0 +1,181 @@
+package output
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/maxvaer/dirfuzz/pkg/version"
+)
+
+type harHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harRequest struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []struct{}  `json:"cookies"`
+	Headers     []harHeader `json:"headers"`
+	QueryString []harHeader `json:"queryString"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int         `json:"bodySize"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+}
+
+type harResponse struct {
+	Status      int         `json:"status"`
+	StatusText  string      `json:"statusText"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []struct{}  `json:"cookies"`
+	Headers     []harHeader `json:"headers"`
+	Content     harContent  `json:"content"`
+	RedirectURL string      `json:"redirectURL"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int64       `json:"bodySize"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+}
+
+// HARWriter records HTTP exchanges to a HAR 1.2 file. Entries are streamed
+// to disk as they arrive, so memory use stays flat regardless of scan size.
+// Bodies are not stored, only their sizes. It is safe for concurrent use.
+type HARWriter struct {
+	mu    sync.Mutex
+	f     *os.File
+	w     *bufio.Writer
+	count int
+	err   error
+}
+
+// NewHARWriter creates path and writes the HAR preamble.
+func NewHARWriter(path string) (*HARWriter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	h := &HARWriter{f: f, w: bufio.NewWriter(f)}
+	creator, _ := json.Marshal(map[string]string{"name": "dirfuzz", "version": version.Version})
+	fmt.Fprintf(h.w, `{"log":{"version":"1.2","creator":%s,"entries":[`, creator)
+	return h, nil
+}
+
+// Record appends one request/response pair. body is the decoded response
+// body; only its length is kept.
+func (h *HARWriter) Record(req *http.Request, resp *http.Response, body []byte, start time.Time, elapsed time.Duration) {
+	ms := float64(elapsed.Microseconds()) / 1000
+	reqHeaders := harHeaders(req.Header)
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	reqHeaders = append([]harHeader{{Name: "Host", Value: host}}, reqHeaders...)
+
+	query := []harHeader{}
+	for name, values := range req.URL.Query() {
+		for _, v := range values {
+			query = append(query, harHeader{Name: name, Value: v})
+		}
+	}
+
+	entry := harEntry{
+		StartedDateTime: start.Format("2006-01-02T15:04:05.000Z07:00"),
+		Time:            ms,
+		Request: harRequest{
+			Method:      req.Method,
+			URL:         req.URL.String(),
+			HTTPVersion: req.Proto,
+			Cookies:     []struct{}{},
+			Headers:     reqHeaders,
+			QueryString: query,
+			HeadersSize: -1,
+			BodySize:    0,
+		},
+		Response: harResponse{
+			Status:      resp.StatusCode,
+			StatusText:  http.StatusText(resp.StatusCode),
+			HTTPVersion: resp.Proto,
+			Cookies:     []struct{}{},
+			Headers:     harHeaders(resp.Header),
+			Content:     harContent{Size: len(body), MimeType: resp.Header.Get("Content-Type")},
+			RedirectURL: resp.Header.Get("Location"),
+			HeadersSize: -1,
+			BodySize:    resp.ContentLength,
+		},
+		Timings: harTimings{Send: 0, Wait: ms, Receive: 0},
+	}
+
+	data, err := json.Marshal(entry)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.err != nil {
+		return
+	}
+	if err != nil {
+		h.err = err
+		return
+	}
+	if h.count > 0 {
+		h.w.WriteByte(',')
+	}
+	if _, err := h.w.Write(data); err != nil {
+		h.err = err
+	}
+	h.count++
+}
+
+// Close finishes the JSON document and closes the file. It returns the
+// first error encountered while recording, if any.
+func (h *HARWriter) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.w.WriteString("]}}\n")
+	if err := h.w.Flush(); err != nil && h.err == nil {
+		h.err = err
+	}
+	if err := h.f.Close(); err != nil && h.err == nil {
+		h.err = err
+	}
+	return h.err
+}
+
+// harHeaders flattens h into name/value pairs sorted by name.
+func harHeaders(h http.Header) []harHeader {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	out := make([]harHeader, 0, len(names))
+	for _, name := range names {
+		for _, v := range h[name] {
+			out = append(out, harHeader{Name: name, Value: v})
+		}
+	}
+	return out
+}
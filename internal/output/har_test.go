@@ -0,0 +1,91 @@
+package output
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestHARWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scan.har")
+	h, err := NewHARWriter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const n = 25
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			u, _ := url.Parse("http://example.com/admin?x=1")
+			req := &http.Request{Method: "GET", URL: u, Proto: "HTTP/1.1", Header: http.Header{"User-Agent": {"dirfuzz/1.0"}}}
+			resp := &http.Response{
+				StatusCode:    301,
+				Proto:         "HTTP/1.1",
+				Header:        http.Header{"Location": {"/admin/"}, "Content-Type": {"text/html"}},
+				ContentLength: 5,
+			}
+			h.Record(req, resp, []byte("hello"), time.Now(), 12*time.Millisecond)
+		}()
+	}
+	wg.Wait()
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var har struct {
+		Log struct {
+			Version string
+			Entries []harEntry
+		}
+	}
+	if err := json.Unmarshal(data, &har); err != nil {
+		t.Fatalf("HAR is not valid JSON: %v", err)
+	}
+	if har.Log.Version != "1.2" {
+		t.Errorf("version = %q, want 1.2", har.Log.Version)
+	}
+	if len(har.Log.Entries) != n {
+		t.Fatalf("got %d entries, want %d", len(har.Log.Entries), n)
+	}
+
+	e := har.Log.Entries[0]
+	if e.Request.Method != "GET" || e.Request.URL != "http://example.com/admin?x=1" {
+		t.Errorf("request = %+v", e.Request)
+	}
+	if e.Request.Headers[0] != (harHeader{Name: "Host", Value: "example.com"}) {
+		t.Errorf("expected Host as first request header, got %+v", e.Request.Headers)
+	}
+	if e.Response.Status != 301 || e.Response.RedirectURL != "/admin/" || e.Response.Content.Size != 5 {
+		t.Errorf("response = %+v", e.Response)
+	}
+	if e.Time != 12 {
+		t.Errorf("time = %v, want 12", e.Time)
+	}
+}
+
+func TestHARWriter_Empty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.har")
+	h, err := NewHARWriter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if !json.Valid(data) {
+		t.Errorf("empty HAR is not valid JSON: %s", data)
+	}
+}
@@ -0,0 +1,160 @@
+package output
+
+import (
+	"html/template"
+	"io"
+	"os"
+	"time"
+
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+type htmlRow struct {
+	Method      string
+	Host        string
+	StatusCode  int
+	StatusClass string
+	Size        int64
+	DurationMs  int64
+	URL         string
+	RedirectURL string
+}
+
+type htmlReport struct {
+	Generated string
+	Stats     Stats
+	Duration  string
+	Rows      []htmlRow
+}
+
+// HTMLWriter buffers results and writes a self-contained HTML report with
+// a sortable table when WriteFooter is called.
+type HTMLWriter struct {
+	w      io.Writer
+	closer io.Closer
+	rows   []htmlRow
+}
+
+// NewHTMLWriter creates an HTML report writer.
+func NewHTMLWriter(outputFile string) (*HTMLWriter, error) {
+	var w io.Writer = os.Stdout
+	var closer io.Closer
+	if outputFile != "" {
+		f, err := os.Create(outputFile)
+		if err != nil {
+			return nil, err
+		}
+		w = f
+		closer = f
+	}
+	return &HTMLWriter{w: w, closer: closer}, nil
+}
+
+func (h *HTMLWriter) WriteHeader() error { return nil }
+
+func (h *HTMLWriter) WriteResult(result *scanner.ScanResult) error {
+	h.rows = append(h.rows, htmlRow{
+		Method:      result.Method,
+		Host:        result.Host,
+		StatusCode:  result.StatusCode,
+		StatusClass: statusClass(result.StatusCode),
+		Size:        result.ContentLength,
+		DurationMs:  result.Duration.Milliseconds(),
+		URL:         result.URL,
+		RedirectURL: result.RedirectURL,
+	})
+	return nil
+}
+
+func (h *HTMLWriter) WriteFooter(stats Stats) error {
+	return htmlTemplate.Execute(h.w, htmlReport{
+		Generated: time.Now().Format(time.RFC1123),
+		Stats:     stats,
+		Duration:  stats.Duration.Round(time.Millisecond).String(),
+		Rows:      h.rows,
+	})
+}
+
+func (h *HTMLWriter) Close() error {
+	if h.closer != nil {
+		return h.closer.Close()
+	}
+	return nil
+}
+
+// statusClass returns the CSS class used to color a row by status class.
+func statusClass(code int) string {
+	switch {
+	case code >= 200 && code < 300:
+		return "s2xx"
+	case code >= 300 && code < 400:
+		return "s3xx"
+	case code >= 400 && code < 500:
+		return "s4xx"
+	case code >= 500:
+		return "s5xx"
+	default:
+		return ""
+	}
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>dirfuzz report</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #222; }
+h1 { font-size: 1.4em; }
+.stats span { display: inline-block; margin-right: 1.5em; }
+table { border-collapse: collapse; width: 100%; margin-top: 1em; font-size: 0.9em; }
+th, td { text-align: left; padding: 4px 8px; border-bottom: 1px solid #ddd; }
+th { cursor: pointer; background: #f4f4f4; user-select: none; }
+td.num { text-align: right; font-variant-numeric: tabular-nums; }
+tr.s2xx td.status { color: #1a7f37; }
+tr.s3xx td.status { color: #0969da; }
+tr.s4xx td.status { color: #9a6700; }
+tr.s5xx td.status { color: #cf222e; }
+</style>
+</head>
+<body>
+<h1>dirfuzz report</h1>
+<div class="stats">
+<span>Generated: {{.Generated}}</span>
+<span>Requests: {{.Stats.TotalRequests}}</span>
+<span>Found: {{len .Rows}}</span>
+<span>Filtered: {{.Stats.FilteredCount}}</span>
+<span>Errors: {{.Stats.ErrorCount}}</span>
+<span>Duration: {{.Duration}}</span>
+<span>Rate: {{printf "%.1f" .Stats.RequestsPerSec}} req/s</span>
+</div>
+<table id="results">
+<thead>
+<tr><th data-type="num">Status</th><th data-type="num">Size</th><th data-type="num">Time (ms)</th><th>Method</th><th>Host</th><th>URL</th><th>Redirect</th></tr>
+</thead>
+<tbody>
+{{- range .Rows}}
+<tr class="{{.StatusClass}}"><td class="num status">{{.StatusCode}}</td><td class="num">{{.Size}}</td><td class="num">{{.DurationMs}}</td><td>{{.Method}}</td><td>{{.Host}}</td><td><a href="{{.URL}}">{{.URL}}</a></td><td>{{.RedirectURL}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+<script>
+document.querySelectorAll("#results th").forEach(function (th, col) {
+  var asc = true;
+  th.addEventListener("click", function () {
+    var tbody = document.querySelector("#results tbody");
+    var rows = Array.prototype.slice.call(tbody.rows);
+    var num = th.dataset.type === "num";
+    rows.sort(function (a, b) {
+      var x = a.cells[col].textContent, y = b.cells[col].textContent;
+      var c = num ? (parseFloat(x) || 0) - (parseFloat(y) || 0) : x.localeCompare(y);
+      return asc ? c : -c;
+    });
+    asc = !asc;
+    rows.forEach(function (r) { tbody.appendChild(r); });
+  });
+});
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,63 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+func TestHTMLWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.html")
+	w, err := NewHTMLWriter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := []*scanner.ScanResult{
+		{Method: "GET", URL: "http://example.com/admin", Path: "admin", StatusCode: 200, ContentLength: 1532},
+		{Method: "GET", URL: "http://example.com/images", Path: "images", StatusCode: 301, RedirectURL: "http://example.com/images/"},
+		{Method: "GET", URL: "http://example.com/<script>", Path: "<script>", StatusCode: 403},
+	}
+	if err := w.WriteHeader(); err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		if err := w.WriteResult(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.WriteFooter(Stats{TotalRequests: 100, FilteredCount: 97, Duration: 2 * time.Second}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := string(data)
+
+	for _, tag := range []string{"<html", "</html>", "<body>", "</body>", "<table", "</table>"} {
+		if !strings.Contains(doc, tag) {
+			t.Errorf("missing %s in report", tag)
+		}
+	}
+	if rows := strings.Count(doc, "<tr class="); rows != len(results) {
+		t.Errorf("got %d result rows, want %d", rows, len(results))
+	}
+	if !strings.Contains(doc, "Requests: 100") {
+		t.Error("expected scan stats in report header")
+	}
+	if !strings.Contains(doc, `class="s3xx"`) {
+		t.Error("expected status class on 301 row")
+	}
+	if strings.Contains(doc, "http://example.com/<script>") {
+		t.Error("expected URL to be HTML-escaped")
+	}
+}
@@ -9,41 +9,61 @@ import (
 )
 
 type jsonEntry struct {
-	Method        string `json:"method"`
-	Host          string `json:"host,omitempty"`
-	URL           string `json:"url"`
-	Path          string `json:"path"`
-	StatusCode    int    `json:"status"`
-	ContentLength int64  `json:"size"`
-	RedirectURL   string `json:"redirect,omitempty"`
+	Method        string            `json:"method"`
+	Host          string            `json:"host,omitempty"`
+	URL           string            `json:"url"`
+	Path          string            `json:"path"`
+	StatusCode    int               `json:"status"`
+	ContentLength int64             `json:"size"`
+	RedirectURL   string            `json:"redirect,omitempty"`
+	RedirectChain []string          `json:"redirect_chain,omitempty"`
+	TLSSubject    string            `json:"tls_cn,omitempty"`
+	TLSSANs       []string          `json:"tls_sans,omitempty"`
+	Headers       map[string]string `json:"headers,omitempty"`
+	Title         string            `json:"title,omitempty"`
+	BodyFile      string            `json:"body_file,omitempty"`
+	Truncated     bool              `json:"truncated,omitempty"`
+	Allow         []string          `json:"allow,omitempty"`
+	AcceptsRange  bool              `json:"accepts_range,omitempty"`
+	FullSize      int64             `json:"full_size,omitempty"`
+	WebSocket     bool              `json:"websocket,omitempty"`
+	Slow          bool              `json:"slow,omitempty"`
+	Listing       bool              `json:"listing,omitempty"`
+	DurationMs    int64             `json:"duration_ms"`
 }
 
-// JSONWriter writes results as a JSON array.
+// JSONWriter writes results as a JSON array, or as JSON Lines (one object
+// per line, written immediately) in append mode so successive runs can
+// add to the same file.
 type JSONWriter struct {
 	w       io.Writer
 	closer  io.Closer
-	entries []jsonEntry
+	lines   bool
+	fields  []string // --fields selection; nil = the standard entry
+	entries []any
 }
 
-// NewJSONWriter creates a JSON output writer.
-func NewJSONWriter(outputFile string) (*JSONWriter, error) {
+// NewJSONWriter creates a JSON output writer. appendMode keeps an existing
+// outputFile and switches to JSON Lines. If fields is non-empty, each
+// object holds just those fields (from OutputFields), in that order.
+func NewJSONWriter(outputFile string, appendMode bool, fields []string) (*JSONWriter, error) {
 	var w io.Writer = os.Stdout
 	var closer io.Closer
 	if outputFile != "" {
-		f, err := os.Create(outputFile)
+		f, _, err := openOutput(outputFile, appendMode)
 		if err != nil {
 			return nil, err
 		}
 		w = f
 		closer = f
 	}
-	return &JSONWriter{w: w, closer: closer}, nil
+	return &JSONWriter{w: w, closer: closer, lines: appendMode, fields: fields}, nil
 }
 
 func (j *JSONWriter) WriteHeader() error { return nil }
 
 func (j *JSONWriter) WriteResult(result *scanner.ScanResult) error {
-	j.entries = append(j.entries, jsonEntry{
+	var entry any = jsonEntry{
 		Method:        result.Method,
 		Host:          result.Host,
 		URL:           result.URL,
@@ -51,11 +71,35 @@ func (j *JSONWriter) WriteResult(result *scanner.ScanResult) error {
 		StatusCode:    result.StatusCode,
 		ContentLength: result.ContentLength,
 		RedirectURL:   result.RedirectURL,
-	})
+		RedirectChain: result.RedirectChain,
+		TLSSubject:    result.TLSSubject,
+		TLSSANs:       result.TLSSANs,
+		Headers:       result.Headers,
+		Title:         result.Title,
+		BodyFile:      result.BodyFile,
+		Truncated:     result.Truncated,
+		Allow:         result.Allow,
+		AcceptsRange:  result.AcceptsRange,
+		FullSize:      result.FullSize,
+		WebSocket:     result.WebSocket,
+		Slow:          result.Slow,
+		Listing:       result.Listing,
+		DurationMs:    result.Duration.Milliseconds(),
+	}
+	if len(j.fields) > 0 {
+		entry = newFieldEntry(j.fields, result)
+	}
+	if j.lines {
+		return json.NewEncoder(j.w).Encode(entry)
+	}
+	j.entries = append(j.entries, entry)
 	return nil
 }
 
 func (j *JSONWriter) WriteFooter(stats Stats) error {
+	if j.lines {
+		return nil
+	}
 	enc := json.NewEncoder(j.w)
 	enc.SetIndent("", "  ")
 	return enc.Encode(j.entries)
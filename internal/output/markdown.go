@@ -0,0 +1,63 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+// MarkdownWriter writes results as a GitHub-flavored Markdown table,
+// suitable for pasting into reports.
+type MarkdownWriter struct {
+	w      io.Writer
+	closer io.Closer
+}
+
+// NewMarkdownWriter creates a Markdown output writer.
+func NewMarkdownWriter(outputFile string) (*MarkdownWriter, error) {
+	var w io.Writer = os.Stdout
+	var closer io.Closer
+	if outputFile != "" {
+		f, err := os.Create(outputFile)
+		if err != nil {
+			return nil, err
+		}
+		w = f
+		closer = f
+	}
+	return &MarkdownWriter{w: w, closer: closer}, nil
+}
+
+func (m *MarkdownWriter) WriteHeader() error {
+	_, err := fmt.Fprint(m.w, "| Status | Size | URL | Redirect |\n| ---: | ---: | --- | --- |\n")
+	return err
+}
+
+func (m *MarkdownWriter) WriteResult(result *scanner.ScanResult) error {
+	_, err := fmt.Fprintf(m.w, "| %d | %d | %s | %s |\n",
+		result.StatusCode, result.ContentLength,
+		escapeMarkdownCell(result.URL), escapeMarkdownCell(result.RedirectURL))
+	return err
+}
+
+func (m *MarkdownWriter) WriteFooter(stats Stats) error {
+	_, err := fmt.Fprintf(m.w, "\n**Requests:** %d · **Filtered:** %d · **Errors:** %d · **Duration:** %s\n",
+		stats.TotalRequests, stats.FilteredCount, stats.ErrorCount, stats.Duration.Round(time.Millisecond))
+	return err
+}
+
+func (m *MarkdownWriter) Close() error {
+	if m.closer != nil {
+		return m.closer.Close()
+	}
+	return nil
+}
+
+// escapeMarkdownCell escapes characters that would break a table cell.
+func escapeMarkdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
@@ -0,0 +1,70 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+func TestMarkdownWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.md")
+	w, err := NewMarkdownWriter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := []*scanner.ScanResult{
+		{URL: "http://example.com/admin", StatusCode: 200, ContentLength: 1532},
+		{URL: "http://example.com/images", StatusCode: 301, RedirectURL: "http://example.com/images/"},
+		{URL: "http://example.com/a|b", StatusCode: 403},
+	}
+	if err := w.WriteHeader(); err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		if err := w.WriteResult(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.WriteFooter(Stats{TotalRequests: 100, FilteredCount: 97, Duration: 2 * time.Second}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+
+	if lines[0] != "| Status | Size | URL | Redirect |" {
+		t.Errorf("header row = %q", lines[0])
+	}
+	if lines[1] != "| ---: | ---: | --- | --- |" {
+		t.Errorf("separator row = %q", lines[1])
+	}
+	var rows []string
+	for _, l := range lines[2:] {
+		if strings.HasPrefix(l, "| ") {
+			rows = append(rows, l)
+		}
+	}
+	if len(rows) != len(results) {
+		t.Fatalf("got %d result rows, want %d", len(rows), len(results))
+	}
+	if rows[1] != "| 301 | 0 | http://example.com/images | http://example.com/images/ |" {
+		t.Errorf("redirect row = %q", rows[1])
+	}
+	if !strings.Contains(rows[2], `http://example.com/a\|b`) {
+		t.Errorf("expected escaped pipe in %q", rows[2])
+	}
+	if !strings.Contains(lines[len(lines)-1], "**Requests:** 100") {
+		t.Errorf("summary line = %q", lines[len(lines)-1])
+	}
+}
@@ -0,0 +1,46 @@
+package output
+
+import (
+	"sort"
+
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+// Ordered re-sequences a worker pool's results into work-list order
+// (--ordered). Results that arrive early are buffered until every result
+// before them has been emitted, so at most the in-flight window is held
+// in memory. When in is closed early (e.g. the scan was cancelled and
+// some items never produced a result), the buffered results are flushed
+// in order, skipping the gaps.
+//
+// The returned channel is closed after in is closed and drained.
+func Ordered(in <-chan scanner.ScanResult) <-chan scanner.ScanResult {
+	out := make(chan scanner.ScanResult)
+	go func() {
+		defer close(out)
+		pending := make(map[int]scanner.ScanResult)
+		next := 0
+		for result := range in {
+			pending[result.Index] = result
+			for {
+				r, ok := pending[next]
+				if !ok {
+					break
+				}
+				delete(pending, next)
+				next++
+				out <- r
+			}
+		}
+
+		indexes := make([]int, 0, len(pending))
+		for i := range pending {
+			indexes = append(indexes, i)
+		}
+		sort.Ints(indexes)
+		for _, i := range indexes {
+			out <- pending[i]
+		}
+	}()
+	return out
+}
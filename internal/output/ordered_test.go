@@ -0,0 +1,49 @@
+package output
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+func TestOrdered(t *testing.T) {
+	const n = 200
+	indexes := rand.Perm(n)
+
+	in := make(chan scanner.ScanResult)
+	go func() {
+		defer close(in)
+		for _, i := range indexes {
+			in <- scanner.ScanResult{Index: i}
+		}
+	}()
+
+	want := 0
+	for r := range Ordered(in) {
+		if r.Index != want {
+			t.Fatalf("got index %d, want %d", r.Index, want)
+		}
+		want++
+	}
+	if want != n {
+		t.Errorf("got %d results, want %d", want, n)
+	}
+}
+
+func TestOrdered_FlushesAfterGaps(t *testing.T) {
+	// Index 1 never arrives, as when a scan is cancelled mid-request.
+	in := make(chan scanner.ScanResult, 3)
+	in <- scanner.ScanResult{Index: 3}
+	in <- scanner.ScanResult{Index: 0}
+	in <- scanner.ScanResult{Index: 2}
+	close(in)
+
+	var got []int
+	for r := range Ordered(in) {
+		got = append(got, r.Index)
+	}
+	if len(got) != 3 || got[0] != 0 || got[1] != 2 || got[2] != 3 {
+		t.Errorf("got %v, want [0 2 3]", got)
+	}
+}
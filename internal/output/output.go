@@ -1,6 +1,9 @@
 package output
 
 import (
+	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/maxvaer/dirfuzz/internal/scanner"
@@ -13,6 +16,133 @@ type Stats struct {
 	ErrorCount     int
 	Duration       time.Duration
 	RequestsPerSec float64
+
+	// Response breakdown, including filtered responses but not errors.
+	StatusCounts map[int]int
+	SizeBuckets  [len(sizeBucketBounds) + 1]int // see SizeBucketLabels
+
+	// ErrorCounts breaks ErrorCount down by category.
+	ErrorCounts map[scanner.ErrorCategory]int
+
+	// Reported results slower than SlowThreshold (--slow-threshold).
+	SlowThreshold time.Duration
+	Slow          []SlowResponse
+
+	// Reported results that are open directory listings (--detect-listings).
+	Listings []Listing
+}
+
+// Listing is a reported result whose body is a directory listing.
+type Listing struct {
+	Method     string
+	Host       string
+	Path       string
+	StatusCode int
+}
+
+// SlowResponse is a reported result that exceeded the slow threshold.
+type SlowResponse struct {
+	Method     string
+	Host       string
+	Path       string
+	StatusCode int
+	Duration   time.Duration
+}
+
+// sizeBucketBounds are the inclusive upper bounds (in bytes) of every
+// size bucket except the last, which holds everything larger.
+var sizeBucketBounds = [...]int64{0, 100, 1000, 10000, 100000}
+
+// SizeBucketLabels names the SizeBuckets ranges in order.
+var SizeBucketLabels = [len(sizeBucketBounds) + 1]string{"0", "1-100", "101-1K", "1K-10K", "10K-100K", ">100K"}
+
+// RecordResponse adds a response to the status and size breakdown.
+func (s *Stats) RecordResponse(status int, size int64) {
+	if s.StatusCounts == nil {
+		s.StatusCounts = make(map[int]int)
+	}
+	s.StatusCounts[status]++
+	for i, bound := range sizeBucketBounds {
+		if size <= bound {
+			s.SizeBuckets[i]++
+			return
+		}
+	}
+	s.SizeBuckets[len(sizeBucketBounds)]++
+}
+
+// RecordError counts a failed request of the given category.
+func (s *Stats) RecordError(category scanner.ErrorCategory) {
+	if category == "" {
+		category = scanner.ErrorOther
+	}
+	if s.ErrorCounts == nil {
+		s.ErrorCounts = make(map[scanner.ErrorCategory]int)
+	}
+	s.ErrorCount++
+	s.ErrorCounts[category]++
+}
+
+// RecordSlow adds result to the slow responses.
+func (s *Stats) RecordSlow(result *scanner.ScanResult) {
+	s.Slow = append(s.Slow, SlowResponse{
+		Method:     result.Method,
+		Host:       result.Host,
+		Path:       result.Path,
+		StatusCode: result.StatusCode,
+		Duration:   result.Duration,
+	})
+}
+
+// RecordListing adds result to the directory listings.
+func (s *Stats) RecordListing(result *scanner.ScanResult) {
+	s.Listings = append(s.Listings, Listing{
+		Method:     result.Method,
+		Host:       result.Host,
+		Path:       result.Path,
+		StatusCode: result.StatusCode,
+	})
+}
+
+// FormatErrorCounts renders per-category error counts, e.g.
+// "timeout: 12 | refused: 3", in scanner.ErrorCategories order.
+func FormatErrorCounts(counts map[scanner.ErrorCategory]int) string {
+	var parts []string
+	for _, category := range scanner.ErrorCategories {
+		if n := counts[category]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%s: %d", category, n))
+		}
+	}
+	return strings.Join(parts, " | ")
+}
+
+// Add merges the counters of other into s. Duration and RequestsPerSec
+// are left alone since they don't sum meaningfully; SlowThreshold is taken
+// from other if s has none.
+func (s *Stats) Add(other Stats) {
+	s.TotalRequests += other.TotalRequests
+	s.FilteredCount += other.FilteredCount
+	s.ErrorCount += other.ErrorCount
+	for status, n := range other.StatusCounts {
+		if s.StatusCounts == nil {
+			s.StatusCounts = make(map[int]int)
+		}
+		s.StatusCounts[status] += n
+	}
+	for i, n := range other.SizeBuckets {
+		s.SizeBuckets[i] += n
+	}
+	for category, n := range other.ErrorCounts {
+		if s.ErrorCounts == nil {
+			s.ErrorCounts = make(map[scanner.ErrorCategory]int)
+		}
+		s.ErrorCounts[category] += n
+	}
+	if s.SlowThreshold == 0 {
+		s.SlowThreshold = other.SlowThreshold
+	}
+	s.Slow = append(s.Slow, other.Slow...)
+	s.Listings = append(s.Listings, other.Listings...)
 }
 
 // Writer is implemented by each output format.
@@ -22,3 +152,23 @@ type Writer interface {
 	WriteFooter(stats Stats) error
 	Close() error
 }
+
+// openOutput creates path for writing, truncating it unless appendMode is
+// set. In append mode nonEmpty reports whether the file already had
+// content, so writers can skip a header that is already there.
+func openOutput(path string, appendMode bool) (f *os.File, nonEmpty bool, err error) {
+	if !appendMode {
+		f, err = os.Create(path)
+		return f, false, err
+	}
+	f, err = os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, false, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, false, err
+	}
+	return f, info.Size() > 0, nil
+}
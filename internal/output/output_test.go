@@ -0,0 +1,301 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+func TestStatsRecordResponse(t *testing.T) {
+	var s Stats
+	for _, r := range []struct {
+		status int
+		size   int64
+	}{
+		{200, 0}, {200, 100}, {404, 101}, {404, 1000}, {404, 1001}, {301, 100001},
+	} {
+		s.RecordResponse(r.status, r.size)
+	}
+
+	if s.StatusCounts[200] != 2 || s.StatusCounts[404] != 3 || s.StatusCounts[301] != 1 {
+		t.Errorf("StatusCounts = %v", s.StatusCounts)
+	}
+	want := [len(SizeBucketLabels)]int{1, 1, 2, 1, 0, 1}
+	if s.SizeBuckets != want {
+		t.Errorf("SizeBuckets = %v, want %v", s.SizeBuckets, want)
+	}
+}
+
+func TestStatsAdd(t *testing.T) {
+	var a, b Stats
+	a.TotalRequests, a.FilteredCount = 10, 4
+	a.RecordResponse(200, 50)
+	b.TotalRequests, b.ErrorCount = 5, 1
+	b.RecordResponse(200, 50)
+	b.RecordResponse(403, 0)
+
+	a.Add(b)
+	if a.TotalRequests != 15 || a.FilteredCount != 4 || a.ErrorCount != 1 {
+		t.Errorf("counters = %d/%d/%d", a.TotalRequests, a.FilteredCount, a.ErrorCount)
+	}
+	if a.StatusCounts[200] != 2 || a.StatusCounts[403] != 1 {
+		t.Errorf("StatusCounts = %v", a.StatusCounts)
+	}
+	if a.SizeBuckets[0] != 1 || a.SizeBuckets[1] != 2 {
+		t.Errorf("SizeBuckets = %v", a.SizeBuckets)
+	}
+}
+
+func TestStatsAddSlow(t *testing.T) {
+	var total Stats
+	target := Stats{SlowThreshold: time.Second}
+	target.RecordSlow(&scanner.ScanResult{Path: "/report", Duration: 2 * time.Second})
+
+	total.Add(target)
+	if total.SlowThreshold != time.Second {
+		t.Errorf("SlowThreshold = %s, want 1s", total.SlowThreshold)
+	}
+	if len(total.Slow) != 1 {
+		t.Errorf("Slow = %v, want one entry", total.Slow)
+	}
+}
+
+func TestStatsRecordError(t *testing.T) {
+	var a, b Stats
+	a.RecordError(scanner.ErrorTimeout)
+	a.RecordError(scanner.ErrorTimeout)
+	b.RecordError(scanner.ErrorRefused)
+	b.RecordError("")
+
+	a.Add(b)
+	if a.ErrorCount != 4 {
+		t.Errorf("ErrorCount = %d, want 4", a.ErrorCount)
+	}
+	if got, want := FormatErrorCounts(a.ErrorCounts), "refused: 1 | timeout: 2 | other: 1"; got != want {
+		t.Errorf("FormatErrorCounts() = %q, want %q", got, want)
+	}
+}
+
+// writeRun performs one scan's worth of writes through w.
+func writeRun(t *testing.T, w Writer, path string) {
+	t.Helper()
+	if err := w.WriteHeader(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteResult(&scanner.ScanResult{Method: "GET", Path: path, StatusCode: 200}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteFooter(Stats{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOutputAppend(t *testing.T) {
+	tests := []struct {
+		name      string
+		open      func(path string) (Writer, error)
+		header    string
+		wantLines int
+	}{
+		{"text", func(p string) (Writer, error) { return NewTextWriter(p, true, false, false, true, 0, nil) }, "Code", 3},
+		{"csv", func(p string) (Writer, error) { return NewCSVWriter(p, true, nil, nil) }, "method,host", 3},
+		{"json", func(p string) (Writer, error) { return NewJSONWriter(p, true, nil) }, "", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "out")
+			for _, p := range []string{"first", "second"} {
+				w, err := tt.open(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if tw, ok := w.(*TextWriter); ok {
+					tw.summary = io.Discard
+				}
+				writeRun(t, w, p)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			out := string(data)
+			lines := strings.Split(strings.TrimSpace(out), "\n")
+			if len(lines) != tt.wantLines {
+				t.Errorf("got %d lines, want %d:\n%s", len(lines), tt.wantLines, out)
+			}
+			if !strings.Contains(out, "first") || !strings.Contains(out, "second") {
+				t.Errorf("second run should append to the first:\n%s", out)
+			}
+			if tt.header != "" && strings.Count(out, tt.header) != 1 {
+				t.Errorf("header %q should be written once:\n%s", tt.header, out)
+			}
+		})
+	}
+}
+
+func TestOutputAppend_JSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.jsonl")
+	w, err := NewJSONWriter(path, true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeRun(t, w, "admin")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entry jsonEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		t.Fatalf("append mode should write one JSON object per line: %v\n%s", err, data)
+	}
+	if entry.Path != "admin" {
+		t.Errorf("path = %q, want admin", entry.Path)
+	}
+}
+
+func TestOutputTruncatesWithoutAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.csv")
+	for _, p := range []string{"first", "second"} {
+		w, err := NewCSVWriter(path, false, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		writeRun(t, w, p)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "first") {
+		t.Errorf("without append the file should be overwritten:\n%s", data)
+	}
+}
+
+func TestCSVWriterHeaderColumns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.csv")
+	w, err := NewCSVWriter(path, false, []string{"Server", "x-powered-by"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteHeader(); err != nil {
+		t.Fatal(err)
+	}
+	result := &scanner.ScanResult{
+		Method:     "GET",
+		Path:       "admin",
+		StatusCode: 200,
+		Headers:    map[string]string{"Server": "nginx", "X-Powered-By": "PHP/8.2"},
+	}
+	if err := w.WriteResult(result); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteFooter(Stats{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	header, row := records[0], records[1]
+	n := len(header)
+	if header[n-2] != "Server" || header[n-1] != "x-powered-by" {
+		t.Errorf("header = %v, want Server and x-powered-by columns last", header)
+	}
+	if row[n-2] != "nginx" || row[n-1] != "PHP/8.2" {
+		t.Errorf("row = %v, want header values last", row)
+	}
+}
+
+func TestFieldSelection(t *testing.T) {
+	result := &scanner.ScanResult{
+		Method:        "GET",
+		URL:           "http://example.com/admin",
+		Path:          "admin",
+		StatusCode:    200,
+		ContentLength: 42,
+		WordCount:     7,
+		Title:         "Admin",
+	}
+	fields := []string{"title", "status", "url", "words"}
+
+	csvPath := filepath.Join(t.TempDir(), "out.csv")
+	cw, err := NewCSVWriter(csvPath, false, []string{"Server"}, fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeResult(t, cw, result)
+	data, err := os.ReadFile(csvPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "title,status,url,words,Server\nAdmin,200,http://example.com/admin,7,\n"; string(data) != want {
+		t.Errorf("CSV =\n%s\nwant\n%s", data, want)
+	}
+
+	jsonPath := filepath.Join(t.TempDir(), "out.json")
+	jw, err := NewJSONWriter(jsonPath, true, fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeResult(t, jw, result)
+	data, err = os.ReadFile(jsonPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"title":"Admin","status":200,"url":"http://example.com/admin","words":7}` + "\n"; string(data) != want {
+		t.Errorf("JSON = %s, want %s", data, want)
+	}
+}
+
+func TestOutputFieldsHaveExtractors(t *testing.T) {
+	for _, name := range OutputFields {
+		if fieldValues[name] == nil {
+			t.Errorf("field %q has no extractor", name)
+		}
+	}
+	if len(fieldValues) != len(OutputFields) {
+		t.Errorf("%d extractors for %d fields", len(fieldValues), len(OutputFields))
+	}
+}
+
+func writeResult(t *testing.T, w Writer, result *scanner.ScanResult) {
+	t.Helper()
+	for _, err := range []error{w.WriteHeader(), w.WriteResult(result), w.WriteFooter(Stats{}), w.Close()} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestParseFields(t *testing.T) {
+	got, err := ParseFields([]string{" URL", "Status", "content_type"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(got, ",") != "url,status,content_type" {
+		t.Errorf("ParseFields = %v", got)
+	}
+	if _, err := ParseFields([]string{"url", "body"}); err == nil || !strings.Contains(err.Error(), `"body"`) {
+		t.Errorf("unknown field should be rejected by name, got %v", err)
+	}
+}
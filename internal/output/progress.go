@@ -2,6 +2,7 @@ package output
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"sync"
@@ -26,26 +27,55 @@ type Progress struct {
 	start     time.Time
 	done      chan struct{}
 	stopped   chan struct{} // closed when the display goroutine exits
-	quiet     bool
+	quiet     bool          // draw nothing (silent mode or --no-progress)
+	out       io.Writer     // destination for the progress line (stderr)
 	mu        sync.Mutex
-	visible   bool       // whether the progress line is currently drawn
-	pauser    PauseState // may be nil
+	visible   bool          // whether the progress line is currently drawn
+	pauser    PauseState    // may be nil
+	parent    *Aggregate    // may be nil
+	samples   []rateSample  // recent completion counts, oldest first (guarded by mu)
+	heartbeat time.Duration // status line interval while the bar is hidden (0 = off)
+	label     string        // what the heartbeat line reports on, e.g. the target URL
+}
+
+// The progress rate, and so the ETA, is measured over the last rateWindow
+// rather than since the start, so it follows throttling and back-off
+// instead of averaging them away. Samples are taken at most every
+// rateSampleEvery; until they span minRateSpan the average since the
+// start is used.
+const (
+	rateWindow      = 10 * time.Second
+	rateSampleEvery = time.Second
+	minRateSpan     = 2 * time.Second
+)
+
+// rateSample is the completed count at a point in time.
+type rateSample struct {
+	at        time.Time
+	completed int64
 }
 
 // NewProgress creates a progress tracker. Call Start() to begin display updates.
-func NewProgress(total int, quiet bool) *Progress {
+// noProgress hides the progress bar without silencing the rest of the output;
+// counters and ETA keep working either way.
+func NewProgress(total int, quiet, noProgress bool) *Progress {
 	return &Progress{
 		total:   total,
 		start:   time.Now(),
 		done:    make(chan struct{}),
 		stopped: make(chan struct{}),
-		quiet:   quiet,
+		quiet:   quiet || noProgress,
+		out:     os.Stderr,
 	}
 }
 
 // Start begins periodically printing progress to stderr.
 func (p *Progress) Start() {
 	if p.quiet {
+		if p.heartbeat > 0 {
+			go p.beat()
+			return
+		}
 		close(p.stopped)
 		return
 	}
@@ -62,7 +92,7 @@ func (p *Progress) Start() {
 			case <-p.done:
 				p.mu.Lock()
 				p.draw()
-				fmt.Fprint(os.Stderr, "\n")
+				fmt.Fprint(p.out, "\n")
 				p.visible = false
 				p.mu.Unlock()
 				return
@@ -71,6 +101,43 @@ func (p *Progress) Start() {
 	}()
 }
 
+// beat prints a plain status line every heartbeat interval until Stop, so
+// a scan with its progress bar hidden doesn't look hung.
+func (p *Progress) beat() {
+	defer close(p.stopped)
+	ticker := time.NewTicker(p.heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.mu.Lock()
+			p.writeHeartbeat()
+			p.mu.Unlock()
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// writeHeartbeat prints one status line. The caller must hold p.mu.
+func (p *Progress) writeHeartbeat() {
+	completed := p.completed.Load()
+	now := time.Now()
+	pct := float64(0)
+	if p.total > 0 {
+		pct = float64(completed) / float64(p.total) * 100
+	}
+	line := fmt.Sprintf("[*] %s: %d/%d (%.0f%%) | %.0f req/s | Found: %d | Errors: %d",
+		p.label, completed, p.total, pct, p.rate(now, completed), p.found.Load(), p.errors.Load())
+	if left := p.eta(now, completed); left > 0 {
+		line += fmt.Sprintf(" | ETA: %s", left.Round(time.Second))
+	}
+	if p.pauser != nil && p.pauser.IsPaused() {
+		line += " [PAUSED]"
+	}
+	fmt.Fprintln(p.out, line)
+}
+
 // ClearLine temporarily removes the progress bar from the terminal so that
 // a result line can be printed cleanly. Call Redraw() after printing.
 func (p *Progress) ClearLine() {
@@ -79,7 +146,7 @@ func (p *Progress) ClearLine() {
 	}
 	p.mu.Lock()
 	if p.visible {
-		fmt.Fprint(os.Stderr, "\r\033[K")
+		fmt.Fprint(p.out, "\r\033[K")
 		p.visible = false
 	}
 }
@@ -96,6 +163,9 @@ func (p *Progress) Redraw() {
 // Increment records a completed request.
 func (p *Progress) Increment() {
 	p.completed.Add(1)
+	if p.parent != nil {
+		p.parent.requests.Add(1)
+	}
 }
 
 // Completed returns the number of completed requests.
@@ -125,6 +195,25 @@ func (p *Progress) SetPauser(ps PauseState) {
 	p.mu.Unlock()
 }
 
+// SetParent makes the progress count its requests into a, and show a's
+// totals on the progress line. Call it before Start.
+func (p *Progress) SetParent(a *Aggregate) {
+	p.mu.Lock()
+	p.parent = a
+	p.mu.Unlock()
+}
+
+// SetHeartbeat makes a hidden progress bar (silent mode or --no-progress)
+// print a one-line status about label every interval instead
+// (--heartbeat). It has no effect while the bar is shown. Call it before
+// Start.
+func (p *Progress) SetHeartbeat(interval time.Duration, label string) {
+	p.mu.Lock()
+	p.heartbeat = interval
+	p.label = label
+	p.mu.Unlock()
+}
+
 // AddTotal increases the total request count (e.g. when crawl discovers new paths).
 func (p *Progress) AddTotal(n int) {
 	p.mu.Lock()
@@ -132,23 +221,54 @@ func (p *Progress) AddTotal(n int) {
 	p.mu.Unlock()
 }
 
-// ETA returns the estimated remaining time based on current progress rate.
-// Returns 0 if not enough data to estimate.
+// ETA returns the estimated remaining time based on the recent progress
+// rate. Returns 0 if not enough data to estimate.
 func (p *Progress) ETA() time.Duration {
-	completed := p.completed.Load()
-	elapsed := time.Since(p.start).Seconds()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.eta(time.Now(), p.completed.Load())
+}
+
+// eta estimates the time left at now. The caller must hold p.mu.
+func (p *Progress) eta(now time.Time, completed int64) time.Duration {
+	rate := p.rate(now, completed)
+	if rate <= 0 || completed <= 0 || completed >= int64(p.total) {
+		return 0
+	}
+	remaining := float64(int64(p.total)-completed) / rate
+	return time.Duration(remaining * float64(time.Second))
+}
+
+// rate records a sample and returns requests per second over the last
+// rateWindow, or the average since the start (excluding pauses) while the
+// window is too short. A pause empties the window so the idle time isn't
+// counted. The caller must hold p.mu.
+func (p *Progress) rate(now time.Time, completed int64) float64 {
+	if p.pauser != nil && p.pauser.IsPaused() {
+		p.samples = p.samples[:0]
+	} else if n := len(p.samples); n == 0 || now.Sub(p.samples[n-1].at) >= rateSampleEvery {
+		p.samples = append(p.samples, rateSample{at: now, completed: completed})
+	}
+	drop := 0
+	for drop < len(p.samples)-1 && now.Sub(p.samples[drop].at) > rateWindow {
+		drop++
+	}
+	p.samples = append(p.samples[:0], p.samples[drop:]...)
+
+	if len(p.samples) > 0 {
+		oldest := p.samples[0]
+		if span := now.Sub(oldest.at); span >= minRateSpan {
+			return float64(completed-oldest.completed) / span.Seconds()
+		}
+	}
+	elapsed := now.Sub(p.start)
 	if p.pauser != nil {
-		elapsed -= p.pauser.PausedDuration().Seconds()
+		elapsed -= p.pauser.PausedDuration()
 	}
-	if elapsed <= 0 || completed <= 0 {
+	if elapsed <= 0 {
 		return 0
 	}
-	rate := float64(completed) / elapsed
-	p.mu.Lock()
-	total := p.total
-	p.mu.Unlock()
-	remaining := float64(int64(total)-completed) / rate
-	return time.Duration(remaining * float64(time.Second))
+	return float64(completed) / elapsed.Seconds()
 }
 
 // Stop ends the progress display and waits for the display goroutine to exit.
@@ -184,17 +304,8 @@ func buildBar(pct float64, width int) string {
 
 func (p *Progress) draw() {
 	completed := p.completed.Load()
-	elapsed := time.Since(p.start).Seconds()
-	if p.pauser != nil {
-		elapsed -= p.pauser.PausedDuration().Seconds()
-		if elapsed < 0 {
-			elapsed = 0
-		}
-	}
-	rate := float64(0)
-	if elapsed > 0 {
-		rate = float64(completed) / elapsed
-	}
+	now := time.Now()
+	rate := p.rate(now, completed)
 
 	pct := float64(0)
 	if p.total > 0 {
@@ -202,9 +313,8 @@ func (p *Progress) draw() {
 	}
 
 	eta := ""
-	if rate > 0 && completed < int64(p.total) {
-		remaining := float64(int64(p.total)-completed) / rate
-		eta = fmt.Sprintf("ETA: %s", time.Duration(remaining*float64(time.Second)).Round(time.Second))
+	if left := p.eta(now, completed); left > 0 {
+		eta = fmt.Sprintf("ETA: %s", left.Round(time.Second))
 	}
 
 	pauseTag := ""
@@ -213,10 +323,15 @@ func (p *Progress) draw() {
 		pauseTag = fmt.Sprintf(" [PAUSED %s]", pd)
 	}
 
+	targetTag := ""
+	if p.parent != nil {
+		targetTag = p.parent.tag()
+	}
+
 	bar := buildBar(pct, 20)
 
-	fmt.Fprintf(os.Stderr, "\r\033[K%s %3.0f%% | %d/%d | %.0f req/s | Found: %d | Filtered: %d | Errors: %d | %s%s",
+	fmt.Fprintf(p.out, "\r\033[K%s %3.0f%% | %d/%d | %.0f req/s | Found: %d | Filtered: %d | Errors: %d | %s%s%s",
 		bar, pct, completed, p.total, rate,
-		p.found.Load(), p.filtered.Load(), p.errors.Load(), eta, pauseTag)
+		p.found.Load(), p.filtered.Load(), p.errors.Load(), eta, pauseTag, targetTag)
 	p.visible = true
 }
@@ -0,0 +1,142 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+func TestProgress_NoProgressKeepsResults(t *testing.T) {
+	var buf bytes.Buffer
+	progress := NewProgress(10, false, true)
+	progress.out = &buf
+	tw := &TextWriter{w: &buf, summary: &buf, noColor: true}
+
+	progress.Start()
+	if err := tw.WriteHeader(); err != nil {
+		t.Fatal(err)
+	}
+	progress.Increment()
+	progress.IncrementFound()
+	progress.ClearLine()
+	if err := tw.WriteResult(&scanner.ScanResult{Method: "GET", Path: "admin", StatusCode: 200}); err != nil {
+		t.Fatal(err)
+	}
+	progress.Redraw()
+	progress.Stop()
+	if err := tw.WriteFooter(Stats{TotalRequests: 10}); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "admin") {
+		t.Errorf("result line missing from output:\n%s", out)
+	}
+	if strings.Contains(out, "\r\033[K") || strings.Contains(out, "Found:") {
+		t.Errorf("progress bar drawn despite noProgress:\n%q", out)
+	}
+}
+
+func TestProgress_DrawsByDefault(t *testing.T) {
+	var buf bytes.Buffer
+	progress := NewProgress(10, false, false)
+	progress.out = &buf
+
+	progress.Start()
+	progress.Increment()
+	progress.Stop()
+
+	if !strings.Contains(buf.String(), "\r\033[K") {
+		t.Errorf("expected a progress bar, got %q", buf.String())
+	}
+}
+
+func TestProgress_ReportsIntoAggregate(t *testing.T) {
+	agg := NewAggregate(3)
+	for range 2 {
+		var buf bytes.Buffer
+		progress := NewProgress(2, false, false)
+		progress.out = &buf
+		progress.SetParent(agg)
+		progress.Start()
+		progress.Increment()
+		progress.Increment()
+		progress.Stop()
+		if !strings.Contains(buf.String(), "/3, ") {
+			t.Errorf("progress line lacks the target count: %q", buf.String())
+		}
+		agg.TargetDone()
+	}
+
+	if got := agg.TargetsDone(); got != 2 {
+		t.Errorf("TargetsDone() = %d, want 2", got)
+	}
+	if got := agg.Requests(); got != 4 {
+		t.Errorf("Requests() = %d, want 4", got)
+	}
+	if got := agg.tag(); got != " [target 3/3, 4 req total]" {
+		t.Errorf("tag() = %q", got)
+	}
+}
+
+func TestProgress_ETAFollowsRecentRate(t *testing.T) {
+	p := NewProgress(10000, true, false)
+	start := p.start
+
+	// 20s at 50 req/s, then throttled to 5 req/s for 10s.
+	var completed int64
+	now := start
+	for sec := 1; sec <= 30; sec++ {
+		if sec <= 20 {
+			completed += 50
+		} else {
+			completed += 5
+		}
+		now = start.Add(time.Duration(sec) * time.Second)
+		p.rate(now, completed)
+	}
+
+	remaining := float64(10000 - completed)
+	cumulative := time.Duration(remaining / (float64(completed) / 30) * float64(time.Second))
+	want := time.Duration(remaining / 5 * float64(time.Second))
+	got := p.eta(now, completed)
+	if got < want*9/10 || got > want*11/10 {
+		t.Errorf("eta = %s, want about %s (5 req/s)", got, want)
+	}
+	if got <= 3*cumulative {
+		t.Errorf("eta = %s barely differs from the cumulative estimate %s", got, cumulative)
+	}
+}
+
+func TestProgress_ETAFallsBackToAverage(t *testing.T) {
+	p := NewProgress(100, true, false)
+	// Half a second in, the window is too short; use the average since start.
+	got := p.eta(p.start.Add(500*time.Millisecond), 10)
+	if want := 4500 * time.Millisecond; got != want {
+		t.Errorf("eta = %s, want %s", got, want)
+	}
+}
+
+func TestProgress_HeartbeatWhenHidden(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewProgress(10, true, false)
+	p.out = &buf
+	p.SetHeartbeat(10*time.Millisecond, "http://target")
+	p.Start()
+	p.Increment()
+	p.Increment()
+	p.IncrementFound()
+	time.Sleep(50 * time.Millisecond)
+	p.Stop()
+
+	out := buf.String()
+	if !strings.Contains(out, "[*] http://target: 2/10 (20%)") || !strings.Contains(out, "Found: 1") {
+		t.Errorf("no heartbeat line in:\n%q", out)
+	}
+	if strings.Contains(out, "\r") {
+		t.Errorf("heartbeat drew the animated bar:\n%q", out)
+	}
+}
@@ -0,0 +1,100 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+// TargetSummary is the --summary record for one scanned target.
+type TargetSummary struct {
+	Target         string                        `json:"target"`
+	TotalRequests  int                           `json:"total_requests"`
+	Found          int                           `json:"found"`
+	Filtered       int                           `json:"filtered"`
+	Errors         int                           `json:"errors"`
+	ErrorCounts    map[scanner.ErrorCategory]int `json:"error_categories,omitempty"`
+	DurationMs     int64                         `json:"duration_ms"`
+	RequestsPerSec float64                       `json:"requests_per_sec"`
+	StatusCodes    map[int]int                   `json:"status_codes"`
+	Directories    []string                      `json:"directories"`
+	Aborted        bool                          `json:"aborted,omitempty"` // skipped by --max-eta or stopped early
+}
+
+// NewTargetSummary builds a target's summary from its final stats.
+func NewTargetSummary(target string, stats Stats, found int, dirs []string) TargetSummary {
+	codes := stats.StatusCounts
+	if codes == nil {
+		codes = map[int]int{}
+	}
+	if dirs == nil {
+		dirs = []string{}
+	}
+	return TargetSummary{
+		Target:         target,
+		TotalRequests:  stats.TotalRequests,
+		Found:          found,
+		Filtered:       stats.FilteredCount,
+		Errors:         stats.ErrorCount,
+		ErrorCounts:    stats.ErrorCounts,
+		DurationMs:     stats.Duration.Milliseconds(),
+		RequestsPerSec: stats.RequestsPerSec,
+		StatusCodes:    codes,
+		Directories:    dirs,
+	}
+}
+
+// SummaryWriter collects per-target summaries and writes them to a JSON
+// file on Close (--summary). It is safe for concurrent use; a nil
+// SummaryWriter ignores everything.
+type SummaryWriter struct {
+	mu      sync.Mutex
+	f       *os.File
+	targets []TargetSummary
+}
+
+// NewSummaryWriter creates the summary file up front so a bad path fails
+// before the scan starts.
+func NewSummaryWriter(path string) (*SummaryWriter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &SummaryWriter{f: f}, nil
+}
+
+// Add records the summary of a finished target.
+func (s *SummaryWriter) Add(summary TargetSummary) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.targets = append(s.targets, summary)
+}
+
+// Close writes {"targets": [...]} and closes the file.
+func (s *SummaryWriter) Close() error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	targets := s.targets
+	if targets == nil {
+		targets = []TargetSummary{}
+	}
+	enc := json.NewEncoder(s.f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(struct {
+		Targets []TargetSummary `json:"targets"`
+	}{targets}); err != nil {
+		s.f.Close()
+		return fmt.Errorf("writing summary: %w", err)
+	}
+	return s.f.Close()
+}
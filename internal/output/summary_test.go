@@ -0,0 +1,62 @@
+package output
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSummaryWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "summary.json")
+	s, err := NewSummaryWriter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := Stats{
+		TotalRequests:  10,
+		FilteredCount:  6,
+		ErrorCount:     1,
+		Duration:       2 * time.Second,
+		RequestsPerSec: 5,
+		StatusCounts:   map[int]int{200: 3, 404: 6},
+	}
+	s.Add(NewTargetSummary("http://example.com", stats, 3, []string{"admin/"}))
+	s.Add(NewTargetSummary("http://other.com", Stats{}, 0, nil))
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Targets []TargetSummary `json:"targets"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, data)
+	}
+	if len(doc.Targets) != 2 {
+		t.Fatalf("got %d targets, want 2", len(doc.Targets))
+	}
+	got := doc.Targets[0]
+	if got.TotalRequests != 10 || got.Found != 3 || got.Filtered != 6 || got.Errors != 1 || got.DurationMs != 2000 {
+		t.Errorf("counts = %+v", got)
+	}
+	if got.StatusCodes[404] != 6 || len(got.Directories) != 1 {
+		t.Errorf("status codes %v, directories %v", got.StatusCodes, got.Directories)
+	}
+	if empty := doc.Targets[1]; empty.StatusCodes == nil || empty.Directories == nil {
+		t.Errorf("empty target should encode {} and [], got %+v", empty)
+	}
+}
+
+func TestSummaryWriter_Nil(t *testing.T) {
+	var s *SummaryWriter
+	s.Add(TargetSummary{})
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,43 @@
+package output
+
+import (
+	"sync"
+
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+// SyncWriter serializes calls to any other Writer so it can be shared by
+// several goroutines.
+type SyncWriter struct {
+	mu    sync.Mutex
+	inner Writer
+}
+
+// NewSyncWriter wraps inner with a mutex around all Writer methods.
+func NewSyncWriter(inner Writer) *SyncWriter {
+	return &SyncWriter{inner: inner}
+}
+
+func (w *SyncWriter) WriteHeader() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.inner.WriteHeader()
+}
+
+func (w *SyncWriter) WriteResult(result *scanner.ScanResult) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.inner.WriteResult(result)
+}
+
+func (w *SyncWriter) WriteFooter(stats Stats) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.inner.WriteFooter(stats)
+}
+
+func (w *SyncWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.inner.Close()
+}
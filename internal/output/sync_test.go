@@ -0,0 +1,64 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+func TestSyncWriter_ConcurrentWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.json")
+	inner, err := NewJSONWriter(path, false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := NewSyncWriter(inner)
+
+	const goroutines, perGoroutine = 20, 50
+	if err := w.WriteHeader(); err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				r := &scanner.ScanResult{Path: fmt.Sprintf("g%d/%d", g, i), StatusCode: 200}
+				if err := w.WriteResult(r); err != nil {
+					t.Error(err)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	if err := w.WriteFooter(Stats{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []struct {
+		Path string `json:"path"`
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		seen[e.Path] = true
+	}
+	if len(entries) != goroutines*perGoroutine || len(seen) != len(entries) {
+		t.Errorf("got %d entries (%d unique), want %d", len(entries), len(seen), goroutines*perGoroutine)
+	}
+}
@@ -4,46 +4,78 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"slices"
+	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/maxvaer/dirfuzz/internal/scanner"
+	"golang.org/x/term"
 )
 
 // ANSI color codes.
 const (
-	colorReset  = "\033[0m"
-	colorGreen  = "\033[32m"
-	colorCyan   = "\033[36m"
-	colorYellow = "\033[33m"
-	colorRed    = "\033[31m"
+	colorReset   = "\033[0m"
+	colorGreen   = "\033[32m"
+	colorCyan    = "\033[36m"
+	colorYellow  = "\033[33m"
+	colorRed     = "\033[31m"
+	colorBlue    = "\033[34m"
+	colorMagenta = "\033[35m"
+	colorWhite   = "\033[37m"
 )
 
 // TextWriter writes colored text output to a writer.
 type TextWriter struct {
-	w       io.Writer
-	noColor bool
-	quiet   bool
-	fullURL bool
+	w          io.Writer
+	summary    io.Writer // destination for the footer summary (stderr)
+	noColor    bool
+	quiet      bool
+	fullURL    bool
+	skipHeader bool // appending to a file that already has one
+	maxURL     int  // elide the path/URL column beyond this many characters (0 = no cap)
+	termWidth  int  // terminal width to fit result lines into (0 = not a terminal)
+	clusters   Clusterer
+	scheme     map[int]string // per-status color overrides from --color-scheme
 }
 
+// minURLWidth is the narrowest the path/URL column is elided to when
+// fitting lines into a narrow terminal.
+const minURLWidth = 20
+
 // NewTextWriter creates a text output writer. If outputFile is empty, stdout
 // is used. noColor disables ANSI escape codes. fullURL shows the complete URL
 // instead of just the path component (default shows /admin instead of https://example.com/admin).
-func NewTextWriter(outputFile string, noColor, quiet, fullURL bool) (*TextWriter, error) {
-	var w io.Writer = os.Stdout
+// appendMode keeps an existing outputFile and adds to it. scheme overrides
+// the color of individual status codes (see ParseColorScheme).
+//
+// When writing to a terminal, long paths are elided in the middle so each
+// result fits on one line. truncateURL additionally caps the column at that
+// many characters; a negative value disables elision. Files always get the
+// full path.
+func NewTextWriter(outputFile string, noColor, quiet, fullURL, appendMode bool, truncateURL int, scheme map[int]string) (*TextWriter, error) {
+	tw := &TextWriter{w: os.Stdout, summary: os.Stderr, noColor: noColor, quiet: quiet, fullURL: fullURL, scheme: scheme}
 	if outputFile != "" {
-		f, err := os.Create(outputFile)
+		f, existing, err := openOutput(outputFile, appendMode)
 		if err != nil {
 			return nil, err
 		}
-		w = f
+		tw.w = f
+		tw.skipHeader = existing
+		return tw, nil
+	}
+	if truncateURL >= 0 && term.IsTerminal(int(os.Stdout.Fd())) {
+		tw.maxURL = truncateURL
+		if width, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil {
+			tw.termWidth = width
+		}
 	}
-	return &TextWriter{w: w, noColor: noColor, quiet: quiet, fullURL: fullURL}, nil
+	return tw, nil
 }
 
 func (t *TextWriter) WriteHeader() error {
-	if t.quiet {
+	if t.quiet || t.skipHeader {
 		return nil
 	}
 	dim := "\033[2m"
@@ -56,11 +88,13 @@ func (t *TextWriter) WriteHeader() error {
 	if t.fullURL {
 		label = "URL"
 	}
-	_, err := fmt.Fprintf(t.w, "%sCode      Size  %s%s\n", dim, label, reset)
+	_, err := fmt.Fprintf(t.w, "%sCode      Size     Time  %s%s\n", dim, label, reset)
 	return err
 }
 
 func (t *TextWriter) WriteResult(result *scanner.ScanResult) error {
+	t.clusters.Add(result)
+
 	color := t.colorForStatus(result.StatusCode)
 	reset := colorReset
 	if t.noColor {
@@ -69,26 +103,62 @@ func (t *TextWriter) WriteResult(result *scanner.ScanResult) error {
 	}
 
 	redirectInfo := ""
+	if len(result.RedirectChain) > 0 {
+		redirectInfo = fmt.Sprintf(" [%s]", strings.Join(result.RedirectChain, " -> "))
+	}
 	if result.RedirectURL != "" {
-		redirectInfo = fmt.Sprintf(" -> %s", result.RedirectURL)
+		redirectInfo += fmt.Sprintf(" -> %s", result.RedirectURL)
 	}
 
-	prefix := ""
-	if result.Method != "" && result.Method != "GET" {
-		prefix += fmt.Sprintf("[%s] ", result.Method)
+	if result.Title != "" {
+		redirectInfo += fmt.Sprintf(" %q", result.Title)
+	}
+	if len(result.Headers) > 0 {
+		redirectInfo += " (" + formatHeaders(result.Headers) + ")"
+	}
+	if result.WebSocket {
+		redirectInfo += " [websocket]"
+	}
+	if len(result.Allow) > 0 {
+		redirectInfo += " (Allow: " + strings.Join(result.Allow, ", ") + ")"
+	}
+	if result.AcceptsRange {
+		if result.FullSize > 0 {
+			redirectInfo += fmt.Sprintf(" [range: %d bytes]", result.FullSize)
+		} else {
+			redirectInfo += " [range]"
+		}
 	}
-	if result.Host != "" {
-		prefix += fmt.Sprintf("[%s] ", result.Host)
+	if result.Truncated {
+		redirectInfo += " [truncated]"
 	}
+	if result.Slow {
+		redirectInfo += " [slow]"
+	}
+	if result.Listing {
+		redirectInfo += " [directory listing]"
+	}
+
+	prefix := resultPrefix(result.Method, result.Host)
 
 	location := "/" + strings.TrimLeft(result.Path, "/")
 	if t.fullURL {
 		location = result.URL
 	}
 
-	_, err := fmt.Fprintf(t.w, "%s%3d%s  %8d  %s%s%s\n",
+	// Response time column is omitted in quiet mode to keep output minimal.
+	timing := ""
+	if !t.quiet {
+		timing = fmt.Sprintf("%7s  ", fmt.Sprintf("%dms", result.Duration.Milliseconds()))
+	}
+
+	// Status and size columns take 15 characters ("200  12345678  ").
+	location = elideMiddle(location, t.urlWidth(15+len(timing), prefix+redirectInfo))
+
+	_, err := fmt.Fprintf(t.w, "%s%3d%s  %8d  %s%s%s%s\n",
 		color, result.StatusCode, reset,
 		result.ContentLength,
+		timing,
 		prefix,
 		location,
 		redirectInfo,
@@ -100,7 +170,7 @@ func (t *TextWriter) WriteFooter(stats Stats) error {
 	if t.quiet {
 		return nil
 	}
-	_, err := fmt.Fprintf(os.Stderr,
+	_, err := fmt.Fprintf(t.summary,
 		"\nCompleted: %d requests | Filtered: %d | Errors: %d | Duration: %s | %.1f req/s\n",
 		stats.TotalRequests,
 		stats.FilteredCount,
@@ -108,7 +178,168 @@ func (t *TextWriter) WriteFooter(stats Stats) error {
 		stats.Duration.Round(time.Millisecond),
 		stats.RequestsPerSec,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	if len(stats.ErrorCounts) > 0 {
+		if err := t.writeSection("Errors: "+FormatErrorCounts(stats.ErrorCounts), nil); err != nil {
+			return err
+		}
+	}
+	if len(stats.StatusCounts) > 0 {
+		if err := t.writeHistogram(stats); err != nil {
+			return err
+		}
+	}
+	if err := t.writeListings(stats); err != nil {
+		return err
+	}
+	if err := t.writeSlow(stats); err != nil {
+		return err
+	}
+	return t.writeClusters()
+}
+
+// writeListings lists every reported open directory listing
+// (--detect-listings); each one exposes the files it indexes.
+func (t *TextWriter) writeListings(stats Stats) error {
+	if len(stats.Listings) == 0 {
+		return nil
+	}
+	lines := make([]string, len(stats.Listings))
+	for i, l := range stats.Listings {
+		lines[i] = fmt.Sprintf("%3d  %s", l.StatusCode, footerPath(l.Method, l.Host, l.Path))
+	}
+	return t.writeSection(fmt.Sprintf("Directory listings (%d):", len(stats.Listings)), lines)
+}
+
+// maxSlow caps the slow responses listed in the footer.
+const maxSlow = 10
+
+// writeSlow lists the slowest reported responses (--slow-threshold):
+// heavy endpoints worth a closer look, or DoS candidates.
+func (t *TextWriter) writeSlow(stats Stats) error {
+	if len(stats.Slow) == 0 {
+		return nil
+	}
+	slow := slices.Clone(stats.Slow)
+	sort.SliceStable(slow, func(i, j int) bool { return slow[i].Duration > slow[j].Duration })
+	var lines []string
+	for _, s := range slow[:min(len(slow), maxSlow)] {
+		lines = append(lines, fmt.Sprintf("%7s  %3d  %s",
+			fmt.Sprintf("%dms", s.Duration.Milliseconds()), s.StatusCode, footerPath(s.Method, s.Host, s.Path)))
+	}
+	if n := len(slow) - maxSlow; n > 0 {
+		lines = append(lines, fmt.Sprintf("... and %d more", n))
+	}
+	return t.writeSection(fmt.Sprintf("Slow responses (over %s):", stats.SlowThreshold), lines)
+}
+
+// writeSection writes a footer section: a title line followed by lines,
+// each indented by two spaces.
+func (t *TextWriter) writeSection(title string, lines []string) error {
+	if _, err := fmt.Fprintln(t.summary, title); err != nil {
+		return err
+	}
+	for _, line := range lines {
+		if _, err := fmt.Fprintf(t.summary, "  %s\n", line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resultPrefix returns the "[METHOD] [host] " shown before a result's
+// path; GET and an unset host are left out.
+func resultPrefix(method, host string) string {
+	prefix := ""
+	if method != "" && method != "GET" {
+		prefix += fmt.Sprintf("[%s] ", method)
+	}
+	if host != "" {
+		prefix += fmt.Sprintf("[%s] ", host)
+	}
+	return prefix
+}
+
+// footerPath formats a result listed in a footer section, e.g.
+// "[POST] [dev.target.com] /api".
+func footerPath(method, host, path string) string {
+	return resultPrefix(method, host) + "/" + strings.TrimLeft(path, "/")
+}
+
+// Clusters smaller than minClusterSize aren't worth a footer line; at most
+// maxClusters are listed.
+const (
+	minClusterSize = 3
+	maxClusters    = 5
+)
+
+// writeClusters lists the largest groups of similar results, e.g. a
+// soft-404 page the filters let through.
+func (t *TextWriter) writeClusters() error {
+	clusters := t.clusters.Clusters(minClusterSize)
+	if len(clusters) == 0 {
+		return nil
+	}
+	if _, err := fmt.Fprintln(t.summary, "Similar results:"); err != nil {
+		return err
+	}
+	for _, c := range clusters[:min(len(clusters), maxClusters)] {
+		if _, err := fmt.Fprintf(t.summary, "  %d responses (%d) cluster around size ~%d\n",
+			c.Count, c.StatusCode, c.Size); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// histogramWidth is the length of the longest bar in the size histogram.
+const histogramWidth = 30
+
+// writeHistogram prints per-status counts and a size histogram covering
+// every response, filtered or not.
+func (t *TextWriter) writeHistogram(stats Stats) error {
+	codes := make([]int, 0, len(stats.StatusCounts))
+	for code := range stats.StatusCounts {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+
+	parts := make([]string, len(codes))
+	for i, code := range codes {
+		color := t.colorForStatus(code)
+		reset := colorReset
+		if color == "" {
+			reset = ""
+		}
+		parts[i] = fmt.Sprintf("%s%d%s: %d", color, code, reset, stats.StatusCounts[code])
+	}
+	if _, err := fmt.Fprintf(t.summary, "Status codes: %s\n", strings.Join(parts, " | ")); err != nil {
+		return err
+	}
+
+	most := 0
+	for _, n := range stats.SizeBuckets {
+		most = max(most, n)
+	}
+	if _, err := fmt.Fprintln(t.summary, "Response sizes (bytes):"); err != nil {
+		return err
+	}
+	for i, n := range stats.SizeBuckets {
+		bar := 0
+		if most > 0 {
+			bar = n * histogramWidth / most
+		}
+		if n > 0 && bar == 0 {
+			bar = 1
+		}
+		if _, err := fmt.Fprintf(t.summary, "  %8s  %-*s %d\n",
+			SizeBucketLabels[i], histogramWidth, strings.Repeat("#", bar), n); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (t *TextWriter) Close() error {
@@ -122,6 +353,9 @@ func (t *TextWriter) colorForStatus(code int) string {
 	if t.noColor {
 		return ""
 	}
+	if color, ok := t.scheme[code]; ok {
+		return color
+	}
 	switch {
 	case code >= 200 && code < 300:
 		return colorGreen
@@ -135,3 +369,51 @@ func (t *TextWriter) colorForStatus(code int) string {
 		return ""
 	}
 }
+
+// urlWidth returns how many characters the path/URL column may use, given
+// the width of the fixed columns before it and the text around it. Zero
+// means unlimited.
+func (t *TextWriter) urlWidth(fixed int, around string) int {
+	limit := t.maxURL
+	if t.termWidth > 0 {
+		avail := t.termWidth - fixed - utf8.RuneCountInString(around)
+		avail = max(avail, minURLWidth)
+		if limit == 0 || avail < limit {
+			limit = avail
+		}
+	}
+	return limit
+}
+
+// elideMiddle shortens s to at most width characters by replacing its
+// middle with "...", keeping both the host and the final path segment
+// readable. width <= 0 returns s unchanged.
+func elideMiddle(s string, width int) string {
+	const ellipsis = "..."
+	runes := []rune(s)
+	if width <= 0 || len(runes) <= width {
+		return s
+	}
+	if width <= len(ellipsis) {
+		return string(runes[:width])
+	}
+	keep := width - len(ellipsis)
+	head := (keep + 1) / 2
+	tail := keep - head
+	return string(runes[:head]) + ellipsis + string(runes[len(runes)-tail:])
+}
+
+// formatHeaders renders captured headers as "Name: value; Name: value",
+// sorted by name.
+func formatHeaders(headers map[string]string) string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = name + ": " + headers[name]
+	}
+	return strings.Join(parts, "; ")
+}
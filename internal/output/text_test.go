@@ -0,0 +1,251 @@
+package output
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+func TestTextWriterFooterErrors(t *testing.T) {
+	var summary bytes.Buffer
+	w := &TextWriter{w: io.Discard, summary: &summary, noColor: true}
+
+	var stats Stats
+	stats.RecordError(scanner.ErrorReset)
+	stats.RecordError(scanner.ErrorDNS)
+	stats.RecordError(scanner.ErrorDNS)
+	if err := w.WriteFooter(stats); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(summary.String(), "\nErrors: dns: 2 | reset: 1\n") {
+		t.Errorf("missing error breakdown in:\n%s", summary.String())
+	}
+}
+
+func TestTextWriterFooterHistogram(t *testing.T) {
+	var summary bytes.Buffer
+	w := &TextWriter{w: io.Discard, summary: &summary, noColor: true}
+
+	var stats Stats
+	stats.TotalRequests = 6
+	stats.FilteredCount = 3
+	for _, size := range []int64{10, 20} {
+		stats.RecordResponse(200, size)
+	}
+	for i := 0; i < 3; i++ {
+		stats.RecordResponse(404, 5000)
+	}
+	stats.RecordResponse(301, 0)
+
+	if err := w.WriteFooter(stats); err != nil {
+		t.Fatal(err)
+	}
+	out := summary.String()
+
+	if !strings.Contains(out, "Status codes: 200: 2 | 301: 1 | 404: 3") {
+		t.Errorf("missing sorted status breakdown in:\n%s", out)
+	}
+	if strings.Contains(out, "\nErrors: ") {
+		t.Errorf("error breakdown printed without errors:\n%s", out)
+	}
+	for _, line := range []string{
+		"         0  " + strings.Repeat("#", 10),
+		"     1-100  " + strings.Repeat("#", 20),
+		"    1K-10K  " + strings.Repeat("#", 30) + " 3",
+	} {
+		if !strings.Contains(out, line) {
+			t.Errorf("missing histogram line %q in:\n%s", line, out)
+		}
+	}
+}
+
+func TestTextWriterFooterQuiet(t *testing.T) {
+	var summary bytes.Buffer
+	w := &TextWriter{w: io.Discard, summary: &summary, quiet: true}
+
+	var stats Stats
+	stats.RecordResponse(200, 10)
+	if err := w.WriteFooter(stats); err != nil {
+		t.Fatal(err)
+	}
+	if summary.Len() != 0 {
+		t.Errorf("expected no footer in quiet mode, got:\n%s", summary.String())
+	}
+}
+
+func TestTextWriterRedirectChain(t *testing.T) {
+	var buf bytes.Buffer
+	w := &TextWriter{w: &buf, noColor: true, quiet: true}
+
+	err := w.WriteResult(&scanner.ScanResult{
+		Path:          "old",
+		StatusCode:    200,
+		RedirectChain: []string{"301 /a", "302 /b"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "/old [301 /a -> 302 /b]") {
+		t.Errorf("missing redirect chain in %q", buf.String())
+	}
+}
+
+func TestTextWriterElidesLongURLOnTerminal(t *testing.T) {
+	long := "https://example.com/" + strings.Repeat("a", 100) + "/index.php"
+	result := &scanner.ScanResult{Method: "GET", StatusCode: 200, URL: long, Duration: 5 * time.Millisecond}
+
+	tests := []struct {
+		name      string
+		termWidth int
+		maxURL    int
+		wantLen   int
+	}{
+		{"fit terminal", 80, 0, 80},
+		{"explicit cap", 200, 40, 15 + 9 + 40},
+		{"terminal narrower than cap", 60, 100, 60},
+		{"floor in tiny terminal", 30, 0, 15 + 9 + minURLWidth},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := &TextWriter{w: &buf, noColor: true, fullURL: true, termWidth: tt.termWidth, maxURL: tt.maxURL}
+			if err := w.WriteResult(result); err != nil {
+				t.Fatal(err)
+			}
+			line := strings.TrimSuffix(buf.String(), "\n")
+			if len(line) != tt.wantLen {
+				t.Errorf("line is %d characters, want %d: %q", len(line), tt.wantLen, line)
+			}
+			if !strings.Contains(line, "https://e") || !strings.HasSuffix(line, ".php") || !strings.Contains(line, "...") {
+				t.Errorf("URL should be elided in the middle: %q", line)
+			}
+		})
+	}
+}
+
+func TestTextWriterKeepsFullURLInFile(t *testing.T) {
+	long := "/" + strings.Repeat("a", 300)
+	path := filepath.Join(t.TempDir(), "out.txt")
+	w, err := NewTextWriter(path, true, false, false, false, 10, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteResult(&scanner.ScanResult{StatusCode: 200, Path: long}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), long) {
+		t.Errorf("file output should keep the full path, got %q", data)
+	}
+}
+
+func TestElideMiddle(t *testing.T) {
+	tests := []struct {
+		in    string
+		width int
+		want  string
+	}{
+		{"/admin", 0, "/admin"},
+		{"/admin", 10, "/admin"},
+		{"/abcdefghij", 9, "/ab...hij"},
+		{"/abcdefghij", 3, "/ab"},
+	}
+	for _, tt := range tests {
+		if got := elideMiddle(tt.in, tt.width); got != tt.want {
+			t.Errorf("elideMiddle(%q, %d) = %q, want %q", tt.in, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestTextWriterShowsHeaders(t *testing.T) {
+	var buf bytes.Buffer
+	w := &TextWriter{w: &buf, noColor: true}
+	result := &scanner.ScanResult{
+		StatusCode: 200,
+		Path:       "admin",
+		Headers:    map[string]string{"X-Powered-By": "PHP/8.2", "Server": "nginx"},
+	}
+	if err := w.WriteResult(result); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(buf.String(), "/admin (Server: nginx; X-Powered-By: PHP/8.2)\n") {
+		t.Errorf("unexpected line %q", buf.String())
+	}
+}
+
+func TestTextWriterFooterClusters(t *testing.T) {
+	var summary bytes.Buffer
+	w := &TextWriter{w: io.Discard, summary: &summary, noColor: true}
+	for i := 0; i < 4; i++ {
+		if err := w.WriteResult(&scanner.ScanResult{StatusCode: 200, LineCount: 10, WordCount: 100, ContentLength: 4200}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.WriteResult(&scanner.ScanResult{StatusCode: 301}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteFooter(Stats{}); err != nil {
+		t.Fatal(err)
+	}
+	out := summary.String()
+	if !strings.Contains(out, "4 responses (200) cluster around size ~4200") {
+		t.Errorf("missing cluster line in:\n%s", out)
+	}
+	if strings.Contains(out, "(301)") {
+		t.Errorf("single result should not form a cluster:\n%s", out)
+	}
+}
+
+func TestTextWriterFooterSlow(t *testing.T) {
+	var summary bytes.Buffer
+	w := &TextWriter{w: io.Discard, summary: &summary, noColor: true}
+
+	stats := Stats{SlowThreshold: time.Second}
+	stats.RecordSlow(&scanner.ScanResult{Path: "/export", StatusCode: 200, Duration: 1500 * time.Millisecond})
+	stats.RecordSlow(&scanner.ScanResult{Path: "/report", StatusCode: 200, Duration: 4 * time.Second})
+	stats.RecordSlow(&scanner.ScanResult{Method: "POST", Host: "dev.target.com", Path: "api", StatusCode: 201, Duration: 2 * time.Second})
+	if err := w.WriteFooter(stats); err != nil {
+		t.Fatal(err)
+	}
+	out := summary.String()
+	if !strings.Contains(out, "Slow responses (over 1s):") {
+		t.Fatalf("missing slow responses header in:\n%s", out)
+	}
+	report, export := strings.Index(out, "/report"), strings.Index(out, "/export")
+	if report < 0 || export < 0 || report > export {
+		t.Errorf("want slowest first, got:\n%s", out)
+	}
+	if !strings.Contains(out, "  2000ms  201  [POST] [dev.target.com] /api\n") {
+		t.Errorf("missing method/host prefix in:\n%s", out)
+	}
+}
+
+func TestTextWriterFooterListings(t *testing.T) {
+	var summary bytes.Buffer
+	w := &TextWriter{w: io.Discard, summary: &summary, noColor: true}
+
+	var stats Stats
+	stats.RecordListing(&scanner.ScanResult{Path: "/uploads/", StatusCode: 200})
+	stats.RecordListing(&scanner.ScanResult{Host: "dev.target.com", Path: "backup/", StatusCode: 200})
+	if err := w.WriteFooter(stats); err != nil {
+		t.Fatal(err)
+	}
+	out := summary.String()
+	for _, want := range []string{"Directory listings (2):", "200  /uploads/", "200  [dev.target.com] /backup/"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("footer missing %q in:\n%s", want, out)
+		}
+	}
+}
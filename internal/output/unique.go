@@ -0,0 +1,69 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+// UniqueKeys are the result fields UniqueWriter can deduplicate on.
+var UniqueKeys = []string{"status", "size", "hash", "words", "lines", "title"}
+
+// UniqueWriter drops results whose chosen fields (e.g. status and size)
+// match a result that was already written (--unique-by). It wraps any
+// other Writer.
+type UniqueWriter struct {
+	inner Writer
+	keys  []string
+	seen  map[string]struct{}
+}
+
+// NewUniqueWriter wraps inner and passes through only the first result for
+// each combination of keys, which must be from UniqueKeys.
+func NewUniqueWriter(inner Writer, keys []string) *UniqueWriter {
+	return &UniqueWriter{inner: inner, keys: keys, seen: make(map[string]struct{})}
+}
+
+func (w *UniqueWriter) WriteHeader() error {
+	return w.inner.WriteHeader()
+}
+
+func (w *UniqueWriter) WriteResult(result *scanner.ScanResult) error {
+	key := w.key(result)
+	if _, dup := w.seen[key]; dup {
+		return nil
+	}
+	w.seen[key] = struct{}{}
+	return w.inner.WriteResult(result)
+}
+
+func (w *UniqueWriter) WriteFooter(stats Stats) error {
+	return w.inner.WriteFooter(stats)
+}
+
+func (w *UniqueWriter) Close() error {
+	return w.inner.Close()
+}
+
+// key joins the chosen fields of result into a map key.
+func (w *UniqueWriter) key(result *scanner.ScanResult) string {
+	parts := make([]string, len(w.keys))
+	for i, k := range w.keys {
+		switch k {
+		case "status":
+			parts[i] = fmt.Sprint(result.StatusCode)
+		case "size":
+			parts[i] = fmt.Sprint(result.ContentLength)
+		case "hash":
+			parts[i] = fmt.Sprintf("%x", result.BodyHash)
+		case "words":
+			parts[i] = fmt.Sprint(result.WordCount)
+		case "lines":
+			parts[i] = fmt.Sprint(result.LineCount)
+		case "title":
+			parts[i] = fmt.Sprintf("%q", result.Title)
+		}
+	}
+	return strings.Join(parts, "|")
+}
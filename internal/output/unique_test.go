@@ -0,0 +1,72 @@
+package output
+
+import (
+	"crypto/md5"
+	"strings"
+	"testing"
+
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+// recordingWriter collects the paths of written results.
+type recordingWriter struct {
+	paths []string
+}
+
+func (r *recordingWriter) WriteHeader() error { return nil }
+func (r *recordingWriter) WriteResult(result *scanner.ScanResult) error {
+	r.paths = append(r.paths, result.Path)
+	return nil
+}
+func (r *recordingWriter) WriteFooter(Stats) error { return nil }
+func (r *recordingWriter) Close() error            { return nil }
+
+func TestUniqueWriter(t *testing.T) {
+	page := md5.Sum([]byte("page"))
+	other := md5.Sum([]byte("other"))
+	results := []scanner.ScanResult{
+		{Path: "a", StatusCode: 200, ContentLength: 100, BodyHash: page},
+		{Path: "b", StatusCode: 200, ContentLength: 100, BodyHash: other}, // same size, different body
+		{Path: "c", StatusCode: 200, ContentLength: 100, BodyHash: page},
+		{Path: "d", StatusCode: 403, ContentLength: 100, BodyHash: page},
+		{Path: "e", StatusCode: 200, ContentLength: 250, BodyHash: other},
+	}
+
+	tests := []struct {
+		keys []string
+		want string
+	}{
+		{[]string{"status", "size"}, "a,d,e"},
+		{[]string{"status", "hash"}, "a,b,d"},
+		{[]string{"size"}, "a,e"},
+	}
+	for _, tt := range tests {
+		rec := &recordingWriter{}
+		w := NewUniqueWriter(rec, tt.keys)
+		for i := range results {
+			if err := w.WriteResult(&results[i]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if got := strings.Join(rec.paths, ","); got != tt.want {
+			t.Errorf("unique by %v: got %s, want %s", tt.keys, got, tt.want)
+		}
+	}
+}
+
+func TestUniqueWriter_Title(t *testing.T) {
+	rec := &recordingWriter{}
+	w := NewUniqueWriter(rec, []string{"status", "title"})
+	for _, r := range []scanner.ScanResult{
+		{Path: "a", StatusCode: 200, Title: "Login"},
+		{Path: "b", StatusCode: 200, Title: "Login"},
+		{Path: "c", StatusCode: 200, Title: "Dashboard"},
+	} {
+		if err := w.WriteResult(&r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(rec.paths) != 2 || rec.paths[1] != "c" {
+		t.Errorf("got %v, want [a c]", rec.paths)
+	}
+}
@@ -0,0 +1,102 @@
+package resume
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// ffufOutput is the part of ffuf's JSON output (-of json) that names the
+// requested URLs.
+type ffufOutput struct {
+	Results []struct {
+		URL string `json:"url"`
+	} `json:"results"`
+}
+
+// Import reads paths another tool already found for baseURL (--skip-file)
+// and returns them as a state with those paths completed, so
+// FilterRemaining drops them from a scan. Recognized formats are ffuf JSON
+// output, gobuster dir output ("/admin (Status: 301) [Size: 178]"), and
+// plain lists of paths or URLs. The returned state has no resume file and
+// must not be saved.
+func Import(file, baseURL string) (*State, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading skip file: %w", err)
+	}
+
+	var found []string
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var out ffufOutput
+		if err := json.Unmarshal(trimmed, &out); err != nil {
+			return nil, fmt.Errorf("parsing ffuf output %s: %w", file, err)
+		}
+		for _, r := range out.Results {
+			found = append(found, r.URL)
+		}
+	} else {
+		found = parseListing(data)
+	}
+
+	s := New("", baseURL, 0)
+	for _, raw := range found {
+		if p := relativePath(raw, baseURL); p != "" {
+			s.MarkCompleted(p)
+		}
+	}
+	return s, nil
+}
+
+// gobusterStatus marks a result line in gobuster dir output.
+const gobusterStatus = "(Status:"
+
+// parseListing extracts the path or URL at the start of each line of
+// gobuster output or a plain list. In gobuster output only result lines
+// count, so its banner and progress lines are skipped.
+func parseListing(data []byte) []string {
+	gobuster := bytes.Contains(data, []byte(gobusterStatus))
+	var found []string
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if gobuster && !strings.Contains(line, gobusterStatus) {
+			continue
+		}
+		found = append(found, strings.Fields(line)[0])
+	}
+	return found
+}
+
+// relativePath turns a found path or URL into the wordlist entry form
+// used in CompletedPaths: relative to baseURL's path, without a leading
+// slash. URLs on another host are dropped.
+func relativePath(raw, baseURL string) string {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return ""
+	}
+	path := raw
+	if strings.HasPrefix(raw, "http://") || strings.HasPrefix(raw, "https://") {
+		u, err := url.Parse(raw)
+		if err != nil || !strings.EqualFold(u.Host, base.Host) {
+			return ""
+		}
+		path = u.Path
+		if prefix := strings.TrimRight(base.Path, "/"); prefix != "" {
+			rest, ok := strings.CutPrefix(path, prefix)
+			if !ok {
+				return ""
+			}
+			path = rest
+		}
+	}
+	return strings.TrimLeft(path, "/")
+}
@@ -0,0 +1,82 @@
+package resume
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeSkipFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "found")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestImport_FFUF(t *testing.T) {
+	path := writeSkipFile(t, `{
+  "commandline": "ffuf -u https://target/app/FUZZ -w words.txt -of json",
+  "results": [
+    {"input": {"FUZZ": "admin"}, "status": 301, "url": "https://target/app/admin"},
+    {"input": {"FUZZ": "login.php"}, "status": 200, "url": "https://target/app/login.php"},
+    {"input": {"FUZZ": "x"}, "status": 200, "url": "https://other/app/x"}
+  ]
+}`)
+	s, err := Import(path, "https://target/app/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"admin", "login.php"}; !reflect.DeepEqual(s.CompletedPaths, want) {
+		t.Errorf("CompletedPaths = %v, want %v", s.CompletedPaths, want)
+	}
+	if got := s.FilterRemaining([]string{"admin", "backup", "login.php", "x"}); !reflect.DeepEqual(got, []string{"backup", "x"}) {
+		t.Errorf("FilterRemaining = %v", got)
+	}
+}
+
+func TestImport_Gobuster(t *testing.T) {
+	path := writeSkipFile(t, `===============================================================
+Gobuster v3.6
+by OJ Reeves (@TheColonial) & Christian Mehlmauer (@firefart)
+===============================================================
+[+] Url:                     http://target
+[+] Threads:                 10
+===============================================================
+Starting gobuster in directory enumeration mode
+===============================================================
+/admin                (Status: 301) [Size: 178] [--> http://target/admin/]
+/index.html           (Status: 200) [Size: 612]
+http://target/images  (Status: 301) [Size: 178]
+Progress: 4614 / 4615 (99.98%)
+===============================================================
+Finished
+===============================================================
+`)
+	s, err := Import(path, "http://target")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"admin", "index.html", "images"}; !reflect.DeepEqual(s.CompletedPaths, want) {
+		t.Errorf("CompletedPaths = %v, want %v", s.CompletedPaths, want)
+	}
+}
+
+func TestImport_PlainList(t *testing.T) {
+	path := writeSkipFile(t, "# found manually\nadmin\n/api/users\n\nhttp://target/backup.zip\n")
+	s, err := Import(path, "http://target")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"admin", "api/users", "backup.zip"}; !reflect.DeepEqual(s.CompletedPaths, want) {
+		t.Errorf("CompletedPaths = %v, want %v", s.CompletedPaths, want)
+	}
+}
+
+func TestImport_InvalidJSON(t *testing.T) {
+	if _, err := Import(writeSkipFile(t, `{"results": [`), "http://target"); err == nil {
+		t.Error("expected error for truncated ffuf JSON")
+	}
+}
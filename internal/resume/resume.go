@@ -4,7 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"sync"
+	"time"
+
+	"github.com/maxvaer/dirfuzz/internal/filter"
 )
 
 // State tracks the progress of a scan so it can be resumed after interruption.
@@ -13,6 +17,18 @@ type State struct {
 	CompletedPaths []string `json:"completed_paths"`
 	TotalPaths     int      `json:"total_paths"`
 
+	// SmartFilter is the calibrated root baseline, reused on resume so
+	// the scan doesn't re-probe and risk a different baseline.
+	SmartFilter *filter.SmartFilter `json:"smart_filter,omitempty"`
+
+	// PendingDirs is the recursion frontier: directories discovered but
+	// not yet fully scanned, mapped to their recursion depth.
+	PendingDirs map[string]int `json:"pending_dirs,omitempty"`
+
+	// CrawlPaths are paths discovered by crawling and queued for a crawl
+	// pass. Entries also in CompletedPaths are already done.
+	CrawlPaths []string `json:"crawl_paths,omitempty"`
+
 	mu   sync.Mutex
 	path string
 	done map[string]struct{}
@@ -71,6 +87,78 @@ func (s *State) MarkCompleted(path string) {
 	}
 }
 
+// SetSmartFilter records the calibrated smart filter for the next save.
+func (s *State) SetSmartFilter(sf *filter.SmartFilter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.SmartFilter = sf
+}
+
+// PendingDir is a directory from the recursion frontier.
+type PendingDir struct {
+	Path  string
+	Depth int
+}
+
+// AddPendingDirs queues dirs for recursion at the given depth. A directory
+// that is already queued keeps its shallower depth.
+func (s *State) AddPendingDirs(depth int, dirs ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.PendingDirs == nil {
+		s.PendingDirs = make(map[string]int)
+	}
+	for _, d := range dirs {
+		if cur, ok := s.PendingDirs[d]; !ok || depth < cur {
+			s.PendingDirs[d] = depth
+		}
+	}
+}
+
+// DonePendingDir removes dir from the recursion frontier.
+func (s *State) DonePendingDir(dir string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.PendingDirs, dir)
+}
+
+// Pending returns the recursion frontier ordered by depth, then path.
+func (s *State) Pending() []PendingDir {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dirs := make([]PendingDir, 0, len(s.PendingDirs))
+	for path, depth := range s.PendingDirs {
+		dirs = append(dirs, PendingDir{Path: path, Depth: depth})
+	}
+	sort.Slice(dirs, func(i, j int) bool {
+		if dirs[i].Depth != dirs[j].Depth {
+			return dirs[i].Depth < dirs[j].Depth
+		}
+		return dirs[i].Path < dirs[j].Path
+	})
+	return dirs
+}
+
+// AddCrawlPaths queues crawl-discovered paths.
+func (s *State) AddCrawlPaths(paths ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.CrawlPaths = append(s.CrawlPaths, paths...)
+}
+
+// RemainingCrawlPaths returns the queued crawl paths not yet completed.
+func (s *State) RemainingCrawlPaths() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var remaining []string
+	for _, p := range s.CrawlPaths {
+		if _, ok := s.done[p]; !ok {
+			remaining = append(remaining, p)
+		}
+	}
+	return remaining
+}
+
 // Save writes the current state to disk.
 func (s *State) Save() error {
 	s.mu.Lock()
@@ -83,6 +171,36 @@ func (s *State) Save() error {
 	return os.WriteFile(s.path, data, 0644)
 }
 
+// Autosave saves the state every interval in the background until the
+// returned stop function is called. stop waits for an in-flight save to
+// finish, so calling it before Remove guarantees the file stays removed.
+// stop is safe to call more than once.
+func (s *State) Autosave(interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				_ = s.Save()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-exited
+		})
+	}
+}
+
 // FilterRemaining returns only paths that haven't been completed yet.
 func (s *State) FilterRemaining(paths []string) []string {
 	s.mu.Lock()
@@ -0,0 +1,77 @@
+package resume
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestState_FrontierRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scan.state")
+	s := New(path, "http://target", 10)
+	s.AddPendingDirs(2, "a/deep")
+	s.AddPendingDirs(1, "b", "a")
+	s.AddPendingDirs(3, "b") // already queued at a shallower depth
+	s.DonePendingDir("a")
+	s.AddCrawlPaths("js/app.js", "api/users")
+	s.MarkCompleted("js/app.js")
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []PendingDir{{Path: "b", Depth: 1}, {Path: "a/deep", Depth: 2}}
+	if got := loaded.Pending(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Pending() = %v, want %v", got, want)
+	}
+	if got := loaded.RemainingCrawlPaths(); !reflect.DeepEqual(got, []string{"api/users"}) {
+		t.Errorf("RemainingCrawlPaths() = %v, want [api/users]", got)
+	}
+}
+
+func TestState_Autosave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scan.state")
+	s := New(path, "http://target", 3)
+	stop := s.Autosave(10 * time.Millisecond)
+	defer stop()
+
+	s.MarkCompleted("admin")
+	s.MarkCompleted("login")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		loaded, err := Load(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if loaded != nil && loaded.IsCompleted("admin") && loaded.IsCompleted("login") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("autosave never wrote the completed paths")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestState_AutosaveStopBeforeRemove(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scan.state")
+	s := New(path, "http://target", 1)
+	stop := s.Autosave(time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+
+	stop()
+	stop() // idempotent
+	if err := s.Remove(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("resume file reappeared after stop+Remove, stat err = %v", err)
+	}
+}
@@ -0,0 +1,19 @@
+package runner
+
+import (
+	"github.com/maxvaer/dirfuzz/internal/output"
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+// baselineCollector keeps what a target reports so the footer can diff it
+// against --baseline. Result loops run on a single goroutine per target,
+// so no locking.
+type baselineCollector struct {
+	output.Writer
+	entries []output.BaselineEntry
+}
+
+func (c *baselineCollector) WriteResult(result *scanner.ScanResult) error {
+	c.entries = append(c.entries, output.NewBaselineEntry(result))
+	return c.Writer.WriteResult(result)
+}
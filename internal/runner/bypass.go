@@ -0,0 +1,208 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/maxvaer/dirfuzz/internal/config"
+	"github.com/maxvaer/dirfuzz/internal/crawl"
+	"github.com/maxvaer/dirfuzz/internal/filter"
+	"github.com/maxvaer/dirfuzz/internal/hook"
+	"github.com/maxvaer/dirfuzz/internal/output"
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+// forbiddenCollector records the 403 results written during a scan so
+// --bypass-403 can retry them with mutated paths afterwards. Like
+// limitWriter it only runs on the target's result loop, so no locking.
+type forbiddenCollector struct {
+	output.Writer
+	items []scanner.WorkItem
+}
+
+func (c *forbiddenCollector) WriteResult(result *scanner.ScanResult) error {
+	if result.StatusCode == http.StatusForbidden {
+		c.items = append(c.items, scanner.WorkItem{Method: result.Method, Path: result.Path, Host: result.Host})
+	}
+	return c.Writer.WriteResult(result)
+}
+
+// hidesForbidden reports whether --include-status or --exclude-status
+// filters out 403s, leaving --bypass-403 nothing to retry.
+func hidesForbidden(opts *config.Options) bool {
+	if len(opts.IncludeStatus) > 0 {
+		return !slices.Contains(opts.IncludeStatus, http.StatusForbidden)
+	}
+	return slices.Contains(opts.ExcludeStatus, http.StatusForbidden)
+}
+
+// bypassVariants returns mutations of path that access rules matching the
+// literal path often miss but servers still route to the same resource:
+// trailing slashes and dots, encoded dots, path parameters, whitespace,
+// and case changes of the last segment.
+func bypassVariants(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	dir, name := "", path
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		dir, name = path[:i+1], path[i+1:]
+	}
+
+	candidates := []string{
+		path + "/",
+		path + "/.",
+		path + "//",
+		dir + "./" + name + "/./",
+		dir + "%2e/" + name,
+		dir + name + "%2e",
+		path + "..;/",
+		path + ";/",
+		path + "%20",
+		path + "%09",
+		path + "?",
+		dir + strings.ToUpper(name),
+		dir + titleCase(name),
+	}
+
+	variants := make([]string, 0, len(candidates))
+	seen := map[string]bool{path: true}
+	for _, v := range candidates {
+		if !seen[v] {
+			seen[v] = true
+			variants = append(variants, v)
+		}
+	}
+	return variants
+}
+
+// titleCase upper-cases the first letter of s.
+func titleCase(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+// runBypassPass probes the --bypass-403 variants of each forbidden item
+// and reports those that answer with something other than 403 and pass
+// the filter chain. Variants already scanned are skipped.
+func runBypassPass(
+	ctx context.Context,
+	opts *config.Options,
+	req *scanner.Requester,
+	chain *filter.Chain,
+	out output.Writer,
+	workerCfg scanner.WorkerConfig,
+	hookRunner *hook.Runner,
+	bodies *output.BodyStore,
+	agg *output.Aggregate,
+	forbidden []scanner.WorkItem,
+	scannedSet map[string]struct{},
+	stats *output.Stats,
+) error {
+	var items []scanner.WorkItem
+	queued := make(map[scanner.WorkItem]struct{})
+	for _, f := range forbidden {
+		for _, v := range bypassVariants(f.Path) {
+			item := scanner.WorkItem{Method: f.Method, Path: v, Host: f.Host}
+			if _, dup := queued[item]; dup || hasKey(scannedSet, v) {
+				continue
+			}
+			queued[item] = struct{}{}
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	vlog := newVerboseLogger(opts)
+	stats.TotalRequests += len(items)
+
+	if !opts.Silent {
+		fmt.Fprintf(os.Stderr, "\n[*] 403 bypass: %d variants of %d forbidden paths\n", len(items), len(forbidden))
+	}
+
+	progress := output.NewProgress(len(items), opts.Silent, opts.NoProgress)
+	if workerCfg.Pauser != nil {
+		progress.SetPauser(workerCfg.Pauser)
+	}
+	if agg != nil {
+		progress.SetParent(agg)
+	}
+	progress.SetHeartbeat(opts.Heartbeat, opts.URL)
+	progress.Start()
+	defer progress.Stop()
+
+	// The variants are encoded by hand; "path?" must reach the server as is.
+	req = req.WithRawPaths()
+	results := scanner.RunWorkerPool(ctx, req, items, workerCfg)
+	if opts.Ordered {
+		results = output.Ordered(results)
+	}
+
+	for result := range results {
+		progress.Increment()
+
+		if result.Error != nil {
+			stats.RecordError(result.ErrorCategory)
+			progress.IncrementErrors()
+			vlog.log(progress, &result)
+			continue
+		}
+		stats.RecordResponse(result.StatusCode, result.ContentLength)
+
+		filtered, reason := result.StatusCode == http.StatusForbidden, "still forbidden"
+		if !filtered {
+			filtered, reason = chain.Apply(&result)
+		}
+		if filtered {
+			result.Filtered = true
+			result.FilterReason = reason
+			stats.FilteredCount++
+			progress.IncrementFiltered()
+			vlog.log(progress, &result)
+			continue
+		}
+
+		progress.IncrementFound()
+		flagSlow(opts, stats, &result)
+		flagListing(opts, stats, &result)
+		vlog.log(progress, &result)
+
+		if bodies != nil {
+			if err := bodies.Save(&result); err != nil && !opts.Silent {
+				progress.ClearLine()
+				fmt.Fprintf(os.Stderr, "[!] %v\n", err)
+				progress.Redraw()
+			}
+		}
+		if opts.ExtractTitle {
+			result.Title = crawl.ExtractTitle(result.Body)
+		}
+		if opts.ProbeMethods {
+			probeMethods(ctx, req, workerCfg.RateLimiter, stats, progress, &result)
+		}
+		if opts.ProbeRange {
+			probeRange(ctx, req, workerCfg.RateLimiter, stats, progress, &result)
+		}
+		result.Body = nil
+
+		progress.ClearLine()
+		if err := out.WriteResult(&result); err != nil {
+			progress.Redraw()
+			return err
+		}
+		progress.Redraw()
+
+		if hookRunner != nil {
+			hookRunner.Run(&result)
+		}
+	}
+	return nil
+}
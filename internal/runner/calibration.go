@@ -0,0 +1,35 @@
+package runner
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/maxvaer/dirfuzz/internal/filter"
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+// calibrationCache holds the smart filters calibrated so far in a
+// multi-target run, so targets that answer unknown paths the same way
+// reuse one instead of being probed again (--share-calibration). It is
+// safe for concurrent use; targets calibrating at the same time may
+// still each do so.
+type calibrationCache struct {
+	mu      sync.Mutex
+	filters []*filter.SmartFilter
+}
+
+// find returns a cached filter that fits the target of req, or nil.
+func (c *calibrationCache) find(ctx context.Context, req *scanner.Requester, calibratePath string) *filter.SmartFilter {
+	c.mu.Lock()
+	filters := slices.Clone(c.filters)
+	c.mu.Unlock()
+	return filter.FindCalibrated(ctx, req, calibratePath, filters)
+}
+
+// add makes sf available to later targets.
+func (c *calibrationCache) add(sf *filter.SmartFilter) {
+	c.mu.Lock()
+	c.filters = append(c.filters, sf)
+	c.mu.Unlock()
+}
@@ -0,0 +1,117 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/maxvaer/dirfuzz/internal/config"
+	"github.com/maxvaer/dirfuzz/internal/output"
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+// runTargetsConcurrently scans up to opts.TargetConcurrency targets at a
+// time. All targets write to one shared output writer, so the output file
+// holds a single header and footer with aggregate stats; with --output-dir
+// each target keeps its own file instead. Per-target banners and progress
+// bars are suppressed since they would interleave.
+func runTargetsConcurrently(ctx context.Context, opts *config.Options, targets []target, pipe pipeline) error {
+	var shared *sharedWriter
+	if opts.OutputDir == "" {
+		out, err := createWriter(opts, opts.OutputFile)
+		if err != nil {
+			return fmt.Errorf("creating output writer: %w", err)
+		}
+		defer out.Close()
+
+		if err := out.WriteHeader(); err != nil {
+			return err
+		}
+
+		shared = &sharedWriter{w: out}
+		pipe.newWriter = func(*config.Options) (output.Writer, error) { return shared, nil }
+	}
+	pipe.detached = true
+
+	if !opts.Silent {
+		fmt.Fprintf(os.Stderr, "[*] Scanning %d targets, %d at a time\n", len(targets), opts.TargetConcurrency)
+	}
+
+	startTime := time.Now()
+	sem := make(chan struct{}, opts.TargetConcurrency)
+	var wg sync.WaitGroup
+	var finished atomic.Int32
+
+dispatch:
+	for _, t := range targets {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break dispatch
+		}
+		wg.Add(1)
+		go func(target target) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			targetOpts := target.options(opts)
+			targetOpts.Silent = true
+			err := runSingleTarget(ctx, targetOpts, pipe)
+			if err != nil && ctx.Err() == nil {
+				fmt.Fprintf(os.Stderr, "[!] Error scanning %s: %v\n", target.URL, err)
+			}
+			if !opts.Silent {
+				fmt.Fprintf(os.Stderr, "[*] Target %d/%d done: %s\n", finished.Add(1), len(targets), target.URL)
+			}
+		}(t)
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if shared == nil {
+		return nil
+	}
+
+	stats := shared.totals()
+	stats.Duration = time.Since(startTime)
+	if stats.Duration.Seconds() > 0 {
+		stats.RequestsPerSec = float64(stats.TotalRequests) / stats.Duration.Seconds()
+	}
+	return shared.w.WriteFooter(stats)
+}
+
+// sharedWriter lets several concurrent target scans write to one output
+// writer, which createWriter has already wrapped in an output.SyncWriter.
+// Headers and Close are left to the owner, and footers only accumulate
+// stats.
+type sharedWriter struct {
+	w     output.Writer
+	mu    sync.Mutex // guards stats
+	stats output.Stats
+}
+
+func (s *sharedWriter) WriteHeader() error { return nil }
+
+func (s *sharedWriter) WriteResult(result *scanner.ScanResult) error {
+	return s.w.WriteResult(result)
+}
+
+func (s *sharedWriter) WriteFooter(stats output.Stats) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats.Add(stats)
+	return nil
+}
+
+func (s *sharedWriter) Close() error { return nil }
+
+func (s *sharedWriter) totals() output.Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats
+}
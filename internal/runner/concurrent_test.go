@@ -0,0 +1,87 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTargetConcurrency(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+
+	var urls []string
+	for i := 0; i < 4; i++ {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				m := maxInFlight.Load()
+				if n <= m || maxInFlight.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+
+			switch r.URL.Path {
+			case "/admin", "/login":
+				fmt.Fprint(w, "page")
+			default:
+				w.WriteHeader(404)
+			}
+		}))
+		defer srv.Close()
+		urls = append(urls, srv.URL)
+	}
+
+	urlsFile := filepath.Join(t.TempDir(), "urls.txt")
+	if err := os.WriteFile(urlsFile, []byte(strings.Join(urls, "\n")), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	wordlist := writeWordlist(t, []string{"admin", "login", "missing"})
+	opts := testOpts(t, "", wordlist)
+	opts.URLsFile = urlsFile
+	opts.Threads = 1
+	opts.TargetConcurrency = 4
+	opts.ExcludeStatus = []int{404}
+	opts.OutputFormat = "json"
+
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+
+	var entries []struct {
+		URL string `json:"url"`
+	}
+	if err := json.Unmarshal([]byte(readOutput(t, opts.OutputFile)), &entries); err != nil {
+		t.Fatalf("output is not a single JSON document: %v", err)
+	}
+
+	got := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		got[e.URL] = true
+	}
+	for _, u := range urls {
+		for _, p := range []string{"/admin", "/login"} {
+			if !got[u+p] {
+				t.Errorf("missing result %s%s", u, p)
+			}
+		}
+	}
+	if len(entries) != len(urls)*2 {
+		t.Errorf("got %d results, want %d", len(entries), len(urls)*2)
+	}
+
+	// With one thread per target, overlapping requests mean targets ran in parallel.
+	if maxInFlight.Load() < 2 {
+		t.Errorf("expected targets to be scanned concurrently, max in-flight = %d", maxInFlight.Load())
+	}
+}
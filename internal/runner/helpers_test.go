@@ -1,9 +1,12 @@
 package runner
 
 import (
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/maxvaer/dirfuzz/internal/config"
+	"github.com/maxvaer/dirfuzz/internal/output"
 	"github.com/maxvaer/dirfuzz/internal/scanner"
 )
 
@@ -243,13 +246,124 @@ func TestIsStaticAssetDir(t *testing.T) {
 		{"api/v1/admin", false},
 	}
 	for _, tt := range tests {
-		got := isStaticAssetDir(tt.dir)
+		got := isNoRecurseDir(tt.dir, staticAssetDirs)
 		if got != tt.want {
-			t.Errorf("isStaticAssetDir(%q) = %v, want %v", tt.dir, got, tt.want)
+			t.Errorf("isNoRecurseDir(%q, staticAssetDirs) = %v, want %v", tt.dir, got, tt.want)
 		}
 	}
 }
 
+func TestNoRecurseDirs(t *testing.T) {
+	tests := []struct {
+		name string
+		opts config.Options
+		dir  string
+		want bool
+	}{
+		{"default kept", config.Options{NoRecurse: []string{"vendor"}}, "css", true},
+		{"user dir", config.Options{NoRecurse: []string{"vendor"}}, "lib/Vendor/", true},
+		{"user dir with slashes", config.Options{NoRecurse: []string{"/node_modules/"}}, "node_modules", true},
+		{"other dir", config.Options{NoRecurse: []string{"vendor"}}, "admin", false},
+		{"reset drops defaults", config.Options{NoRecurse: []string{"vendor"}, NoRecurseReset: true}, "images", false},
+		{"reset keeps user dirs", config.Options{NoRecurse: []string{"vendor"}, NoRecurseReset: true}, "vendor", true},
+		{"no options", config.Options{}, "fonts", true},
+	}
+	for _, tt := range tests {
+		if got := isNoRecurseDir(tt.dir, noRecurseDirs(&tt.opts)); got != tt.want {
+			t.Errorf("%s: isNoRecurseDir(%q) = %v, want %v", tt.name, tt.dir, got, tt.want)
+		}
+	}
+}
+
+func TestIsRecurseOnlyDir(t *testing.T) {
+	names := map[string]struct{}{"admin": {}, "api": {}}
+	tests := []struct {
+		dir  string
+		want bool
+	}{
+		{"admin", true},
+		{"API/", true},
+		{"admin/users", true},
+		{"v1/api/internal", true},
+		{"static", false},
+		{"administrator", false},
+	}
+	for _, tt := range tests {
+		if got := isRecurseOnlyDir(tt.dir, names); got != tt.want {
+			t.Errorf("isRecurseOnlyDir(%q) = %v, want %v", tt.dir, got, tt.want)
+		}
+	}
+}
+
+func TestIsDirectoryListing(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want bool
+	}{
+		{"apache", `<!DOCTYPE HTML PUBLIC "-//W3C//DTD HTML 3.2 Final//EN">
+<html>
+ <head>
+  <title>Index of /uploads</title>
+ </head>
+ <body>
+<h1>Index of /uploads</h1>
+  <table>
+   <tr><th><a href="?C=N;O=D">Name</a></th><th><a href="?C=M;O=A">Last modified</a></th></tr>
+   <tr><td><a href="/">Parent Directory</a></td></tr>
+   <tr><td><a href="report.pdf">report.pdf</a></td></tr>
+  </table>
+</body></html>`, true},
+		{"nginx", `<html>
+<head><title>Index of /backup/</title></head>
+<body>
+<h1>Index of /backup/</h1><hr><pre><a href="../">../</a>
+<a href="db.sql.gz">db.sql.gz</a>                                          12-Mar-2024 10:02    4194304
+</pre><hr></body>
+</html>`, true},
+		{"python http.server", `<!DOCTYPE HTML>
+<html lang="en">
+<head><title>Directory listing for /</title></head>
+<body><h1>Directory listing for /</h1></body></html>`, true},
+		{"heading only", `<html><body><H1>Index of /files</H1></body></html>`, true},
+		{"ordinary page", `<html><head><title>Home</title></head><body><p>Index of /products is below</p></body></html>`, false},
+		{"empty", ``, false},
+	}
+	for _, tt := range tests {
+		if got := isDirectoryListing([]byte(tt.body)); got != tt.want {
+			t.Errorf("%s: isDirectoryListing() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFlagListing(t *testing.T) {
+	listing := []byte("<html><head><title>Index of /files</title></head></html>")
+	page := []byte("<html><head><title>Files</title></head><body>Index of /files</body></html>")
+
+	var stats output.Stats
+	off := &scanner.ScanResult{Path: "files", Body: listing}
+	flagListing(&config.Options{}, &stats, off)
+	if off.Listing || len(stats.Listings) != 0 {
+		t.Error("listing flagged without --detect-listings")
+	}
+
+	opts := &config.Options{DetectListings: true}
+	flagged := &scanner.ScanResult{Method: "GET", Path: "files", StatusCode: 200, Body: listing}
+	plain := &scanner.ScanResult{Path: "about", StatusCode: 200, Body: page}
+	flagListing(opts, &stats, flagged)
+	flagListing(opts, &stats, plain)
+	if !flagged.Listing {
+		t.Error("autoindex body not flagged as a listing")
+	}
+	if plain.Listing {
+		t.Error("page that only mentions \"Index of\" flagged as a listing")
+	}
+	want := []output.Listing{{Method: "GET", Path: "files", StatusCode: 200}}
+	if !slices.Equal(stats.Listings, want) {
+		t.Errorf("Listings = %+v, want %+v", stats.Listings, want)
+	}
+}
+
 func TestResolveMethods(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -283,3 +397,86 @@ func TestResolveMethods(t *testing.T) {
 		})
 	}
 }
+
+func TestHidesForbidden(t *testing.T) {
+	tests := []struct {
+		name    string
+		include []int
+		exclude []int
+		want    bool
+	}{
+		{"no status filter", nil, nil, false},
+		{"exclude 404", nil, []int{404}, false},
+		{"exclude 403", nil, []int{403, 404}, true},
+		{"include without 403", []int{200, 301}, nil, true},
+		{"include 403", []int{200, 403}, nil, false},
+	}
+	for _, tt := range tests {
+		opts := &config.Options{IncludeStatus: tt.include, ExcludeStatus: tt.exclude}
+		if got := hidesForbidden(opts); got != tt.want {
+			t.Errorf("%s: hidesForbidden() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBypassVariants(t *testing.T) {
+	got := bypassVariants("/api/admin/")
+	for _, want := range []string{"api/admin/.", "api/%2e/admin", "api/admin..;/", "api/ADMIN", "api/Admin", "api/./admin/./"} {
+		if !slices.Contains(got, want) {
+			t.Errorf("bypassVariants missing %q: %v", want, got)
+		}
+	}
+	if slices.Contains(got, "api/admin") {
+		t.Error("variants should not include the original path")
+	}
+	if len(bypassVariants("/")) != 0 {
+		t.Error("root has no variants")
+	}
+	// Both case variants of "ADMIN" equal the original and are dropped.
+	if n := len(bypassVariants("ADMIN")); n != len(got)-2 {
+		t.Errorf("got %d variants for ADMIN, want %d", n, len(got)-2)
+	}
+}
+
+func TestHandleKey(t *testing.T) {
+	pauser := scanner.NewPauser()
+	handleKey(' ', pauser, nil, nil, true)
+	if !pauser.IsPaused() {
+		t.Error("Space should pause")
+	}
+	handleKey('\r', pauser, nil, nil, true)
+	if pauser.IsPaused() {
+		t.Error("Enter should resume")
+	}
+
+	scaler := scanner.NewScaler(10)
+	handleKey('+', pauser, scaler, nil, true)
+	handleKey('-', pauser, scaler, nil, true)
+	handleKey('-', pauser, scaler, nil, true)
+	if got := scaler.Target(); got != 10-threadStep {
+		t.Errorf("threads after +, -, - = %d, want %d", got, 10-threadStep)
+	}
+
+	// The skip request reaches a listening scan.
+	skip := make(chan struct{})
+	got := make(chan struct{})
+	go func() {
+		<-skip
+		close(got)
+	}()
+	for received := false; !received; {
+		handleKey('s', pauser, nil, skip, true)
+		select {
+		case <-got:
+			received = true
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	// Nobody listening, or no skip channel at all: the key is dropped.
+	handleKey('s', pauser, nil, skip, true)
+	handleKey('S', pauser, nil, nil, true)
+	if pauser.IsPaused() {
+		t.Error("'s' should not toggle the pauser")
+	}
+}
@@ -0,0 +1,39 @@
+package runner
+
+import (
+	"context"
+
+	"github.com/maxvaer/dirfuzz/internal/output"
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+// limitWriter enforces --max-results: it passes through the first max
+// results, then cancels the target's context so the worker pools of every
+// phase wind down. Results still in flight after that are dropped.
+// Result loops run on a single goroutine per target, so no locking.
+type limitWriter struct {
+	output.Writer
+	max     int
+	written int
+	cancel  context.CancelFunc
+}
+
+func (l *limitWriter) WriteResult(result *scanner.ScanResult) error {
+	if l.written >= l.max {
+		return nil
+	}
+	l.written++
+	if err := l.Writer.WriteResult(result); err != nil {
+		return err
+	}
+	if l.written == l.max {
+		l.cancel()
+	}
+	return nil
+}
+
+// reached reports whether the cap has been hit. It is safe on a nil
+// receiver (no --max-results).
+func (l *limitWriter) reached() bool {
+	return l != nil && l.written >= l.max
+}
@@ -0,0 +1,29 @@
+package runner
+
+import (
+	"regexp"
+
+	"github.com/maxvaer/dirfuzz/internal/config"
+	"github.com/maxvaer/dirfuzz/internal/output"
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+// listingPattern matches the title or heading of an autoindex page:
+// "Index of /..." from Apache, nginx and lighttpd, and "Directory listing
+// for /..." from Python's http.server.
+var listingPattern = regexp.MustCompile(`(?i)<(?:title|h1)[^>]*>\s*(?:index of /|directory listing for /)`)
+
+// isDirectoryListing reports whether body looks like an open directory
+// listing.
+func isDirectoryListing(body []byte) bool {
+	return listingPattern.Match(body)
+}
+
+// flagListing marks a reported result whose body is a directory listing
+// (--detect-listings) and records it for the footer.
+func flagListing(opts *config.Options, stats *output.Stats, result *scanner.ScanResult) {
+	if opts.DetectListings && isDirectoryListing(result.Body) {
+		result.Listing = true
+		stats.RecordListing(result)
+	}
+}
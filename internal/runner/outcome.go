@@ -0,0 +1,90 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	"github.com/maxvaer/dirfuzz/internal/output"
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+// Outcome summarizes how a Run ended, for scripting via the exit code.
+type Outcome int
+
+const (
+	// OutcomeResults means every target completed and at least one result
+	// was reported.
+	OutcomeResults Outcome = iota
+	// OutcomeNoResults means every target completed without results.
+	OutcomeNoResults
+	// OutcomeAborted means the scan was interrupted or a target was
+	// skipped by --max-eta.
+	OutcomeAborted
+)
+
+// Process exit codes returned by ExitCode.
+const (
+	ExitOK        = 0 // completed with results
+	ExitError     = 1 // failed
+	ExitNoResults = 2 // completed with no results
+	ExitAborted   = 3 // interrupted or skipped by --max-eta
+)
+
+// ExitCode maps the result of Run to the process exit code.
+func ExitCode(outcome Outcome, err error) int {
+	if outcome == OutcomeAborted || errors.Is(err, context.Canceled) {
+		return ExitAborted
+	}
+	if err != nil {
+		return ExitError
+	}
+	if outcome == OutcomeNoResults {
+		return ExitNoResults
+	}
+	return ExitOK
+}
+
+// outcomeTracker accumulates the Outcome across all targets of a Run. A
+// nil tracker records nothing.
+type outcomeTracker struct {
+	results atomic.Int64
+	aborted atomic.Bool
+}
+
+// track wraps w so every result written through it is counted.
+func (o *outcomeTracker) track(w output.Writer) output.Writer {
+	if o == nil {
+		return w
+	}
+	return &countingWriter{Writer: w, n: &o.results}
+}
+
+// abort records that a target was cut short.
+func (o *outcomeTracker) abort() {
+	if o != nil {
+		o.aborted.Store(true)
+	}
+}
+
+func (o *outcomeTracker) outcome(ctx context.Context) Outcome {
+	switch {
+	case o.aborted.Load() || ctx.Err() != nil:
+		return OutcomeAborted
+	case o.results.Load() > 0:
+		return OutcomeResults
+	default:
+		return OutcomeNoResults
+	}
+}
+
+// countingWriter counts the results passed to the wrapped writer.
+type countingWriter struct {
+	output.Writer
+	n *atomic.Int64
+}
+
+func (c *countingWriter) WriteResult(result *scanner.ScanResult) error {
+	c.n.Add(1)
+	return c.Writer.WriteResult(result)
+}
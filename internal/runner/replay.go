@@ -0,0 +1,52 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/maxvaer/dirfuzz/internal/config"
+	"github.com/maxvaer/dirfuzz/internal/output"
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+// replayWriter re-sends every reported result through --replay-proxy so
+// matches show up in an intercepting proxy's history while the scan itself
+// goes direct. Like limitWriter it only runs on the target's result loop,
+// so no locking. Replay failures never fail the scan; the first one is
+// reported.
+type replayWriter struct {
+	output.Writer
+	ctx     context.Context
+	req     *scanner.Requester
+	limiter *scanner.RateLimiter // nil = no global rate cap
+	quiet   bool
+	warned  bool
+}
+
+// newReplayRequester builds the requester for --replay-proxy: the scan's
+// options with the proxy swapped for the replay proxy.
+func newReplayRequester(opts *config.Options) (*scanner.Requester, error) {
+	replayOpts := *opts
+	replayOpts.Proxy = opts.ReplayProxy
+	replayOpts.ProxyFile = ""
+	return scanner.NewRequester(&replayOpts)
+}
+
+func (r *replayWriter) WriteResult(result *scanner.ScanResult) error {
+	if err := r.Writer.WriteResult(result); err != nil {
+		return err
+	}
+	if r.limiter != nil {
+		if err := r.limiter.Wait(r.ctx); err != nil {
+			return nil
+		}
+	}
+	if _, err := r.req.Do(r.ctx, result.Method, result.Path, result.Host); err != nil && r.ctx.Err() == nil && !r.warned {
+		r.warned = true
+		if !r.quiet {
+			fmt.Fprintf(os.Stderr, "[!] Replaying %s through --replay-proxy: %v\n", result.Path, err)
+		}
+	}
+	return nil
+}
@@ -1,12 +1,18 @@
 package runner
 
 import (
-	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"slices"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -18,60 +24,150 @@ import (
 	"github.com/maxvaer/dirfuzz/internal/output"
 	"github.com/maxvaer/dirfuzz/internal/resume"
 	"github.com/maxvaer/dirfuzz/internal/scanner"
+	"github.com/maxvaer/dirfuzz/internal/specimport"
 	"github.com/maxvaer/dirfuzz/internal/wordlist"
 	"github.com/maxvaer/dirfuzz/pkg/version"
 )
 
 // Run executes the full scan pipeline. It supports multiple targets via
-// -l (URL list file) and --cidr flags.
-func Run(ctx context.Context, opts *config.Options) error {
+// -l (URL list file) and --cidr flags. The Outcome tells whether results
+// were found or the scan was cut short; see ExitCode.
+func Run(ctx context.Context, opts *config.Options) (Outcome, error) {
 	targets, err := resolveTargets(opts)
 	if err != nil {
-		return err
+		return OutcomeNoResults, err
+	}
+
+	if opts.TimeLimit > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.TimeLimit)
+		defer cancel()
+		defer func() {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) && !opts.Silent {
+				fmt.Fprintf(os.Stderr, "[!] Stopped after --time-limit %s; results are partial\n", opts.TimeLimit)
+			}
+		}()
+	}
+
+	tracker := &outcomeTracker{}
+	pipe := pipeline{
+		newWriter:  newTargetWriter,
+		outcome:    tracker,
+		throttlers: scanner.NewThrottlerRegistry(opts.Delay, opts.AdaptiveThrottle, opts.Silent),
+	}
+	if opts.HARFile != "" {
+		har, err := output.NewHARWriter(opts.HARFile)
+		if err != nil {
+			return OutcomeNoResults, fmt.Errorf("creating HAR file: %w", err)
+		}
+		defer func() {
+			if err := har.Close(); err != nil {
+				fmt.Fprintf(os.Stderr, "[!] Writing HAR file: %v\n", err)
+			}
+		}()
+		pipe.recorder = har
+	}
+	if opts.SummaryFile != "" {
+		summary, err := output.NewSummaryWriter(opts.SummaryFile)
+		if err != nil {
+			return OutcomeNoResults, fmt.Errorf("creating summary file: %w", err)
+		}
+		defer func() {
+			if err := summary.Close(); err != nil {
+				fmt.Fprintf(os.Stderr, "[!] %v\n", err)
+			}
+		}()
+		pipe.summary = summary
+	}
+	if opts.OutputDir != "" {
+		if err := os.MkdirAll(opts.OutputDir, 0755); err != nil {
+			return OutcomeNoResults, fmt.Errorf("creating output directory: %w", err)
+		}
+		assignOutputFiles(opts, targets)
+	}
+	if opts.BaselineFile != "" {
+		baseline, err := output.LoadBaseline(opts.BaselineFile)
+		if err != nil {
+			return OutcomeNoResults, fmt.Errorf("loading baseline: %w", err)
+		}
+		pipe.baseline = baseline
 	}
 
+	if opts.ShareCalibration && len(targets) > 1 {
+		pipe.calibrations = &calibrationCache{}
+	}
+	if opts.Bypass403 && !opts.VHost && !opts.Silent && hidesForbidden(opts) {
+		fmt.Fprintln(os.Stderr, "[!] --bypass-403 has nothing to retry: the status filter hides 403 results")
+	}
+	if opts.OpenAPISpec != "" && !opts.VHost && len(targets) > 0 {
+		// The spec is fetched with the first target's settings (proxy,
+		// TLS, headers) and shared by every target.
+		specOpts := targets[0].options(opts)
+		req, err := scanner.NewRequester(specOpts)
+		if err != nil {
+			return OutcomeNoResults, fmt.Errorf("creating requester: %w", err)
+		}
+		items, err := loadOpenAPI(ctx, specOpts, req)
+		if err != nil {
+			return OutcomeNoResults, err
+		}
+		pipe.specItems = append([]scanner.WorkItem{}, items...)
+	}
+
+	if opts.TargetConcurrency > 1 && len(targets) > 1 {
+		err := runTargetsConcurrently(ctx, opts, targets, pipe)
+		return tracker.outcome(ctx), err
+	}
+
+	if len(targets) > 1 {
+		pipe.skip = make(chan struct{})
+		pipe.aggregate = output.NewAggregate(len(targets))
+	}
+	if err := runTargets(ctx, opts, targets, pipe); err != nil {
+		return OutcomeAborted, err
+	}
+	return tracker.outcome(ctx), nil
+}
+
+// runTargets scans targets one after another. It only returns an error
+// when ctx ends the run; errors of a single target are reported and the
+// next target is scanned.
+func runTargets(ctx context.Context, opts *config.Options, targets []target, pipe pipeline) error {
 	for idx, target := range targets {
+		if ctx.Err() != nil {
+			break
+		}
 		if len(targets) > 1 && !opts.Silent {
-			fmt.Fprintf(os.Stderr, "\n[*] Target %d/%d: %s\n", idx+1, len(targets), target)
+			fmt.Fprintf(os.Stderr, "\n[*] Target %d/%d: %s\n", idx+1, len(targets), target.URL)
 		}
-		opts.URL = target
-		if err := runSingleTarget(ctx, opts); err != nil {
+		err := runSingleTarget(ctx, target.options(opts), pipe)
+		if pipe.aggregate != nil {
+			pipe.aggregate.TargetDone()
+		}
+		if err != nil {
 			if ctx.Err() != nil {
 				return err
 			}
-			fmt.Fprintf(os.Stderr, "[!] Error scanning %s: %v\n", target, err)
+			fmt.Fprintf(os.Stderr, "[!] Error scanning %s: %v\n", target.URL, err)
 		}
 	}
 	return nil
 }
 
-// resolveTargets builds the list of URLs to scan from -u, -l, and --cidr.
-func resolveTargets(opts *config.Options) ([]string, error) {
-	var targets []string
+// resolveTargets builds the list of targets to scan from -u, -l, and --cidr.
+func resolveTargets(opts *config.Options) ([]target, error) {
+	var targets []target
 
 	if opts.URL != "" {
-		targets = append(targets, opts.URL)
+		targets = append(targets, target{URL: opts.URL})
 	}
 
 	if opts.URLsFile != "" {
-		f, err := os.Open(opts.URLsFile)
+		fileTargets, err := loadTargetsFile(opts)
 		if err != nil {
-			return nil, fmt.Errorf("opening URLs file: %w", err)
-		}
-		defer f.Close()
-		sc := bufio.NewScanner(f)
-		for sc.Scan() {
-			line := strings.TrimSpace(sc.Text())
-			if line != "" && !strings.HasPrefix(line, "#") {
-				if !strings.HasPrefix(line, "http://") && !strings.HasPrefix(line, "https://") {
-					line = "http://" + line
-				}
-				targets = append(targets, line)
-			}
-		}
-		if err := sc.Err(); err != nil {
-			return nil, fmt.Errorf("reading URLs file: %w", err)
+			return nil, err
 		}
+		targets = append(targets, fileTargets...)
 	}
 
 	if opts.CIDRTargets != "" {
@@ -79,11 +175,15 @@ func resolveTargets(opts *config.Options) ([]string, error) {
 		if opts.URL != "" && strings.HasPrefix(opts.URL, "http://") {
 			scheme = "http"
 		}
-		cidrURLs, err := netutil.ExpandTargets(opts.CIDRTargets, opts.Ports, scheme)
+		// Stream the range straight into targets rather than building
+		// an intermediate URL list first.
+		cidrURLs, err := netutil.Targets(opts.CIDRTargets, opts.Ports, scheme, opts.MaxHosts, opts.ExcludeHosts)
 		if err != nil {
 			return nil, fmt.Errorf("expanding CIDR: %w", err)
 		}
-		targets = append(targets, cidrURLs...)
+		for u := range cidrURLs {
+			targets = append(targets, target{URL: u})
+		}
 	}
 
 	if len(targets) == 0 {
@@ -92,12 +192,75 @@ func resolveTargets(opts *config.Options) ([]string, error) {
 	return targets, nil
 }
 
-func runSingleTarget(ctx context.Context, opts *config.Options) error {
+// pipeline holds the parts of a target scan that differ between the CLI
+// (Run) and the embedding API (Scan).
+type pipeline struct {
+	newWriter func(opts *config.Options) (output.Writer, error)
+	// detached disables everything that touches the terminal or process
+	// state: stdin pause/resume, signal handlers, and unconditional warnings.
+	detached bool
+	recorder scanner.Recorder      // nil = no HAR recording
+	outcome  *outcomeTracker       // nil = outcome not tracked (Scan)
+	summary  *output.SummaryWriter // nil = no --summary file
+	skip     chan struct{}         // receives the 's' key; nil = single target
+	// throttlers shares adaptive back-off between targets on the same
+	// host; nil = a fresh throttler per target (Scan).
+	throttlers *scanner.ThrottlerRegistry
+	// aggregate counts finished targets and total requests of a sequential
+	// multi-target scan; nil = single target or concurrent targets.
+	aggregate *output.Aggregate
+	baseline  *output.Baseline // nil = no --baseline diff
+	// calibrations shares smart filters between targets; nil = calibrate
+	// every target (no --share-calibration).
+	calibrations *calibrationCache
+	// specItems holds the --openapi endpoints, loaded once for all
+	// targets; nil = load them per target (Scan).
+	specItems []scanner.WorkItem
+}
+
+func runSingleTarget(ctx context.Context, opts *config.Options, pipe pipeline) error {
 	// 1. Load wordlist.
-	paths, err := wordlist.Load(opts.WordlistPath, opts.Extensions, opts.ForceExtensions)
+	var paths []string
+	var err error
+	if opts.WordlistKeyword != "" {
+		paths, err = wordlist.LoadTemplated(opts.WordlistName, opts.WordlistPaths, opts.Extensions, opts.ForceExtensions, opts.WordlistKeyword, opts.KeywordWordlist)
+	} else {
+		paths, err = wordlist.LoadNamed(opts.WordlistName, opts.WordlistPaths, opts.Extensions, opts.ForceExtensions)
+	}
 	if err != nil {
 		return fmt.Errorf("loading wordlist: %w", err)
 	}
+	if opts.Mutate {
+		paths = wordlist.Mutate(paths)
+	}
+	if opts.Prioritize {
+		words := opts.PriorityWords
+		if len(words) == 0 {
+			words = wordlist.PriorityWords
+		}
+		paths = wordlist.Prioritize(paths, words)
+	}
+	if opts.WordlistOffset > 0 || opts.WordlistLimit > 0 {
+		total := len(paths)
+		paths = wordlist.Shard(paths, opts.WordlistOffset, opts.WordlistLimit)
+		if !opts.Silent {
+			if opts.WordlistOffset >= total {
+				fmt.Fprintf(os.Stderr, "[!] --wordlist-offset %d is past the end of the wordlist (%d entries)\n", opts.WordlistOffset, total)
+			} else {
+				fmt.Fprintf(os.Stderr, "[+] Wordlist shard: entries %d-%d of %d\n", opts.WordlistOffset+1, opts.WordlistOffset+len(paths), total)
+			}
+		}
+	}
+	var recursionPaths []string
+	if opts.Recursive && opts.RecursionWordlist != "" {
+		recursionPaths, err = wordlist.Load([]string{opts.RecursionWordlist}, opts.Extensions, opts.ForceExtensions)
+		if err != nil {
+			return fmt.Errorf("loading recursion wordlist: %w", err)
+		}
+		if opts.Mutate {
+			recursionPaths = wordlist.Mutate(recursionPaths)
+		}
+	}
 
 	// 2. Create HTTP requester.
 	req, err := scanner.NewRequester(opts)
@@ -105,8 +268,54 @@ func runSingleTarget(ctx context.Context, opts *config.Options) error {
 		return fmt.Errorf("creating requester: %w", err)
 	}
 
+	specItems := pipe.specItems
+	if specItems == nil && opts.OpenAPISpec != "" && !opts.VHost {
+		if specItems, err = loadOpenAPI(ctx, opts, req); err != nil {
+			return err
+		}
+	}
+	if pipe.recorder != nil {
+		req.SetRecorder(pipe.recorder)
+	}
+
+	// 2b. Seed extra paths from robots.txt and sitemap.xml.
+	var seeds []string
+	if opts.SeedRobots && !opts.VHost {
+		seeds = fetchSeedPaths(ctx, req, opts.URL)
+	}
+
+	// Recursion defaults to the main wordlist plus seeds. It appends
+	// entries to found directories, so it gets them without the prefix and
+	// suffix, and before resume filtering drops completed entries.
+	if recursionPaths == nil {
+		recursionPaths = mergePaths(slices.Clone(paths), seeds)
+	}
+	if opts.Prefix != "" || opts.Suffix != "" {
+		paths = wordlist.Wrap(paths, opts.Prefix, opts.Suffix)
+	}
+	if opts.SeedRobots && !opts.VHost {
+		before := len(paths)
+		paths = mergePaths(paths, seeds)
+		if !opts.Silent {
+			fmt.Fprintf(os.Stderr, "[+] Seeded %d new paths from robots.txt/sitemap.xml\n", len(paths)-before)
+		}
+	}
+
+	// 2c. Fingerprint the favicon (--favicon-hash).
+	if opts.FaviconHash && !opts.Silent {
+		iconURL := faviconURL(opts.URL)
+		if hash, ok := fetchFaviconHash(ctx, req, iconURL); ok {
+			fmt.Fprintf(os.Stderr, "[+] Favicon hash: %d (Shodan: http.favicon.hash:%d)\n", hash, hash)
+		} else {
+			fmt.Fprintf(os.Stderr, "[*] No favicon at %s\n", iconURL)
+		}
+	}
+
 	// 3. Resume support (before banner so path count is accurate).
 	var resumeState *resume.State
+	var resumedDirs []resume.PendingDir
+	var resumedCrawl []string
+	stopResumeSaves := func() {}
 	if opts.ResumeFile != "" {
 		existing, err := resume.Load(opts.ResumeFile)
 		if err != nil {
@@ -116,26 +325,53 @@ func runSingleTarget(ctx context.Context, opts *config.Options) error {
 			resumeState = existing
 			before := len(paths)
 			paths = resumeState.FilterRemaining(paths)
+			resumedDirs = resumeState.Pending()
+			resumedCrawl = resumeState.RemainingCrawlPaths()
 			if !opts.Silent {
 				fmt.Fprintf(os.Stderr, "[+] Resuming: skipping %d already completed paths\n", before-len(paths))
+				if n := len(resumedDirs) + len(resumedCrawl); n > 0 {
+					fmt.Fprintf(os.Stderr, "[+] Resuming: %d pending directories, %d queued crawl paths\n", len(resumedDirs), len(resumedCrawl))
+				}
 			}
 		} else {
 			resumeState = resume.New(opts.ResumeFile, opts.URL, len(paths))
 		}
 
+		// Save periodically so a hard crash loses at most one interval.
+		if opts.ResumeInterval > 0 {
+			stopAutosave := resumeState.Autosave(opts.ResumeInterval)
+			defer stopAutosave()
+			stopResumeSaves = stopAutosave
+		}
+
 		// Save state on interrupt for resume.
-		sigCh := make(chan os.Signal, 1)
-		signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
-		go func() {
-			<-sigCh
-			if resumeState != nil {
-				_ = resumeState.Save()
-				fmt.Fprintf(os.Stderr, "\n[*] Progress saved to %s — resume with --resume-file\n", opts.ResumeFile)
-			}
-		}()
+		if !pipe.detached {
+			sigCh := make(chan os.Signal, 1)
+			signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+			go func() {
+				<-sigCh
+				if resumeState != nil {
+					_ = resumeState.Save()
+					fmt.Fprintf(os.Stderr, "\n[*] Progress saved to %s — resume with --resume-file\n", opts.ResumeFile)
+				}
+			}()
+		}
 	}
 
-	if len(paths) == 0 {
+	// 3b. Skip paths another tool already found (--skip-file).
+	if opts.SkipFile != "" {
+		imported, err := resume.Import(opts.SkipFile, opts.URL)
+		if err != nil {
+			return err
+		}
+		before := len(paths)
+		paths = imported.FilterRemaining(paths)
+		if !opts.Silent {
+			fmt.Fprintf(os.Stderr, "[+] Skipping %d paths listed in %s\n", before-len(paths), opts.SkipFile)
+		}
+	}
+
+	if len(paths) == 0 && len(resumedDirs) == 0 && len(resumedCrawl) == 0 {
 		if !opts.Silent {
 			fmt.Fprintf(os.Stderr, "[+] All paths already completed\n")
 		}
@@ -147,8 +383,43 @@ func runSingleTarget(ctx context.Context, opts *config.Options) error {
 		printBanner(opts, len(paths))
 	}
 
+	// 4b. Set up interactive pause/resume and thread scaling.
+	var pauser *scanner.Pauser
+	scaler := scanner.NewScaler(opts.Threads)
+	if !pipe.detached {
+		var cleanupTerminal func()
+		pauser, cleanupTerminal = startStdinToggle(opts.Silent, scaler, pipe.skip)
+		defer cleanupTerminal()
+	}
+
+	// 4c. Abandon this target when 's' is pressed, from calibration on.
+	// Cancelling ctx ends every phase; a paused scan is resumed so its
+	// workers can exit. A skip is the user's choice, so it doesn't make
+	// the run's outcome aborted.
+	var skipped atomic.Bool
+	if pipe.skip != nil {
+		var cancelTarget context.CancelFunc
+		ctx, cancelTarget = context.WithCancel(ctx)
+		defer cancelTarget()
+		go func() {
+			select {
+			case <-pipe.skip:
+			case <-ctx.Done():
+				return
+			}
+			skipped.Store(true)
+			cancelTarget()
+			if pauser != nil && pauser.IsPaused() {
+				pauser.Toggle()
+			}
+			if !opts.Silent {
+				fmt.Fprintf(os.Stderr, "\r\033[K[*] Skipping %s\n", opts.URL)
+			}
+		}()
+	}
+
 	// 5. Build filter chain.
-	needBody := opts.MatchBody != "" || opts.ExcludeBody != "" || opts.Crawl
+	needBody := opts.MatchBody != "" || opts.ExcludeBody != "" || opts.FilterLoginPages || opts.DetectListings || opts.Crawl || opts.ExtractTitle || opts.SaveBodies != ""
 	chain := filter.NewChain()
 	if len(opts.IncludeStatus) > 0 || len(opts.ExcludeStatus) > 0 {
 		chain.Add(filter.NewStatusFilter(opts.IncludeStatus, opts.ExcludeStatus))
@@ -156,33 +427,104 @@ func runSingleTarget(ctx context.Context, opts *config.Options) error {
 	if len(opts.ExcludeSize) > 0 {
 		chain.Add(filter.NewSizeFilter(opts.ExcludeSize))
 	}
+	if len(opts.MatchWords) > 0 || len(opts.ExcludeWords) > 0 {
+		chain.Add(filter.NewWordCountFilter(opts.MatchWords, opts.ExcludeWords))
+	}
+	if len(opts.MatchLines) > 0 || len(opts.ExcludeLines) > 0 {
+		chain.Add(filter.NewLineCountFilter(opts.MatchLines, opts.ExcludeLines))
+	}
+	if len(opts.ExcludeLengthRanges) > 0 {
+		rf, err := filter.NewSizeRangeFilter(opts.ExcludeLengthRanges)
+		if err != nil {
+			return err
+		}
+		chain.Add(rf)
+	}
+	if opts.MinSize > 0 {
+		chain.Add(filter.NewMinSizeFilter(opts.MinSize))
+	}
+	if len(opts.MatchContentType) > 0 || len(opts.ExcludeContentType) > 0 {
+		chain.Add(filter.NewContentTypeFilter(opts.MatchContentType, opts.ExcludeContentType))
+	}
+	if opts.ExcludeRedirectTo != "" {
+		rf, err := filter.NewRedirectFilter(opts.ExcludeRedirectTo)
+		if err != nil {
+			return fmt.Errorf("--exclude-redirect-to: %w", err)
+		}
+		chain.Add(rf)
+	}
+	if opts.FilterExpr != "" {
+		ef, err := filter.NewExprFilter(opts.FilterExpr)
+		if err != nil {
+			return fmt.Errorf("--filter: %w", err)
+		}
+		chain.Add(ef)
+	}
 
-	// 6. Smart filter calibration.
-	if opts.SmartFilter {
+	// 6. Smart filter calibration (or restore from resume file).
+	wildcard := false
+	if opts.SmartFilter && resumeState != nil && resumeState.SmartFilter != nil {
+		chain.Add(resumeState.SmartFilter)
+		wildcard = resumeState.SmartFilter.Wildcard()
+		if !opts.Silent {
+			fmt.Fprintf(os.Stderr, "[+] Smart filter baseline restored from %s\n", opts.ResumeFile)
+		}
+	} else if sf := findSharedCalibration(ctx, opts, req, pipe); sf != nil {
+		chain.Add(sf)
+		if resumeState != nil {
+			resumeState.SetSmartFilter(sf)
+		}
+		if !opts.Silent {
+			fmt.Fprintf(os.Stderr, "[+] Smart filter reused: %s answers unknown paths like an earlier target\n", opts.URL)
+		}
+	} else if opts.SmartFilter {
 		if !opts.Silent {
 			fmt.Fprintf(os.Stderr, "[*] Calibrating smart filter against %s ...\n", opts.URL)
 		}
 		var sf *filter.SmartFilter
 		var sfErr error
 		if opts.VHost {
-			sf, sfErr = filter.NewSmartFilterVHost(ctx, req, opts.URL, opts.SmartFilterThreshold)
+			sf, sfErr = filter.NewSmartFilterVHost(ctx, req, opts.URL, opts.SmartFilterThreshold, opts.SmartFilterProbes, opts.Threads)
 		} else {
-			sf, sfErr = filter.NewSmartFilter(ctx, req, "", opts.SmartFilterThreshold)
+			sf, sfErr = filter.NewSmartFilter(ctx, req, "", opts.CalibrateURL, opts.SmartFilterThreshold, opts.SmartFilterProbes, opts.Threads)
 		}
 		if sfErr != nil {
-			fmt.Fprintf(os.Stderr, "[!] Smart filter disabled: %v\n", sfErr)
+			if !pipe.detached && ctx.Err() == nil {
+				fmt.Fprintf(os.Stderr, "[!] Smart filter disabled: %v\n", sfErr)
+			}
 		} else {
 			chain.Add(sf)
+			if resumeState != nil {
+				resumeState.SetSmartFilter(sf)
+			}
+			if pipe.calibrations != nil && !opts.VHost {
+				pipe.calibrations.add(sf)
+			}
 			if !opts.Silent {
 				fmt.Fprintf(os.Stderr, "[+] Smart filter ready\n")
 			}
+			wildcard = sf.Wildcard()
 		}
 	}
 	// Duplicate filter catches catch-all routes that serve the same
 	// page for every subpath (e.g. /app/login/*) — these evade smart
 	// filter calibration because the probes hit a different route.
-	if opts.DuplicateThreshold > 0 {
-		chain.Add(filter.NewDuplicateFilter(opts.DuplicateThreshold))
+	duplicateThreshold := opts.DuplicateThreshold
+	if wildcard {
+		hint := " (try --ignore-wildcard-dns)"
+		if opts.IgnoreWildcardDNS {
+			// Wildcard pages often embed the requested host name, so
+			// they slip past the exact-hash baseline; only let the
+			// first of each response through.
+			duplicateThreshold = 1
+			hint = ", hiding repeated responses"
+		}
+		if !opts.Silent {
+			fmt.Fprintf(os.Stderr, "[!] %s answers every host name with the same page (wildcard DNS/vhost); expect false positives%s\n", opts.URL, hint)
+		}
+	}
+	if duplicateThreshold > 0 {
+		chain.Add(filter.NewDuplicateFilter(duplicateThreshold))
 	}
 
 	// Body filters (added after smart filter so they run on remaining results).
@@ -192,37 +534,118 @@ func runSingleTarget(ctx context.Context, opts *config.Options) error {
 	if opts.ExcludeBody != "" {
 		chain.Add(filter.NewBodyExcludeFilter(opts.ExcludeBody))
 	}
+	if opts.FilterLoginPages {
+		chain.Add(filter.NewLoginPageFilter())
+	}
 
 	// 7. Create output writer.
-	out, err := createWriter(opts)
+	out, err := pipe.newWriter(opts)
 	if err != nil {
 		return fmt.Errorf("creating output writer: %w", err)
 	}
 	defer out.Close()
+	out = pipe.outcome.track(out)
+	var found atomic.Int64
+	out = &countingWriter{Writer: out, n: &found}
+	var reported *baselineCollector
+	if pipe.baseline != nil {
+		reported = &baselineCollector{Writer: out}
+		out = reported
+	}
 
 	if err := out.WriteHeader(); err != nil {
 		return err
 	}
 
-	// 8. Create throttler and hook runner.
-	throttler := scanner.NewThrottler(opts.Delay, opts.AdaptiveThrottle, opts.Silent)
+	// 7a. Remember 403s for the bypass pass (--bypass-403).
+	var forbidden *forbiddenCollector
+	if opts.Bypass403 && !opts.VHost {
+		forbidden = &forbiddenCollector{Writer: out}
+		out = forbidden
+	}
+
+	// 7a2. Re-send reported results through --replay-proxy.
+	var replay *replayWriter
+	if opts.ReplayProxy != "" {
+		replayReq, err := newReplayRequester(opts)
+		if err != nil {
+			return fmt.Errorf("creating replay requester: %w", err)
+		}
+		replay = &replayWriter{Writer: out, ctx: ctx, req: replayReq, quiet: opts.Silent}
+		out = replay
+	}
+
+	// 7b. Cap the number of results (--max-results). Hitting the cap cancels
+	// ctx, which ends the current phase and skips the rest.
+	var limit *limitWriter
+	if opts.MaxResults > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+		limit = &limitWriter{Writer: out, max: opts.MaxResults, cancel: cancel}
+		out = limit
+	}
+
+	// 7c. Drop results structurally identical to an earlier one (--unique-by).
+	// It wraps the limit so that only results actually written count
+	// towards --max-results.
+	if len(opts.UniqueBy) > 0 {
+		out = output.NewUniqueWriter(out, opts.UniqueBy)
+	}
+
+	// 8. Create throttler and hook runner. Run shares one throttler per
+	// host across targets.
+	var throttler *scanner.Throttler
+	if pipe.throttlers != nil {
+		throttler = pipe.throttlers.Get(opts.URL)
+	} else {
+		throttler = scanner.NewThrottler(opts.Delay, opts.AdaptiveThrottle, opts.Silent)
+	}
 
 	var hookRunner *hook.Runner
 	if opts.OnResultCmd != "" {
 		hookRunner = hook.NewRunner(opts.OnResultCmd, opts.Silent)
 	}
+	vlog := newVerboseLogger(opts)
+
+	scope, err := crawl.NewScope(opts.ScopeInclude, opts.ScopeExclude)
+	if err != nil {
+		return fmt.Errorf("crawl scope: %w", err)
+	}
+
+	var bodies *output.BodyStore
+	if opts.SaveBodies != "" {
+		bodies, err = output.NewBodyStore(opts.SaveBodies, opts.MaxBodySize)
+		if err != nil {
+			return err
+		}
+	}
 
+	jitter, err := scanner.ParseJitter(opts.DelayJitter, opts.Delay)
+	if err != nil {
+		return fmt.Errorf("--delay-jitter: %w", err)
+	}
 	workerCfg := scanner.WorkerConfig{
-		Threads:   opts.Threads,
-		Throttler: throttler,
-		KeepBody:  needBody,
+		Threads:     opts.Threads,
+		Throttler:   throttler,
+		RateLimiter: scanner.NewRateLimiter(opts.RateLimit),
+		Jitter:      jitter,
+		KeepBody:    needBody,
+	}
+	if replay != nil {
+		replay.limiter = workerCfg.RateLimiter
 	}
 
-	// 8b. Set up interactive pause/resume.
-	pauser, cleanupTerminal := startStdinToggle(opts.Silent)
-	defer cleanupTerminal()
+	workerCfg.Pauser = pauser
 	if pauser != nil {
-		workerCfg.Pauser = pauser
+		workerCfg.Scaler = scaler
+	}
+
+	// halted reports whether the remaining phases should be skipped in favor
+	// of the footer: --max-results was hit, --time-limit ran out, or the
+	// target was skipped.
+	halted := func() bool {
+		return limit.reached() || errors.Is(ctx.Err(), context.DeadlineExceeded) || skipped.Load()
 	}
 
 	// 9. Build work items and run worker pool.
@@ -243,12 +666,17 @@ func runSingleTarget(ctx context.Context, opts *config.Options) error {
 		}
 	} else {
 		items = expandItems(paths, methods)
+		items = appendNewItems(items, specItems)
 	}
 
-	progress := output.NewProgress(len(items), opts.Silent)
+	progress := output.NewProgress(len(items), opts.Silent, opts.NoProgress)
 	if pauser != nil {
 		progress.SetPauser(pauser)
 	}
+	if pipe.aggregate != nil {
+		progress.SetParent(pipe.aggregate)
+	}
+	progress.SetHeartbeat(opts.Heartbeat, opts.URL)
 	progress.Start()
 	startTime := time.Now()
 
@@ -256,9 +684,13 @@ func runSingleTarget(ctx context.Context, opts *config.Options) error {
 	defer workerCancel()
 
 	results := scanner.RunWorkerPool(workerCtx, req, items, workerCfg)
+	if opts.Ordered {
+		results = output.Ordered(results)
+	}
 
 	var stats output.Stats
 	stats.TotalRequests = len(items)
+	stats.SlowThreshold = opts.SlowThreshold
 
 	var discoveredDirs []string
 	var crawledPaths []string
@@ -267,6 +699,7 @@ func runSingleTarget(ctx context.Context, opts *config.Options) error {
 		scannedSet[item.Path] = struct{}{}
 	}
 	seenDirs := make(map[string]struct{})
+	noRecurse := noRecurseDirs(opts)
 
 	// ETA-based skip: check after a minimum number of requests for stable estimate.
 	etaCheckAfter := int64(100)
@@ -274,6 +707,9 @@ func runSingleTarget(ctx context.Context, opts *config.Options) error {
 		etaCheckAfter = n // 5% of total, whichever is larger
 	}
 	etaSkipped := false
+	stoppedEarly := false
+	errorsAborted := false
+	consecutiveErrors := 0
 
 	for result := range results {
 		progress.Increment()
@@ -290,6 +726,7 @@ func runSingleTarget(ctx context.Context, opts *config.Options) error {
 					}
 					workerCancel()
 					etaSkipped = true
+					pipe.outcome.abort()
 					break
 				}
 			}
@@ -300,10 +737,26 @@ func runSingleTarget(ctx context.Context, opts *config.Options) error {
 		}
 
 		if result.Error != nil {
-			stats.ErrorCount++
+			stats.RecordError(result.ErrorCategory)
 			progress.IncrementErrors()
+			vlog.log(progress, &result)
+			consecutiveErrors++
+			if opts.AbortOnErrors > 0 && consecutiveErrors >= opts.AbortOnErrors {
+				if !opts.Silent {
+					progress.ClearLine()
+					fmt.Fprintf(os.Stderr, "[!] Skipping %s: %d consecutive errors (--abort-on-errors), last: %v\n",
+						opts.URL, consecutiveErrors, result.Error)
+					progress.Redraw()
+				}
+				workerCancel()
+				errorsAborted = true
+				pipe.outcome.abort()
+				break
+			}
 			continue
 		}
+		consecutiveErrors = 0
+		stats.RecordResponse(result.StatusCode, result.ContentLength)
 
 		// Apply filter chain.
 		filtered, reason := chain.Apply(&result)
@@ -312,34 +765,57 @@ func runSingleTarget(ctx context.Context, opts *config.Options) error {
 			result.FilterReason = reason
 			stats.FilteredCount++
 			progress.IncrementFiltered()
+			vlog.log(progress, &result)
 			continue
 		}
 
 		progress.IncrementFound()
+		flagSlow(opts, &stats, &result)
+		flagListing(opts, &stats, &result)
+		vlog.log(progress, &result)
 
 		// Extract links before clearing body.
 		if opts.Crawl && result.Body != nil {
-			newPaths := crawl.ExtractPaths(result.Body, opts.URL)
+			newPaths := scope.Filter(crawl.ExtractPaths(result.Body, opts.URL))
 			for _, p := range newPaths {
 				if _, already := scannedSet[p]; !already {
 					crawledPaths = append(crawledPaths, p)
 					scannedSet[p] = struct{}{}
+					queueCrawlPath(resumeState, p)
 				}
 			}
 			// Infer directories from crawled paths for recursive scanning and tree output.
-			if (opts.Recursive || opts.Tree) && !opts.VHost {
+			if collectsDirs(opts) && !opts.VHost {
 				for _, p := range newPaths {
 					for _, dir := range extractParentDirs(p, opts.MaxDepth) {
 						key := normalizeDirKey(dir)
 						if _, already := seenDirs[key]; !already {
 							discoveredDirs = append(discoveredDirs, dir)
 							seenDirs[key] = struct{}{}
+							queuePendingDir(opts, resumeState, 1, dir)
 						}
 					}
 				}
 			}
 		}
 
+		if bodies != nil {
+			if err := bodies.Save(&result); err != nil && !opts.Silent {
+				progress.ClearLine()
+				fmt.Fprintf(os.Stderr, "[!] %v\n", err)
+				progress.Redraw()
+			}
+		}
+		if opts.ExtractTitle {
+			result.Title = crawl.ExtractTitle(result.Body)
+		}
+		if opts.ProbeMethods {
+			probeMethods(ctx, req, workerCfg.RateLimiter, &stats, progress, &result)
+		}
+		if opts.ProbeRange {
+			probeRange(ctx, req, workerCfg.RateLimiter, &stats, progress, &result)
+		}
+
 		// Clear body to free memory after filtering and crawling.
 		result.Body = nil
 
@@ -355,71 +831,135 @@ func runSingleTarget(ctx context.Context, opts *config.Options) error {
 			hookRunner.Run(&result)
 		}
 
+		if opts.StopOnFirst {
+			if !opts.Silent {
+				progress.ClearLine()
+				fmt.Fprintf(os.Stderr, "[+] Stopping after first match (--stop-on-first)\n")
+				progress.Redraw()
+			}
+			workerCancel()
+			stoppedEarly = true
+			break
+		}
+
 		// Collect directories for recursive scanning and tree output.
-		if (opts.Recursive || opts.Tree) && !opts.VHost && looksLikeDirectory(result) {
+		if collectsDirs(opts) && !opts.VHost && isRecursionCandidate(opts, result) {
 			dir := strings.TrimRight(result.Path, "/")
 			key := normalizeDirKey(dir)
 			if _, already := seenDirs[key]; !already {
-				if isStaticAssetDir(dir) {
+				if isNoRecurseDir(dir, noRecurse) {
 					if !opts.Silent {
 						progress.ClearLine()
-						fmt.Fprintf(os.Stderr, "[*] Skipping /%s/ (static asset directory)\n", dir)
+						fmt.Fprintf(os.Stderr, "[*] Skipping /%s/ (static asset or --no-recurse directory)\n", dir)
 						progress.Redraw()
 					}
 				} else {
 					discoveredDirs = append(discoveredDirs, dir)
+					queuePendingDir(opts, resumeState, 1, dir)
 				}
 				seenDirs[key] = struct{}{}
 			}
 		}
 	}
 
-	// If target was skipped due to ETA, drain remaining results and return.
-	if etaSkipped {
+	// If target was skipped due to ETA, errors, or the 's' key, or stopped
+	// at the first match, drain remaining results and return without
+	// recursion or crawling.
+	if etaSkipped || stoppedEarly || errorsAborted || skipped.Load() {
 		for range results {
 			// drain channel
 		}
 		progress.Stop()
+		if stoppedEarly || errorsAborted || skipped.Load() {
+			stats.TotalRequests = int(progress.Completed())
+		}
 		stats.Duration = time.Since(startTime)
+		summary := output.NewTargetSummary(opts.URL, stats, int(found.Load()), discoveredDirs)
+		summary.Aborted = true
+		pipe.summary.Add(summary)
 		return out.WriteFooter(stats)
 	}
 
 	// Stop main progress bar before recursive/crawl phases (they create their own).
 	progress.Stop()
+	if halted() {
+		stats.TotalRequests = int(progress.Completed())
+	}
 
 	// 10. Periodic resume save.
 	if resumeState != nil {
 		_ = resumeState.Save()
 	}
 
+	// 10b. Continue the recursion and crawl frontier of an interrupted scan.
+	// Depth-1 directories join this run's discoveries; deeper ones are
+	// resumed level by level after it.
+	var deeperDirs []resume.PendingDir
+	for _, pd := range resumedDirs {
+		if pd.Depth > 1 {
+			deeperDirs = append(deeperDirs, pd)
+			continue
+		}
+		if key := normalizeDirKey(pd.Path); !hasKey(seenDirs, key) {
+			discoveredDirs = append(discoveredDirs, pd.Path)
+			seenDirs[key] = struct{}{}
+		}
+	}
+	for _, p := range resumedCrawl {
+		if !hasKey(scannedSet, p) {
+			crawledPaths = append(crawledPaths, p)
+			scannedSet[p] = struct{}{}
+		}
+	}
+
 	// 11. Recursive scanning (breadth-first).
-	if opts.Recursive && !opts.VHost && len(discoveredDirs) > 0 {
-		err := runRecursive(ctx, opts, req, chain, out, throttler, hookRunner, needBody, discoveredDirs, paths, methods, &stats, resumeState, pauser, 1)
-		if err != nil {
+	if opts.Recursive && !opts.VHost && len(discoveredDirs) > 0 && !halted() {
+		err := runRecursive(ctx, opts, req, chain, out, workerCfg, hookRunner, bodies, pipe.aggregate, discoveredDirs, recursionPaths, methods, &stats, resumeState, 1)
+		if err != nil && !halted() {
+			return err
+		}
+	}
+	for len(deeperDirs) > 0 && opts.Recursive && !halted() {
+		depth := deeperDirs[0].Depth
+		var level []string
+		for len(deeperDirs) > 0 && deeperDirs[0].Depth == depth {
+			level = append(level, deeperDirs[0].Path)
+			deeperDirs = deeperDirs[1:]
+		}
+		err := runRecursive(ctx, opts, req, chain, out, workerCfg, hookRunner, bodies, pipe.aggregate, level, recursionPaths, methods, &stats, resumeState, depth)
+		if err != nil && !halted() {
 			return err
 		}
 	}
 
 	// 12. Crawl passes.
 	var crawlDirs []string
-	if opts.Crawl && len(crawledPaths) > 0 {
+	if opts.Crawl && len(crawledPaths) > 0 && !halted() {
 		var err error
-		crawlDirs, err = runCrawlPasses(ctx, opts, req, chain, out, throttler, hookRunner, needBody, crawledPaths, scannedSet, methods, &stats, resumeState, pauser, 1)
-		if err != nil {
+		crawlDirs, err = runCrawlPasses(ctx, opts, req, chain, out, workerCfg, hookRunner, bodies, pipe.aggregate, scope, crawledPaths, scannedSet, methods, &stats, resumeState, 1)
+		if err != nil && !halted() {
 			return err
 		}
 		// Recursively scan directories discovered during crawling.
-		if opts.Recursive && !opts.VHost && len(crawlDirs) > 0 {
-			err := runRecursive(ctx, opts, req, chain, out, throttler, hookRunner, needBody, crawlDirs, paths, methods, &stats, resumeState, pauser, 1)
-			if err != nil {
+		if opts.Recursive && !opts.VHost && len(crawlDirs) > 0 && !halted() {
+			err := runRecursive(ctx, opts, req, chain, out, workerCfg, hookRunner, bodies, pipe.aggregate, crawlDirs, recursionPaths, methods, &stats, resumeState, 1)
+			if err != nil && !halted() {
 				return err
 			}
 		}
 	}
 
+	// 12b. Retry forbidden paths with bypass mutations.
+	if forbidden != nil && len(forbidden.items) > 0 && !halted() {
+		err := runBypassPass(ctx, opts, req, chain, out, workerCfg, hookRunner, bodies, pipe.aggregate, forbidden.items, scannedSet, &stats)
+		if err != nil && !halted() {
+			return err
+		}
+	}
+
 	// 13. Print directory tree if requested.
+	allDirs := append(discoveredDirs, crawlDirs...)
 	if opts.Tree && !opts.Silent {
-		allDirs := append(discoveredDirs, crawlDirs...)
 		output.PrintTree(os.Stderr, allDirs)
 	}
 
@@ -429,37 +969,60 @@ func runSingleTarget(ctx context.Context, opts *config.Options) error {
 		stats.RequestsPerSec = float64(stats.TotalRequests) / stats.Duration.Seconds()
 	}
 
-	// Clean up resume file on successful completion.
+	summary := output.NewTargetSummary(opts.URL, stats, int(found.Load()), allDirs)
+	summary.Aborted = skipped.Load()
+	pipe.summary.Add(summary)
+
+	// Clean up resume file on successful completion. A scan cut short by
+	// --time-limit or the 's' key keeps it so the rest can be resumed.
 	if resumeState != nil {
-		_ = resumeState.Remove()
+		stopResumeSaves()
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) || skipped.Load() {
+			_ = resumeState.Save()
+		} else {
+			_ = resumeState.Remove()
+		}
 	}
 
-	return out.WriteFooter(stats)
+	if err := out.WriteFooter(stats); err != nil {
+		return err
+	}
+	// Only a complete scan can tell which paths went away.
+	if reported != nil && ctx.Err() == nil && !skipped.Load() && !opts.Silent {
+		diff := pipe.baseline.Compare(opts.URL, reported.entries)
+		return output.WriteDiff(os.Stderr, opts.BaselineFile, opts.URL, diff)
+	}
+	return nil
 }
 
+// runRecursive scans each directory in dirs with recursionPaths appended,
+// then recurses into newly found directories until opts.MaxDepth.
 func runRecursive(
 	ctx context.Context,
 	opts *config.Options,
 	req *scanner.Requester,
 	chain *filter.Chain,
 	out output.Writer,
-	throttler *scanner.Throttler,
+	workerCfg scanner.WorkerConfig,
 	hookRunner *hook.Runner,
-	needBody bool,
+	bodies *output.BodyStore,
+	agg *output.Aggregate,
 	dirs []string,
-	basePaths []string,
+	recursionPaths []string,
 	methods []string,
 	stats *output.Stats,
 	resumeState *resume.State,
-	pauser *scanner.Pauser,
 	depth int,
 ) error {
 	if depth > opts.MaxDepth {
 		return nil
 	}
+	vlog := newVerboseLogger(opts)
 
 	// Deduplicate incoming dirs (case-insensitive, ignore trailing slash).
 	dirs = deduplicateDirs(dirs)
+	noRecurse := noRecurseDirs(opts)
+	dirs = filterRecurseOnly(opts, dirs, resumeState)
 
 	// Find parent smart filter for directory probe check.
 	var parentSF *filter.SmartFilter
@@ -497,16 +1060,22 @@ func runRecursive(
 						fmt.Fprintf(os.Stderr, "\n[*] Skipping /%s/ (directory page matches soft-404 baseline)\n",
 							strings.TrimRight(dir, "/"))
 					}
+					if resumeState != nil {
+						resumeState.DonePendingDir(dir)
+					}
 					continue
 				}
 			}
 		}
 
 		// Build new paths by prepending the discovered directory.
-		newPaths := make([]string, len(basePaths))
-		for i, p := range basePaths {
+		newPaths := make([]string, len(recursionPaths))
+		for i, p := range recursionPaths {
 			newPaths[i] = strings.TrimRight(dir, "/") + "/" + strings.TrimLeft(p, "/")
 		}
+		if resumeState != nil {
+			newPaths = resumeState.FilterRemaining(newPaths)
+		}
 
 		if !opts.Silent {
 			fmt.Fprintf(os.Stderr, "\n[*] Recursing into /%s/ (depth %d/%d, %d paths)\n",
@@ -514,17 +1083,22 @@ func runRecursive(
 		}
 
 		// Build per-directory filter chain: copy static filters, recalibrate smart + duplicate.
+		// With --filter-duplicate-global the parent's duplicate filter is shared instead.
 		dirChain := filter.NewChain()
 		for _, f := range chain.Filters() {
 			switch f.(type) {
-			case *filter.SmartFilter, *filter.DuplicateFilter:
-				// Skip — these are recreated per directory below.
+			case *filter.SmartFilter:
+				// Skip — recalibrated per directory below.
+			case *filter.DuplicateFilter:
+				if opts.GlobalDuplicate {
+					dirChain.Add(f)
+				}
 			default:
 				dirChain.Add(f)
 			}
 		}
 		if opts.SmartFilter {
-			sf, err := filter.NewSmartFilter(ctx, req, dir, opts.SmartFilterThreshold)
+			sf, err := filter.NewSmartFilter(ctx, req, dir, opts.CalibrateURL, opts.SmartFilterThreshold, opts.SmartFilterProbes, opts.Threads)
 			if err == nil {
 				dirChain.Add(sf)
 				if !opts.Silent {
@@ -532,27 +1106,27 @@ func runRecursive(
 				}
 			}
 		}
-		if opts.DuplicateThreshold > 0 {
+		if opts.DuplicateThreshold > 0 && !opts.GlobalDuplicate {
 			dirChain.Add(filter.NewDuplicateFilter(opts.DuplicateThreshold))
 		}
 
-		workerCfg := scanner.WorkerConfig{
-			Threads:   opts.Threads,
-			Throttler: throttler,
-			KeepBody:  needBody,
-			Pauser:    pauser,
-		}
-
 		newItems := expandItems(newPaths, methods)
 
 		// Create a fresh progress bar for this directory.
-		progress := output.NewProgress(len(newItems), opts.Silent)
-		if pauser != nil {
-			progress.SetPauser(pauser)
+		progress := output.NewProgress(len(newItems), opts.Silent, opts.NoProgress)
+		if workerCfg.Pauser != nil {
+			progress.SetPauser(workerCfg.Pauser)
 		}
+		if agg != nil {
+			progress.SetParent(agg)
+		}
+		progress.SetHeartbeat(opts.Heartbeat, opts.URL)
 		progress.Start()
 
 		results := scanner.RunWorkerPool(ctx, req, newItems, workerCfg)
+		if opts.Ordered {
+			results = output.Ordered(results)
+		}
 		stats.TotalRequests += len(newItems)
 
 		for result := range results {
@@ -563,10 +1137,12 @@ func runRecursive(
 			}
 
 			if result.Error != nil {
-				stats.ErrorCount++
+				stats.RecordError(result.ErrorCategory)
 				progress.IncrementErrors()
+				vlog.log(progress, &result)
 				continue
 			}
+			stats.RecordResponse(result.StatusCode, result.ContentLength)
 
 			filtered, reason := dirChain.Apply(&result)
 			if filtered {
@@ -574,10 +1150,30 @@ func runRecursive(
 				result.FilterReason = reason
 				stats.FilteredCount++
 				progress.IncrementFiltered()
+				vlog.log(progress, &result)
 				continue
 			}
 
 			progress.IncrementFound()
+			flagSlow(opts, stats, &result)
+			flagListing(opts, stats, &result)
+			vlog.log(progress, &result)
+			if bodies != nil {
+				if err := bodies.Save(&result); err != nil && !opts.Silent {
+					progress.ClearLine()
+					fmt.Fprintf(os.Stderr, "[!] %v\n", err)
+					progress.Redraw()
+				}
+			}
+			if opts.ExtractTitle {
+				result.Title = crawl.ExtractTitle(result.Body)
+			}
+			if opts.ProbeMethods {
+				probeMethods(ctx, req, workerCfg.RateLimiter, stats, progress, &result)
+			}
+			if opts.ProbeRange {
+				probeRange(ctx, req, workerCfg.RateLimiter, stats, progress, &result)
+			}
 			result.Body = nil
 
 			progress.ClearLine()
@@ -592,12 +1188,13 @@ func runRecursive(
 				hookRunner.Run(&result)
 			}
 
-			if looksLikeDirectory(result) {
+			if isRecursionCandidate(opts, result) {
 				dir := strings.TrimRight(result.Path, "/")
 				key := normalizeDirKey(dir)
 				if _, already := seenDirs[key]; !already {
-					if !isStaticAssetDir(dir) {
+					if !isNoRecurseDir(dir, noRecurse) {
 						nextDirs = append(nextDirs, dir)
+						queuePendingDir(opts, resumeState, depth+1, dir)
 					}
 					seenDirs[key] = struct{}{}
 				}
@@ -605,6 +1202,9 @@ func runRecursive(
 		}
 
 		progress.Stop()
+		if resumeState != nil && ctx.Err() == nil {
+			resumeState.DonePendingDir(dir)
+		}
 	}
 
 	if resumeState != nil {
@@ -612,12 +1212,130 @@ func runRecursive(
 	}
 
 	if len(nextDirs) > 0 {
-		return runRecursive(ctx, opts, req, chain, out, throttler, hookRunner, needBody, nextDirs, basePaths, methods, stats, resumeState, pauser, depth+1)
+		return runRecursive(ctx, opts, req, chain, out, workerCfg, hookRunner, bodies, agg, nextDirs, recursionPaths, methods, stats, resumeState, depth+1)
 	}
 
 	return nil
 }
 
+// fetchSeedPaths requests the host-root /robots.txt and /sitemap.xml and
+// returns the same-origin paths they list. Missing or failing files are
+// ignored.
+func fetchSeedPaths(ctx context.Context, req *scanner.Requester, baseURL string) []string {
+	var seeds []string
+	if body, err := req.Fetch(ctx, hostRootURL(baseURL, "robots.txt")); err == nil {
+		seeds = append(seeds, crawl.ParseRobots(body, baseURL)...)
+	}
+	if body, err := req.Fetch(ctx, hostRootURL(baseURL, "sitemap.xml")); err == nil {
+		seeds = append(seeds, crawl.ParseSitemap(body, baseURL)...)
+	}
+	return seeds
+}
+
+// faviconURL returns the host-root /favicon.ico of target, ignoring any
+// base path, since that is the icon Shodan's http.favicon.hash indexes.
+func faviconURL(target string) string {
+	return hostRootURL(target, "favicon.ico")
+}
+
+// hostRootURL returns the URL of name at the root of target's host,
+// ignoring any base path.
+func hostRootURL(target, name string) string {
+	u, err := url.Parse(target)
+	if err != nil {
+		return strings.TrimRight(target, "/") + "/" + name
+	}
+	return u.Scheme + "://" + u.Host + "/" + name
+}
+
+// fetchFaviconHash requests iconURL and returns its Shodan-style hash.
+// ok is false if there is no favicon.
+func fetchFaviconHash(ctx context.Context, req *scanner.Requester, iconURL string) (hash int32, ok bool) {
+	body, err := req.Fetch(ctx, iconURL)
+	if err != nil || len(body) == 0 {
+		return 0, false
+	}
+	return crawl.FaviconHash(body), true
+}
+
+// probeMethods sends an OPTIONS request for result (--probe-methods) and
+// records the methods the Allow header lists. It waits on the global rate
+// limit like a worker would and counts the request in stats and progress;
+// failures leave result unchanged.
+func probeMethods(ctx context.Context, req *scanner.Requester, limiter *scanner.RateLimiter, stats *output.Stats, progress *output.Progress, result *scanner.ScanResult) {
+	if limiter != nil {
+		if err := limiter.Wait(ctx); err != nil {
+			return
+		}
+	}
+	countProbe(stats, progress)
+	if resp, err := req.Do(ctx, http.MethodOptions, result.Path, result.Host); err == nil {
+		result.Allow = resp.Allow
+	}
+}
+
+// probeRange re-requests result with "Range: bytes=0-0" (--probe-range)
+// and records whether the server answers 206 Partial Content, along with
+// the full size its Content-Range reports. File servers and static
+// handlers support ranges; most application routes ignore them. Like
+// probeMethods it waits on the global rate limit, counts the request and
+// leaves result unchanged on failure.
+func probeRange(ctx context.Context, req *scanner.Requester, limiter *scanner.RateLimiter, stats *output.Stats, progress *output.Progress, result *scanner.ScanResult) {
+	if limiter != nil {
+		if err := limiter.Wait(ctx); err != nil {
+			return
+		}
+	}
+	countProbe(stats, progress)
+	resp, err := req.WithHeader("Range", "bytes=0-0").Do(ctx, result.Method, result.Path, result.Host)
+	if err == nil && resp.StatusCode == http.StatusPartialContent {
+		result.AcceptsRange = true
+		result.FullSize = resp.RangeTotal
+	}
+}
+
+// countProbe records a per-result probe request, which comes on top of the
+// scanned items, in the request count and the progress bar.
+func countProbe(stats *output.Stats, progress *output.Progress) {
+	stats.TotalRequests++
+	progress.AddTotal(1)
+	progress.Increment()
+}
+
+// appendNewItems appends entries from extra whose method and path are not
+// already in items.
+func appendNewItems(items, extra []scanner.WorkItem) []scanner.WorkItem {
+	if len(extra) == 0 {
+		return items
+	}
+	seen := make(map[scanner.WorkItem]struct{}, len(items))
+	for _, it := range items {
+		seen[it] = struct{}{}
+	}
+	for _, it := range extra {
+		if _, ok := seen[it]; !ok {
+			seen[it] = struct{}{}
+			items = append(items, it)
+		}
+	}
+	return items
+}
+
+// mergePaths appends entries from extra that are not already in paths.
+func mergePaths(paths, extra []string) []string {
+	seen := make(map[string]struct{}, len(paths))
+	for _, p := range paths {
+		seen[p] = struct{}{}
+	}
+	for _, p := range extra {
+		if _, ok := seen[p]; !ok {
+			seen[p] = struct{}{}
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
 // extractParentDirs returns intermediate directory segments of a path,
 // limited to maxDepth levels. For example, "/js/asset/login.js" with
 // maxDepth=3 returns ["js", "js/asset"].
@@ -653,18 +1371,83 @@ var staticAssetDirs = map[string]struct{}{
 	"assets": {}, "media": {}, ".hg": {},
 }
 
-// isStaticAssetDir returns true if the last segment of the directory path
-// is a common static asset directory name that is not worth recursing into.
-func isStaticAssetDir(dir string) bool {
+// StaticAssetDirs returns the sorted directory names that are never recursed
+// into by default, for help text.
+func StaticAssetDirs() []string {
+	return slices.Sorted(maps.Keys(staticAssetDirs))
+}
+
+// noRecurseDirs returns the lower-case directory names not to recurse
+// into: staticAssetDirs plus --no-recurse, or only --no-recurse with
+// --no-recurse-reset.
+func noRecurseDirs(opts *config.Options) map[string]struct{} {
+	dirs := make(map[string]struct{}, len(staticAssetDirs)+len(opts.NoRecurse))
+	if !opts.NoRecurseReset {
+		for name := range staticAssetDirs {
+			dirs[name] = struct{}{}
+		}
+	}
+	for _, name := range opts.NoRecurse {
+		if name = strings.ToLower(strings.Trim(name, "/ ")); name != "" {
+			dirs[name] = struct{}{}
+		}
+	}
+	return dirs
+}
+
+// isNoRecurseDir reports whether the last segment of the directory path
+// is one of names (lower-case), compared case-insensitively.
+func isNoRecurseDir(dir string, names map[string]struct{}) bool {
 	dir = strings.TrimRight(dir, "/")
 	lastSeg := dir
 	if idx := strings.LastIndex(dir, "/"); idx >= 0 {
 		lastSeg = dir[idx+1:]
 	}
-	_, ok := staticAssetDirs[strings.ToLower(lastSeg)]
+	_, ok := names[strings.ToLower(lastSeg)]
 	return ok
 }
 
+// filterRecurseOnly drops directories outside the --recurse-only allowlist.
+// A directory is kept when any of its path segments is allowlisted, so the
+// subdirectories of an allowlisted directory still recurse up to --max-depth.
+func filterRecurseOnly(opts *config.Options, dirs []string, resumeState *resume.State) []string {
+	allow := make(map[string]struct{}, len(opts.RecurseOnly))
+	for _, name := range opts.RecurseOnly {
+		if name = strings.ToLower(strings.Trim(name, "/ ")); name != "" {
+			allow[name] = struct{}{}
+		}
+	}
+	if len(allow) == 0 {
+		return dirs
+	}
+
+	kept := dirs[:0:0]
+	for _, dir := range dirs {
+		if isRecurseOnlyDir(dir, allow) {
+			kept = append(kept, dir)
+			continue
+		}
+		if !opts.Silent {
+			fmt.Fprintf(os.Stderr, "[*] Skipping /%s/ (not in --recurse-only)\n", strings.TrimRight(dir, "/"))
+		}
+		if resumeState != nil {
+			resumeState.DonePendingDir(dir)
+		}
+	}
+	return kept
+}
+
+// isRecurseOnlyDir reports whether any segment of the directory path is
+// one of names (lower-case), compared case-insensitively.
+func isRecurseOnlyDir(dir string, names map[string]struct{}) bool {
+	for _, seg := range strings.Split(strings.Trim(dir, "/"), "/") {
+		if _, ok := names[strings.ToLower(seg)]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 // deduplicateDirs returns dirs with case-insensitive, slash-normalized
 // duplicates removed, keeping the first occurrence.
 func deduplicateDirs(dirs []string) []string {
@@ -680,6 +1463,43 @@ func deduplicateDirs(dirs []string) []string {
 	return out
 }
 
+// queuePendingDir records dir in the resume frontier so an interrupted
+// scan can still recurse into it. Directories beyond --max-depth are
+// never scanned and so are not queued.
+func queuePendingDir(opts *config.Options, state *resume.State, depth int, dir string) {
+	if state != nil && opts.Recursive && depth <= opts.MaxDepth {
+		state.AddPendingDirs(depth, dir)
+	}
+}
+
+// queueCrawlPath records a crawl-discovered path in the resume frontier.
+func queueCrawlPath(state *resume.State, path string) {
+	if state != nil {
+		state.AddCrawlPaths(path)
+	}
+}
+
+func hasKey(set map[string]struct{}, key string) bool {
+	_, ok := set[key]
+	return ok
+}
+
+// collectsDirs reports whether discovered directories are needed: for
+// recursion, the --tree output, or the --summary file.
+func collectsDirs(opts *config.Options) bool {
+	return opts.Recursive || opts.Tree || opts.SummaryFile != ""
+}
+
+// isRecursionCandidate reports whether result is a directory worth
+// recursing into: it must look like a directory and, if --recursion-status
+// is set, have one of those status codes.
+func isRecursionCandidate(opts *config.Options, result scanner.ScanResult) bool {
+	if len(opts.RecursionStatus) > 0 && !slices.Contains(opts.RecursionStatus, result.StatusCode) {
+		return false
+	}
+	return looksLikeDirectory(result)
+}
+
 func looksLikeDirectory(result scanner.ScanResult) bool {
 	if strings.HasSuffix(result.Path, "/") {
 		return true
@@ -700,16 +1520,58 @@ func looksLikeDirectory(result scanner.ScanResult) bool {
 	return false
 }
 
-func createWriter(opts *config.Options) (output.Writer, error) {
+// findSharedCalibration returns a smart filter calibrated on an earlier
+// target that fits this one (--share-calibration), or nil. Vhost
+// calibration depends on the target's host name, so it is never shared.
+func findSharedCalibration(ctx context.Context, opts *config.Options, req *scanner.Requester, pipe pipeline) *filter.SmartFilter {
+	if !opts.SmartFilter || opts.VHost || pipe.calibrations == nil {
+		return nil
+	}
+	return pipe.calibrations.find(ctx, req, opts.CalibrateURL)
+}
+
+// loadOpenAPI loads the --openapi endpoints, fetching a spec URL through
+// req.
+func loadOpenAPI(ctx context.Context, opts *config.Options, req *scanner.Requester) ([]scanner.WorkItem, error) {
+	items, err := specimport.LoadOpenAPI(ctx, opts.OpenAPISpec, req)
+	if err != nil {
+		return nil, err
+	}
+	if !opts.Silent {
+		fmt.Fprintf(os.Stderr, "[+] Loaded %d endpoints from OpenAPI spec\n", len(items))
+	}
+	return items, nil
+}
+
+// flagSlow marks a reported result that took longer than --slow-threshold
+// and records it for the footer.
+func flagSlow(opts *config.Options, stats *output.Stats, result *scanner.ScanResult) {
+	if opts.SlowThreshold > 0 && result.Duration > opts.SlowThreshold {
+		result.Slow = true
+		stats.RecordSlow(result)
+	}
+}
+
+// createWriter creates the output writer for opts, writing to path (""
+// for stdout).
+func createWriter(opts *config.Options, path string) (output.Writer, error) {
 	var w output.Writer
 	var err error
 	switch opts.OutputFormat {
 	case "json":
-		w, err = output.NewJSONWriter(opts.OutputFile)
+		w, err = output.NewJSONWriter(path, opts.OutputAppend, opts.Fields)
 	case "csv":
-		w, err = output.NewCSVWriter(opts.OutputFile)
+		w, err = output.NewCSVWriter(path, opts.OutputAppend, opts.ShowHeaders, opts.Fields)
+	case "html":
+		w, err = output.NewHTMLWriter(path)
+	case "md":
+		w, err = output.NewMarkdownWriter(path)
 	default:
-		w, err = output.NewTextWriter(opts.OutputFile, opts.NoColor, opts.Silent, opts.FullURL)
+		var scheme map[int]string
+		if scheme, err = output.ParseColorScheme(opts.ColorScheme); err != nil {
+			return nil, fmt.Errorf("--color-scheme: %w", err)
+		}
+		w, err = output.NewTextWriter(path, opts.NoColor, opts.Silent, opts.FullURL, opts.OutputAppend, opts.TruncateURL, scheme)
 	}
 	if err != nil {
 		return nil, err
@@ -717,9 +1579,81 @@ func createWriter(opts *config.Options) (output.Writer, error) {
 	if opts.SortBy != "" {
 		w = output.NewSortedWriter(w, opts.SortBy)
 	}
+	if opts.TargetConcurrency > 1 {
+		w = output.NewSyncWriter(w)
+	}
 	return w, nil
 }
 
+// newTargetWriter creates the output writer of one target: opts.OutputFile,
+// or with --output-dir a file named after the target (see outputPath).
+func newTargetWriter(opts *config.Options) (output.Writer, error) {
+	return createWriter(opts, outputPath(opts))
+}
+
+// outputExtensions maps --format to the extension of --output-dir files.
+var outputExtensions = map[string]string{
+	"json": ".json",
+	"csv":  ".csv",
+	"html": ".html",
+	"md":   ".md",
+}
+
+// outputPath returns the output file of the target in opts. With
+// --output-dir it is named after the target's host and port, plus its
+// base path if any, e.g. "example.com_443.json" or
+// "10.0.0.5_8080_app.txt". A file already assigned by assignOutputFiles
+// wins.
+func outputPath(opts *config.Options) string {
+	if opts.OutputDir == "" || opts.OutputFile != "" {
+		return opts.OutputFile
+	}
+	name := opts.URL
+	if u, err := url.Parse(opts.URL); err == nil && u.Hostname() != "" {
+		port := u.Port()
+		if port == "" {
+			port = "80"
+			if u.Scheme == "https" {
+				port = "443"
+			}
+		}
+		name = u.Hostname() + "_" + port
+		if p := strings.Trim(u.Path, "/"); p != "" {
+			name += "_" + p
+		}
+	}
+	name = strings.Map(func(r rune) rune {
+		if r == '.' || r == '-' || r == '_' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' {
+			return r
+		}
+		return '_'
+	}, name)
+	ext, ok := outputExtensions[opts.OutputFormat]
+	if !ok {
+		ext = ".txt"
+	}
+	return filepath.Join(opts.OutputDir, name+ext)
+}
+
+// assignOutputFiles gives every target its own --output-dir file. Targets
+// whose names collide, such as http://h/a-b and http://h/a_b or URLs that
+// differ only in the query, get a numeric suffix ("h_80_a_b_2.txt")
+// instead of overwriting each other. Names are compared case-insensitively
+// for case-insensitive file systems.
+func assignOutputFiles(opts *config.Options, targets []target) {
+	used := make(map[string]bool, len(targets))
+	for i := range targets {
+		path := outputPath(targets[i].options(opts))
+		ext := filepath.Ext(path)
+		base := strings.TrimSuffix(path, ext)
+		for n := 2; used[strings.ToLower(path)]; n++ {
+			path = fmt.Sprintf("%s_%d%s", base, n, ext)
+		}
+		used[strings.ToLower(path)] = true
+		targets[i].outputFile = path
+	}
+}
+
 func resolveMethods(opts *config.Options) []string {
 	if len(opts.Methods) > 0 {
 		methods := make([]string, len(opts.Methods))
@@ -747,20 +1681,22 @@ func runCrawlPasses(
 	req *scanner.Requester,
 	chain *filter.Chain,
 	out output.Writer,
-	throttler *scanner.Throttler,
+	workerCfg scanner.WorkerConfig,
 	hookRunner *hook.Runner,
-	needBody bool,
+	bodies *output.BodyStore,
+	agg *output.Aggregate,
+	scope *crawl.Scope,
 	newPaths []string,
 	scannedSet map[string]struct{},
 	methods []string,
 	stats *output.Stats,
 	resumeState *resume.State,
-	pauser *scanner.Pauser,
 	depth int,
 ) ([]string, error) {
 	if depth > opts.CrawlDepth || len(newPaths) == 0 {
 		return nil, nil
 	}
+	vlog := newVerboseLogger(opts)
 
 	items := expandItems(newPaths, methods)
 	stats.TotalRequests += len(items)
@@ -771,20 +1707,20 @@ func runCrawlPasses(
 	}
 
 	// Create a fresh progress bar for this crawl pass.
-	progress := output.NewProgress(len(items), opts.Silent)
-	if pauser != nil {
-		progress.SetPauser(pauser)
+	progress := output.NewProgress(len(items), opts.Silent, opts.NoProgress)
+	if workerCfg.Pauser != nil {
+		progress.SetPauser(workerCfg.Pauser)
 	}
-	progress.Start()
-
-	workerCfg := scanner.WorkerConfig{
-		Threads:   opts.Threads,
-		Throttler: throttler,
-		KeepBody:  needBody,
-		Pauser:    pauser,
+	if agg != nil {
+		progress.SetParent(agg)
 	}
+	progress.SetHeartbeat(opts.Heartbeat, opts.URL)
+	progress.Start()
 
 	results := scanner.RunWorkerPool(ctx, req, items, workerCfg)
+	if opts.Ordered {
+		results = output.Ordered(results)
+	}
 
 	var nextPaths []string
 	var crawlDirs []string
@@ -796,10 +1732,12 @@ func runCrawlPasses(
 			resumeState.MarkCompleted(result.Path)
 		}
 		if result.Error != nil {
-			stats.ErrorCount++
+			stats.RecordError(result.ErrorCategory)
 			progress.IncrementErrors()
+			vlog.log(progress, &result)
 			continue
 		}
+		stats.RecordResponse(result.StatusCode, result.ContentLength)
 
 		filtered, reason := chain.Apply(&result)
 		if filtered {
@@ -807,32 +1745,54 @@ func runCrawlPasses(
 			result.FilterReason = reason
 			stats.FilteredCount++
 			progress.IncrementFiltered()
+			vlog.log(progress, &result)
 			continue
 		}
 
 		progress.IncrementFound()
+		flagSlow(opts, stats, &result)
+		flagListing(opts, stats, &result)
+		vlog.log(progress, &result)
 
 		// Extract links before clearing body.
 		if result.Body != nil {
-			discovered := crawl.ExtractPaths(result.Body, opts.URL)
+			discovered := scope.Filter(crawl.ExtractPaths(result.Body, opts.URL))
 			for _, p := range discovered {
 				if _, already := scannedSet[p]; !already {
 					nextPaths = append(nextPaths, p)
 					scannedSet[p] = struct{}{}
+					queueCrawlPath(resumeState, p)
 				}
 			}
 			// Infer directories from crawled paths for recursive scanning and tree output.
-			if (opts.Recursive || opts.Tree) && !opts.VHost {
+			if collectsDirs(opts) && !opts.VHost {
 				for _, p := range discovered {
 					for _, dir := range extractParentDirs(p, opts.MaxDepth) {
 						if _, already := scannedSet[dir+"/"]; !already {
 							crawlDirs = append(crawlDirs, dir)
 							scannedSet[dir+"/"] = struct{}{}
+							queuePendingDir(opts, resumeState, 1, dir)
 						}
 					}
 				}
 			}
 		}
+		if bodies != nil {
+			if err := bodies.Save(&result); err != nil && !opts.Silent {
+				progress.ClearLine()
+				fmt.Fprintf(os.Stderr, "[!] %v\n", err)
+				progress.Redraw()
+			}
+		}
+		if opts.ExtractTitle {
+			result.Title = crawl.ExtractTitle(result.Body)
+		}
+		if opts.ProbeMethods {
+			probeMethods(ctx, req, workerCfg.RateLimiter, stats, progress, &result)
+		}
+		if opts.ProbeRange {
+			probeRange(ctx, req, workerCfg.RateLimiter, stats, progress, &result)
+		}
 		result.Body = nil
 
 		progress.ClearLine()
@@ -851,7 +1811,7 @@ func runCrawlPasses(
 	progress.Stop()
 
 	if len(nextPaths) > 0 {
-		moreDirs, err := runCrawlPasses(ctx, opts, req, chain, out, throttler, hookRunner, needBody, nextPaths, scannedSet, methods, stats, resumeState, pauser, depth+1)
+		moreDirs, err := runCrawlPasses(ctx, opts, req, chain, out, workerCfg, hookRunner, bodies, agg, scope, nextPaths, scannedSet, methods, stats, resumeState, depth+1)
 		if err != nil {
 			return nil, err
 		}
@@ -2,16 +2,27 @@ package runner
 
 import (
 	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/maxvaer/dirfuzz/internal/config"
+	"github.com/maxvaer/dirfuzz/internal/output"
+	"github.com/maxvaer/dirfuzz/internal/scanner"
 )
 
 func writeWordlist(t *testing.T, words []string) string {
@@ -26,15 +37,16 @@ func writeWordlist(t *testing.T, words []string) string {
 func testOpts(t *testing.T, serverURL, wordlistPath string) *config.Options {
 	t.Helper()
 	return &config.Options{
-		URL:          serverURL,
-		WordlistPath: wordlistPath,
-		Threads:      2,
-		Timeout:      5 * time.Second,
-		Silent:        true,
-		NoColor:      true,
-		OutputFile:   filepath.Join(t.TempDir(), "output.txt"),
-		OutputFormat: "text",
-		SmartFilter:  false,
+		URL:               serverURL,
+		WordlistPaths:     []string{wordlistPath},
+		Threads:           2,
+		Timeout:           5 * time.Second,
+		Silent:            true,
+		NoColor:           true,
+		OutputFile:        filepath.Join(t.TempDir(), "output.txt"),
+		OutputFormat:      "text",
+		SmartFilter:       false,
+		SmartFilterProbes: 5,
 	}
 }
 
@@ -67,7 +79,7 @@ func TestBasicScan(t *testing.T) {
 	opts := testOpts(t, srv.URL, wordlist)
 	opts.ExcludeStatus = []int{404}
 
-	if err := Run(context.Background(), opts); err != nil {
+	if _, err := Run(context.Background(), opts); err != nil {
 		t.Fatal(err)
 	}
 
@@ -103,7 +115,7 @@ func TestSmartFilterRemovesSoft404s(t *testing.T) {
 	opts.SmartFilter = true
 	opts.SmartFilterThreshold = 50
 
-	if err := Run(context.Background(), opts); err != nil {
+	if _, err := Run(context.Background(), opts); err != nil {
 		t.Fatal(err)
 	}
 
@@ -136,7 +148,7 @@ func TestMethodFuzzing(t *testing.T) {
 	opts.Methods = []string{"GET", "POST"}
 	opts.ExcludeStatus = []int{404}
 
-	if err := Run(context.Background(), opts); err != nil {
+	if _, err := Run(context.Background(), opts); err != nil {
 		t.Fatal(err)
 	}
 
@@ -171,7 +183,7 @@ func TestCrawlDiscovery(t *testing.T) {
 	opts.CrawlDepth = 2
 	opts.ExcludeStatus = []int{404}
 
-	if err := Run(context.Background(), opts); err != nil {
+	if _, err := Run(context.Background(), opts); err != nil {
 		t.Fatal(err)
 	}
 
@@ -217,7 +229,7 @@ func TestRecursiveSkipsSoft404Directories(t *testing.T) {
 	opts.Crawl = false
 	opts.DuplicateThreshold = 0 // disable to isolate smart filter behavior
 
-	if err := Run(context.Background(), opts); err != nil {
+	if _, err := Run(context.Background(), opts); err != nil {
 		t.Fatal(err)
 	}
 
@@ -250,7 +262,7 @@ func TestETASkip(t *testing.T) {
 	opts.MaxETA = 1 * time.Second
 
 	start := time.Now()
-	if err := Run(context.Background(), opts); err != nil {
+	if _, err := Run(context.Background(), opts); err != nil {
 		t.Fatal(err)
 	}
 	elapsed := time.Since(start)
@@ -261,3 +273,1823 @@ func TestETASkip(t *testing.T) {
 		t.Errorf("expected ETA skip to abort quickly, but took %s", elapsed)
 	}
 }
+
+func TestDurationInOutput(t *testing.T) {
+	const delay = 50 * time.Millisecond
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(delay)
+			w.WriteHeader(200)
+			fmt.Fprint(w, "slow page")
+			return
+		}
+		w.WriteHeader(404)
+	}))
+	defer srv.Close()
+
+	wordlist := writeWordlist(t, []string{"slow"})
+
+	t.Run("json", func(t *testing.T) {
+		opts := testOpts(t, srv.URL, wordlist)
+		opts.OutputFormat = "json"
+		opts.ExcludeStatus = []int{404}
+		if _, err := Run(context.Background(), opts); err != nil {
+			t.Fatal(err)
+		}
+		var entries []struct {
+			Path       string `json:"path"`
+			DurationMs int64  `json:"duration_ms"`
+		}
+		if err := json.Unmarshal([]byte(readOutput(t, opts.OutputFile)), &entries); err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 {
+			t.Fatalf("expected 1 entry, got %d", len(entries))
+		}
+		if entries[0].DurationMs < delay.Milliseconds() {
+			t.Errorf("duration_ms = %d, want >= %d", entries[0].DurationMs, delay.Milliseconds())
+		}
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		opts := testOpts(t, srv.URL, wordlist)
+		opts.OutputFormat = "csv"
+		opts.ExcludeStatus = []int{404}
+		if _, err := Run(context.Background(), opts); err != nil {
+			t.Fatal(err)
+		}
+		records, err := csv.NewReader(strings.NewReader(readOutput(t, opts.OutputFile))).ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != 2 {
+			t.Fatalf("expected header + 1 row, got %d records", len(records))
+		}
+		col := len(records[0]) - 1
+		if records[0][col] != "duration" {
+			t.Fatalf("last header column = %q, want duration", records[0][col])
+		}
+		ms, err := strconv.Atoi(records[1][col])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if int64(ms) < delay.Milliseconds() {
+			t.Errorf("duration = %d, want >= %d", ms, delay.Milliseconds())
+		}
+	})
+
+	t.Run("text", func(t *testing.T) {
+		opts := testOpts(t, srv.URL, wordlist)
+		opts.Silent = false
+		opts.ExcludeStatus = []int{404}
+		opts.MaxETA = 0
+		if _, err := Run(context.Background(), opts); err != nil {
+			t.Fatal(err)
+		}
+		out := readOutput(t, opts.OutputFile)
+		if !strings.Contains(out, "Time") {
+			t.Errorf("expected Time column heading, got:\n%s", out)
+		}
+		var line string
+		for _, l := range strings.Split(out, "\n") {
+			if strings.Contains(l, "/slow") {
+				line = l
+			}
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 4 || !strings.HasSuffix(fields[2], "ms") {
+			t.Fatalf("expected millisecond column in %q", line)
+		}
+		ms, err := strconv.Atoi(strings.TrimSuffix(fields[2], "ms"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if int64(ms) < delay.Milliseconds() {
+			t.Errorf("time column = %dms, want >= %d", ms, delay.Milliseconds())
+		}
+	})
+}
+
+func TestWordCountFilterWithoutBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/three":
+			fmt.Fprint(w, "one two three")
+		default:
+			fmt.Fprint(w, "just two")
+		}
+	}))
+	defer srv.Close()
+
+	wordlist := writeWordlist(t, []string{"three", "other"})
+	opts := testOpts(t, srv.URL, wordlist)
+	opts.Crawl = false // body is not retained
+	opts.ExcludeWords = []int{2}
+
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+
+	out := readOutput(t, opts.OutputFile)
+	if !strings.Contains(out, "/three") {
+		t.Errorf("expected /three in output, got:\n%s", out)
+	}
+	if strings.Contains(out, "/other") {
+		t.Errorf("unexpected /other — 2-word response should be excluded:\n%s", out)
+	}
+}
+
+func TestSeedRobots(t *testing.T) {
+	// Seed files live at the host root even when the target has a base path.
+	for _, base := range []string{"/", "/app/"} {
+		t.Run("base="+base, func(t *testing.T) {
+			var mu sync.Mutex
+			requested := make(map[string]int)
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				requested[r.URL.Path]++
+				mu.Unlock()
+				switch r.URL.Path {
+				case "/robots.txt":
+					fmt.Fprint(w, "User-agent: *\nDisallow: /secret-area/\nAllow: /admin\n")
+				case "/sitemap.xml":
+					fmt.Fprintf(w, "<urlset><url><loc>http://%s/from-sitemap</loc></url></urlset>", r.Host)
+				case base + "secret-area", base + "from-sitemap", base + "admin":
+					fmt.Fprint(w, "found "+r.URL.Path)
+				default:
+					w.WriteHeader(404)
+				}
+			}))
+			defer srv.Close()
+
+			wordlist := writeWordlist(t, []string{"admin"})
+			opts := testOpts(t, srv.URL+base, wordlist)
+			opts.SeedRobots = true
+			opts.ExcludeStatus = []int{404}
+
+			if _, err := Run(context.Background(), opts); err != nil {
+				t.Fatal(err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			for _, p := range []string{"/robots.txt", "/sitemap.xml"} {
+				if requested[p] != 1 {
+					t.Errorf("expected %s to be fetched once, got %d", p, requested[p])
+				}
+			}
+			for _, p := range []string{base + "secret-area", base + "from-sitemap"} {
+				if requested[p] == 0 {
+					t.Errorf("expected seeded path %s to be scanned", p)
+				}
+			}
+			// "admin" is in both the wordlist and robots.txt; it must be scanned once.
+			if requested[base+"admin"] != 1 {
+				t.Errorf("expected %sadmin to be scanned once, got %d", base, requested[base+"admin"])
+			}
+
+			out := readOutput(t, opts.OutputFile)
+			if !strings.Contains(out, "/secret-area") || !strings.Contains(out, "/from-sitemap") {
+				t.Errorf("expected seeded paths in output, got:\n%s", out)
+			}
+		})
+	}
+}
+
+func TestSeedRobotsRecursionWithPrefix(t *testing.T) {
+	for _, prefix := range []string{"", "v1/"} {
+		t.Run("prefix="+prefix, func(t *testing.T) {
+			var mu sync.Mutex
+			requested := make(map[string]bool)
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				requested[r.URL.Path] = true
+				mu.Unlock()
+				switch r.URL.Path {
+				case "/robots.txt":
+					fmt.Fprint(w, "User-agent: *\nDisallow: /hidden\n")
+				case "/" + prefix + "app":
+					fmt.Fprint(w, "directory index")
+				default:
+					w.WriteHeader(404)
+				}
+			}))
+			defer srv.Close()
+
+			opts := testOpts(t, srv.URL, writeWordlist(t, []string{"app"}))
+			opts.Prefix = prefix
+			opts.SeedRobots = true
+			opts.Recursive = true
+			opts.MaxDepth = 1
+			opts.ExcludeStatus = []int{404}
+
+			if _, err := Run(context.Background(), opts); err != nil {
+				t.Fatal(err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			// Recursion gets the seeded path, without the prefix, either way.
+			if !requested["/"+prefix+"app/hidden"] {
+				t.Errorf("seeded path not used for recursion; requested %v", requested)
+			}
+		})
+	}
+}
+
+func TestRecursionWordlist(t *testing.T) {
+	var mu sync.Mutex
+	requested := make(map[string]bool)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested[r.URL.Path] = true
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/admin":
+			http.Redirect(w, r, "/admin/", 301)
+		case "/admin/deep-only":
+			fmt.Fprint(w, "deep")
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer srv.Close()
+
+	opts := testOpts(t, srv.URL, writeWordlist(t, []string{"admin", "main-only"}))
+	opts.RecursionWordlist = writeWordlist(t, []string{"deep-only"})
+	opts.Recursive = true
+	opts.MaxDepth = 1
+	opts.ExcludeStatus = []int{404}
+
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !requested["/admin/deep-only"] {
+		t.Error("expected recursion wordlist entry /admin/deep-only to be requested")
+	}
+	for _, p := range []string{"/admin/main-only", "/admin/admin"} {
+		if requested[p] {
+			t.Errorf("main wordlist entry %s should not be used for recursion", p)
+		}
+	}
+	if out := readOutput(t, opts.OutputFile); !strings.Contains(out, "/admin/deep-only") {
+		t.Errorf("expected /admin/deep-only in output, got:\n%s", out)
+	}
+}
+
+func TestRecursionStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      []int
+		wantRecurse map[string]bool
+	}{
+		{"default", nil, map[string]bool{"ok": true, "moved": true, "forbidden": true}},
+		{"200 and 301", []int{200, 301}, map[string]bool{"ok": true, "moved": true, "forbidden": false}},
+		{"301 only", []int{301}, map[string]bool{"ok": false, "moved": true, "forbidden": false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			requested := make(map[string]bool)
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				requested[r.URL.Path] = true
+				mu.Unlock()
+				switch r.URL.Path {
+				case "/ok":
+					fmt.Fprint(w, "directory index")
+				case "/moved":
+					http.Redirect(w, r, "/moved/", 301)
+				case "/forbidden/":
+					w.WriteHeader(403)
+				default:
+					w.WriteHeader(404)
+				}
+			}))
+			defer srv.Close()
+
+			opts := testOpts(t, srv.URL, writeWordlist(t, []string{"ok", "moved", "forbidden/"}))
+			opts.RecursionWordlist = writeWordlist(t, []string{"child"})
+			opts.Recursive = true
+			opts.MaxDepth = 1
+			opts.RecursionStatus = tt.status
+			opts.ExcludeStatus = []int{404}
+
+			if _, err := Run(context.Background(), opts); err != nil {
+				t.Fatal(err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			for dir, want := range tt.wantRecurse {
+				if got := requested["/"+dir+"/child"]; got != want {
+					t.Errorf("recursed into /%s/ = %v, want %v", dir, got, want)
+				}
+			}
+		})
+	}
+}
+
+// recordingWriter captures results and footer stats for assertions.
+type recordingWriter struct {
+	results []scanner.ScanResult
+	stats   output.Stats
+}
+
+func (w *recordingWriter) WriteHeader() error { return nil }
+func (w *recordingWriter) WriteResult(r *scanner.ScanResult) error {
+	w.results = append(w.results, *r)
+	return nil
+}
+func (w *recordingWriter) WriteFooter(stats output.Stats) error { w.stats = stats; return nil }
+func (w *recordingWriter) Close() error                         { return nil }
+
+func TestRecurseOnly(t *testing.T) {
+	var mu sync.Mutex
+	requested := make(map[string]bool)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested[r.URL.Path] = true
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/admin", "/static", "/admin/users":
+			fmt.Fprint(w, "directory index")
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer srv.Close()
+
+	opts := testOpts(t, srv.URL, writeWordlist(t, []string{"admin", "static"}))
+	opts.RecursionWordlist = writeWordlist(t, []string{"users"})
+	opts.Recursive = true
+	opts.MaxDepth = 2
+	opts.RecurseOnly = []string{"Admin"}
+	opts.ExcludeStatus = []int{404}
+
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !requested["/admin/users"] {
+		t.Error("allowlisted /admin/ was not recursed into")
+	}
+	if !requested["/admin/users/users"] {
+		t.Error("subdirectory /admin/users/ of an allowlisted directory was not recursed into")
+	}
+	if requested["/static/users"] {
+		t.Error("/static/ is not allowlisted but was recursed into")
+	}
+}
+
+func TestStatsBreakdownIncludesFiltered(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/admin", "/login", "/api":
+			fmt.Fprint(w, "found")
+		case "/old":
+			http.Redirect(w, r, "/new", 302)
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer srv.Close()
+
+	opts := testOpts(t, srv.URL, writeWordlist(t, []string{"admin", "login", "api", "old", "x", "y"}))
+	opts.ExcludeStatus = []int{404}
+
+	rec := &recordingWriter{}
+	pipe := pipeline{
+		newWriter: func(*config.Options) (output.Writer, error) { return rec, nil },
+		detached:  true,
+	}
+	if err := runSingleTarget(context.Background(), opts, pipe); err != nil {
+		t.Fatal(err)
+	}
+
+	s := rec.stats
+	if s.StatusCounts[200] != 3 || s.StatusCounts[302] != 1 || s.StatusCounts[404] != 2 {
+		t.Errorf("StatusCounts = %v", s.StatusCounts)
+	}
+
+	total := 0
+	for _, n := range s.StatusCounts {
+		total += n
+	}
+	if total != s.TotalRequests-s.ErrorCount {
+		t.Errorf("status counts sum to %d, want %d", total, s.TotalRequests-s.ErrorCount)
+	}
+	if total-s.FilteredCount != len(rec.results) {
+		t.Errorf("%d counted - %d filtered != %d emitted", total, s.FilteredCount, len(rec.results))
+	}
+
+	buckets := 0
+	for _, n := range s.SizeBuckets {
+		buckets += n
+	}
+	if buckets != total {
+		t.Errorf("size buckets sum to %d, want %d", buckets, total)
+	}
+}
+
+func TestOpenAPIImport(t *testing.T) {
+	var mu sync.Mutex
+	requested := make(map[string]bool)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested[r.Method+" "+r.URL.Path] = true
+		mu.Unlock()
+		if r.URL.Path == "/users/1" {
+			fmt.Fprint(w, "user")
+			return
+		}
+		w.WriteHeader(404)
+	}))
+	defer srv.Close()
+
+	spec := filepath.Join(t.TempDir(), "openapi.json")
+	err := os.WriteFile(spec, []byte(`{
+	  "openapi": "3.0.0",
+	  "paths": {"/users/{id}": {"get": {}, "delete": {}}}
+	}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := testOpts(t, srv.URL, writeWordlist(t, []string{"admin"}))
+	opts.OpenAPISpec = spec
+	opts.ExcludeStatus = []int{404}
+
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, want := range []string{"GET /admin", "GET /users/1", "DELETE /users/1"} {
+		if !requested[want] {
+			t.Errorf("expected request %q", want)
+		}
+	}
+	if out := readOutput(t, opts.OutputFile); !strings.Contains(out, "[DELETE] /users/1") {
+		t.Errorf("expected DELETE result in output, got:\n%s", out)
+	}
+}
+
+func TestOpenAPIImportFetchedOnceWithAuth(t *testing.T) {
+	var fetches atomic.Int32
+	specSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fetches.Add(1)
+		fmt.Fprint(w, `{"openapi": "3.0.0", "paths": {"/users/{id}": {"get": {}}}}`)
+	}))
+	defer specSrv.Close()
+
+	var hits atomic.Int32
+	var urls []string
+	for i := 0; i < 2; i++ {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/users/1" {
+				hits.Add(1)
+			}
+			w.WriteHeader(404)
+		}))
+		defer srv.Close()
+		urls = append(urls, srv.URL)
+	}
+	urlsFile := filepath.Join(t.TempDir(), "urls.txt")
+	if err := os.WriteFile(urlsFile, []byte(strings.Join(urls, "\n")), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := testOpts(t, "", writeWordlist(t, []string{"admin"}))
+	opts.URLsFile = urlsFile
+	opts.OpenAPISpec = specSrv.URL + "/openapi.json"
+	opts.BearerToken = "token"
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("spec fetched %d times, want once", n)
+	}
+	if n := hits.Load(); n != 2 {
+		t.Errorf("/users/1 requested %d times, want once per target", n)
+	}
+}
+
+func TestHARRecording(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/admin" {
+			fmt.Fprint(w, "admin")
+			return
+		}
+		w.WriteHeader(404)
+	}))
+	defer srv.Close()
+
+	opts := testOpts(t, srv.URL, writeWordlist(t, []string{"admin", "login", "backup"}))
+	opts.HARFile = filepath.Join(t.TempDir(), "scan.har")
+
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+
+	var har struct {
+		Log struct {
+			Entries []struct {
+				Request  struct{ URL string }
+				Response struct{ Status int }
+			}
+		}
+	}
+	if err := json.Unmarshal([]byte(readOutput(t, opts.HARFile)), &har); err != nil {
+		t.Fatalf("HAR is not valid JSON: %v", err)
+	}
+	if len(har.Log.Entries) != 3 {
+		t.Fatalf("got %d HAR entries, want 3", len(har.Log.Entries))
+	}
+	statuses := make(map[string]int)
+	for _, e := range har.Log.Entries {
+		statuses[e.Request.URL] = e.Response.Status
+	}
+	if statuses[srv.URL+"/admin"] != 200 || statuses[srv.URL+"/login"] != 404 {
+		t.Errorf("unexpected HAR entries: %v", statuses)
+	}
+}
+
+func TestGlobalDuplicateFilter(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a", "/b", "/c":
+			http.Redirect(w, r, r.URL.Path+"/", 301)
+		case "/login", "/a/login", "/b/login", "/c/login":
+			fmt.Fprint(w, "<html>catch-all login page</html>")
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name   string
+		global bool
+		want   int
+	}{
+		{"per directory", false, 4},
+		{"global", true, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := testOpts(t, srv.URL, writeWordlist(t, []string{"a", "b", "c", "login"}))
+			opts.Recursive = true
+			opts.MaxDepth = 1
+			opts.ExcludeStatus = []int{404}
+			opts.DuplicateThreshold = 2
+			opts.GlobalDuplicate = tt.global
+
+			if _, err := Run(context.Background(), opts); err != nil {
+				t.Fatal(err)
+			}
+			out := readOutput(t, opts.OutputFile)
+			if got := strings.Count(out, "login\n"); got != tt.want {
+				t.Errorf("login pages shown = %d, want %d; output:\n%s", got, tt.want, out)
+			}
+		})
+	}
+}
+
+func TestStopOnFirst(t *testing.T) {
+	var requests atomic.Int32
+	var deepRequested atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch {
+		case r.URL.Path == "/admin":
+			http.Redirect(w, r, "/admin/", 301)
+		case strings.HasPrefix(r.URL.Path, "/admin/"):
+			deepRequested.Store(true)
+			w.WriteHeader(404)
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer srv.Close()
+
+	words := []string{"admin"}
+	for i := 0; i < 500; i++ {
+		words = append(words, fmt.Sprintf("miss%d", i))
+	}
+	opts := testOpts(t, srv.URL, writeWordlist(t, words))
+	opts.ExcludeStatus = []int{404}
+	opts.Recursive = true
+	opts.MaxDepth = 2
+	opts.StopOnFirst = true
+
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+	if n := requests.Load(); n > 100 {
+		t.Errorf("server saw %d requests, expected the scan to stop soon after the first hit", n)
+	}
+	if deepRequested.Load() {
+		t.Error("recursion should be skipped with --stop-on-first")
+	}
+	if out := readOutput(t, opts.OutputFile); !strings.Contains(out, "/admin") {
+		t.Errorf("expected /admin in output, got:\n%s", out)
+	}
+}
+
+func TestAbortOnErrors(t *testing.T) {
+	const healthy, threshold = 5, 3
+	var mu sync.Mutex
+	seen := make(map[string]bool)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen[r.URL.Path] = true
+		down := len(seen) > healthy
+		mu.Unlock()
+		if !down {
+			w.WriteHeader(404)
+			return
+		}
+		// The host "goes down": drop the connection without a response.
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+	}))
+	defer srv.Close()
+
+	var words []string
+	for i := 0; i < 100; i++ {
+		words = append(words, fmt.Sprintf("page%d", i))
+	}
+	opts := testOpts(t, srv.URL, writeWordlist(t, words))
+	opts.Threads = 1
+	opts.AbortOnErrors = threshold
+
+	outcome, err := Run(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if outcome != OutcomeAborted {
+		t.Errorf("outcome = %v, want OutcomeAborted", outcome)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	// One request may already be in flight when the breaker trips.
+	if n := len(seen); n < healthy+threshold || n > healthy+threshold+1 {
+		t.Errorf("server saw %d paths, want the scan to stop after %d successes and %d errors", n, healthy, threshold)
+	}
+}
+
+func TestMaxResults(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch {
+		case r.URL.Path == "/a" || r.URL.Path == "/b":
+			http.Redirect(w, r, r.URL.Path+"/", 301)
+		case strings.HasPrefix(r.URL.Path, "/a/x"), strings.HasPrefix(r.URL.Path, "/b/x"):
+			fmt.Fprintf(w, "page %s", r.URL.Path)
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer srv.Close()
+
+	words := []string{"a", "b"}
+	for i := 0; i < 50; i++ {
+		words = append(words, fmt.Sprintf("x%d", i))
+	}
+	opts := testOpts(t, srv.URL, writeWordlist(t, words))
+	opts.ExcludeStatus = []int{404}
+	opts.Recursive = true
+	opts.MaxDepth = 1
+	opts.MaxResults = 5
+
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+
+	// 2 results from the main pass, 3 more from recursion.
+	out := readOutput(t, opts.OutputFile)
+	results := regexp.MustCompile(`(?m)^\s*(200|301)\s`).FindAllString(out, -1)
+	if len(results) != 5 {
+		t.Errorf("got %d results, want 5; output:\n%s", len(results), out)
+	}
+	if n := requests.Load(); n >= int32(3*len(words)) {
+		t.Errorf("server saw %d requests, expected the scan to stop early", n)
+	}
+}
+
+func TestMaxResultsCountsOnlyUniqueResults(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/dup"):
+			fmt.Fprint(w, "same page")
+		case strings.HasPrefix(r.URL.Path, "/page"):
+			fmt.Fprint(w, strings.Repeat("x", len(r.URL.Path)*10))
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer srv.Close()
+
+	var words []string
+	for i := 0; i < 30; i++ {
+		words = append(words, fmt.Sprintf("dup%d", i))
+	}
+	words = append(words, "page1", "page22", "page333")
+	opts := testOpts(t, srv.URL, writeWordlist(t, words))
+	opts.ExcludeStatus = []int{404}
+	opts.UniqueBy = []string{"status", "size"}
+	opts.MaxResults = 3
+
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+
+	// Four distinct sizes exist, so three results must be written even
+	// though most candidates are duplicates.
+	out := readOutput(t, opts.OutputFile)
+	results := regexp.MustCompile(`(?m)^\s*200\s`).FindAllString(out, -1)
+	if len(results) != 3 {
+		t.Errorf("got %d results, want 3; output:\n%s", len(results), out)
+	}
+}
+
+func TestResumeContinuesRecursionFrontier(t *testing.T) {
+	var mu sync.Mutex
+	requested := make(map[string]bool)
+	var interrupt context.CancelFunc
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested[r.URL.Path] = true
+		cancel := interrupt
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/a", "/b":
+			http.Redirect(w, r, r.URL.Path+"/", 301)
+		case "/a/x", "/b/x":
+			if cancel != nil {
+				// First run: simulate Ctrl+C once recursion starts.
+				cancel()
+				w.WriteHeader(503)
+				return
+			}
+			fmt.Fprint(w, "found")
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer srv.Close()
+
+	opts := testOpts(t, srv.URL, writeWordlist(t, []string{"a", "b", "x"}))
+	opts.ExcludeStatus = []int{404}
+	opts.Recursive = true
+	opts.MaxDepth = 1
+	opts.Threads = 1
+	opts.ResumeFile = filepath.Join(t.TempDir(), "scan.state")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mu.Lock()
+	interrupt = cancel
+	mu.Unlock()
+	_, _ = Run(ctx, opts) // interrupted mid-recursion
+
+	if _, err := os.Stat(opts.ResumeFile); err != nil {
+		t.Fatalf("resume file should survive an interrupted scan: %v", err)
+	}
+
+	mu.Lock()
+	interrupt = nil
+	requested = make(map[string]bool)
+	mu.Unlock()
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, p := range []string{"/a", "/b", "/x"} {
+		if requested[p] {
+			t.Errorf("completed path %s was scanned again", p)
+		}
+	}
+	for _, p := range []string{"/a/x", "/b/x"} {
+		if !requested[p] {
+			t.Errorf("pending directory path %s was not scanned", p)
+		}
+	}
+	out := readOutput(t, opts.OutputFile)
+	if !strings.Contains(out, "/a/x") || !strings.Contains(out, "/b/x") {
+		t.Errorf("expected recursed results in output, got:\n%s", out)
+	}
+	if _, err := os.Stat(opts.ResumeFile); !os.IsNotExist(err) {
+		t.Errorf("resume file should be removed after completion, stat err = %v", err)
+	}
+}
+
+func TestExtractTitle(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/admin":
+			fmt.Fprint(w, "<html><head><title>\n  Admin Panel\n</title></head></html>")
+		case "/api":
+			fmt.Fprint(w, `{"ok":true}`)
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer srv.Close()
+
+	opts := testOpts(t, srv.URL, writeWordlist(t, []string{"admin", "api", "missing"}))
+	opts.ExcludeStatus = []int{404}
+	opts.ExtractTitle = true
+	opts.OutputFormat = "json"
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+
+	var entries []struct {
+		Path  string `json:"path"`
+		Title string `json:"title"`
+	}
+	if err := json.Unmarshal([]byte(readOutput(t, opts.OutputFile)), &entries); err != nil {
+		t.Fatal(err)
+	}
+	titles := make(map[string]string)
+	for _, e := range entries {
+		titles[e.Path] = e.Title
+	}
+	if len(titles) != 2 || titles["admin"] != "Admin Panel" || titles["api"] != "" {
+		t.Errorf("titles = %v, want admin titled \"Admin Panel\" and api untitled", titles)
+	}
+}
+
+func TestWordlistShard(t *testing.T) {
+	var mu sync.Mutex
+	var requested []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, strings.TrimPrefix(r.URL.Path, "/"))
+		mu.Unlock()
+		w.WriteHeader(404)
+	}))
+	defer srv.Close()
+
+	opts := testOpts(t, srv.URL, writeWordlist(t, []string{"a", "b", "c", "d", "e"}))
+	opts.Crawl = false
+	opts.WordlistOffset = 1
+	opts.WordlistLimit = 2
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(requested)
+	if strings.Join(requested, ",") != "b,c" {
+		t.Errorf("requested %v, want [b c]", requested)
+	}
+}
+
+func TestOrderedOutput(t *testing.T) {
+	words := make([]string, 40)
+	for i := range words {
+		words[i] = fmt.Sprintf("p%02d", i)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Earlier paths answer slower so completion order is reversed.
+		var i int
+		fmt.Sscanf(r.URL.Path, "/p%d", &i)
+		time.Sleep(time.Duration(len(words)-i) * time.Millisecond)
+		if i%3 == 0 {
+			w.WriteHeader(404)
+		}
+	}))
+	defer srv.Close()
+
+	opts := testOpts(t, srv.URL, writeWordlist(t, words))
+	opts.Threads = 10
+	opts.Crawl = false
+	opts.ExcludeStatus = []int{404}
+	opts.Ordered = true
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, line := range strings.Split(strings.TrimSpace(readOutput(t, opts.OutputFile)), "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 && strings.HasPrefix(fields[len(fields)-1], "/p") {
+			got = append(got, fields[len(fields)-1])
+		}
+	}
+	var want []string
+	for i, w := range words {
+		if i%3 != 0 {
+			want = append(want, "/"+w)
+		}
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("output order = %v, want %v", got, want)
+	}
+}
+
+func TestVerboseLogsFilteredResults(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/admin":
+			fmt.Fprint(w, "admin page")
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer srv.Close()
+
+	stderr, err := os.CreateTemp(t.TempDir(), "stderr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := os.Stderr
+	os.Stderr = stderr
+	defer func() { os.Stderr = orig }()
+
+	opts := testOpts(t, srv.URL, writeWordlist(t, []string{"admin", "missing"}))
+	opts.Crawl = false
+	opts.ExcludeStatus = []int{404}
+	opts.Verbose = true
+	_, err = Run(context.Background(), opts)
+	os.Stderr = orig
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logged := readOutput(t, stderr.Name())
+	for _, want := range []string{
+		"[v] GET /missing -> 404, 0 bytes, filtered by status",
+		"[v] GET /admin -> 200, 10 bytes, shown",
+	} {
+		if !strings.Contains(logged, want) {
+			t.Errorf("stderr missing %q:\n%s", want, logged)
+		}
+	}
+}
+
+func TestRunOutcome(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/admin" {
+			fmt.Fprint(w, "admin page")
+			return
+		}
+		w.WriteHeader(404)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name  string
+		words []string
+		want  Outcome
+		code  int
+	}{
+		{"results", []string{"admin", "missing"}, OutcomeResults, ExitOK},
+		{"no results", []string{"missing", "gone"}, OutcomeNoResults, ExitNoResults},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := testOpts(t, srv.URL, writeWordlist(t, tt.words))
+			opts.Crawl = false
+			opts.ExcludeStatus = []int{404}
+			outcome, err := Run(context.Background(), opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if outcome != tt.want {
+				t.Errorf("outcome = %d, want %d", outcome, tt.want)
+			}
+			if code := ExitCode(outcome, err); code != tt.code {
+				t.Errorf("exit code = %d, want %d", code, tt.code)
+			}
+		})
+	}
+}
+
+func TestRunOutcome_Aborted(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(404)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	opts := testOpts(t, srv.URL, writeWordlist(t, []string{"admin"}))
+	outcome, err := Run(ctx, opts)
+	if code := ExitCode(outcome, err); code != ExitAborted {
+		t.Errorf("exit code = %d (outcome %d, err %v), want %d", code, outcome, err, ExitAborted)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		outcome Outcome
+		err     error
+		want    int
+	}{
+		{OutcomeResults, nil, ExitOK},
+		{OutcomeNoResults, nil, ExitNoResults},
+		{OutcomeAborted, nil, ExitAborted},
+		{OutcomeNoResults, errors.New("bad wordlist"), ExitError},
+		{OutcomeResults, context.Canceled, ExitAborted},
+		{OutcomeAborted, context.Canceled, ExitAborted},
+	}
+	for _, tt := range tests {
+		if got := ExitCode(tt.outcome, tt.err); got != tt.want {
+			t.Errorf("ExitCode(%d, %v) = %d, want %d", tt.outcome, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestSaveBodies(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/admin":
+			fmt.Fprint(w, "admin page")
+		case "/api":
+			fmt.Fprint(w, `{"ok":true}`)
+		default:
+			w.WriteHeader(404)
+			fmt.Fprint(w, "not found")
+		}
+	}))
+	defer srv.Close()
+
+	dir := filepath.Join(t.TempDir(), "bodies")
+	opts := testOpts(t, srv.URL, writeWordlist(t, []string{"admin", "api", "missing"}))
+	opts.Crawl = false
+	opts.ExcludeStatus = []int{404}
+	opts.SaveBodies = dir
+	opts.OutputFormat = "json"
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+
+	var entries []struct {
+		Path     string `json:"path"`
+		BodyFile string `json:"body_file"`
+	}
+	if err := json.Unmarshal([]byte(readOutput(t, opts.OutputFile)), &entries); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"admin": "admin page", "api": `{"ok":true}`}
+	for _, e := range entries {
+		if got := readOutput(t, e.BodyFile); got != want[e.Path] {
+			t.Errorf("body of %s = %q, want %q", e.Path, got, want[e.Path])
+		}
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || len(files) != 2 {
+		t.Errorf("got %d results and %d body files, want 2 each (filtered 404 not saved)", len(entries), len(files))
+	}
+}
+
+func TestQueryFuzz(t *testing.T) {
+	var mu sync.Mutex
+	var seen []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen = append(seen, r.URL.Path+" "+r.URL.Query().Get("id"))
+		mu.Unlock()
+		if r.URL.Query().Get("id") != "7" {
+			w.WriteHeader(404)
+		}
+	}))
+	defer srv.Close()
+
+	opts := testOpts(t, srv.URL+"/item?id=FUZZ", writeWordlist(t, []string{"1", "7", "admin"}))
+	opts.Crawl = false
+	opts.ExcludeStatus = []int{404}
+	opts.FullURL = true
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(seen)
+	if want := []string{"/item 1", "/item 7", "/item admin"}; strings.Join(seen, ",") != strings.Join(want, ",") {
+		t.Errorf("server saw %v, want %v", seen, want)
+	}
+	out := readOutput(t, opts.OutputFile)
+	if !strings.Contains(out, srv.URL+"/item?id=7") || strings.Contains(out, "id=admin") {
+		t.Errorf("expected only the id=7 hit, got:\n%s", out)
+	}
+}
+
+func TestBypass403(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.RequestURI {
+		case "/admin", "/secret":
+			w.WriteHeader(http.StatusForbidden)
+		case "/admin..;/":
+			_, _ = w.Write([]byte("admin panel"))
+		default:
+			if strings.HasPrefix(r.RequestURI, "/admin") || strings.HasPrefix(r.RequestURI, "/secret") {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	opts := testOpts(t, srv.URL, writeWordlist(t, []string{"admin", "secret", "missing"}))
+	opts.Crawl = false
+	opts.ExcludeStatus = []int{404}
+	opts.Bypass403 = true
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+
+	out := readOutput(t, opts.OutputFile)
+	if !strings.Contains(out, "/admin..;/") {
+		t.Errorf("expected the bypassed variant in output, got:\n%s", out)
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if strings.Contains(line, "/secret") && !strings.HasSuffix(strings.TrimSpace(line), "/secret") {
+			t.Errorf("variant that stayed 403 was reported: %q", line)
+		}
+	}
+	if want := 3 + 2*len(bypassVariants("admin")); int(requests.Load()) != want {
+		t.Errorf("requests = %d, want %d", requests.Load(), want)
+	}
+}
+
+func TestProbeMethods(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api" {
+			w.WriteHeader(404)
+			return
+		}
+		if r.Method == http.MethodOptions {
+			w.Header().Set("Allow", "GET, POST")
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	opts := testOpts(t, srv.URL, writeWordlist(t, []string{"api", "missing"}))
+	opts.Crawl = false
+	opts.ExcludeStatus = []int{404}
+	opts.ProbeMethods = true
+	opts.OutputFormat = "json"
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+
+	var entries []struct {
+		Path  string   `json:"path"`
+		Allow []string `json:"allow"`
+	}
+	if err := json.Unmarshal([]byte(readOutput(t, opts.OutputFile)), &entries); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || strings.Join(entries[0].Allow, ",") != "GET,POST" {
+		t.Errorf("entries = %+v, want /api with allow [GET POST]", entries)
+	}
+}
+
+func TestProbeRequestsCounted(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api" {
+			w.WriteHeader(404)
+			return
+		}
+		fmt.Fprint(w, "api")
+	}))
+	defer srv.Close()
+
+	opts := testOpts(t, srv.URL, writeWordlist(t, []string{"api", "missing"}))
+	opts.Crawl = false
+	opts.ExcludeStatus = []int{404}
+	opts.ProbeMethods = true
+	opts.ProbeRange = true
+
+	rec := &recordingWriter{}
+	pipe := pipeline{
+		newWriter: func(*config.Options) (output.Writer, error) { return rec, nil },
+		detached:  true,
+	}
+	if err := runSingleTarget(context.Background(), opts, pipe); err != nil {
+		t.Fatal(err)
+	}
+	// Two wordlist requests plus an OPTIONS and a Range probe for /api.
+	if rec.stats.TotalRequests != 4 {
+		t.Errorf("TotalRequests = %d, want 4", rec.stats.TotalRequests)
+	}
+}
+
+func TestProbeRange(t *testing.T) {
+	file := strings.Repeat("backup data ", 100)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/backup.zip":
+			// ServeContent honors Range with 206 and a Content-Range.
+			http.ServeContent(w, r, "backup.zip", time.Time{}, strings.NewReader(file))
+		case "/app":
+			fmt.Fprint(w, "dynamic page")
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer srv.Close()
+
+	opts := testOpts(t, srv.URL, writeWordlist(t, []string{"backup.zip", "app", "missing"}))
+	opts.ExcludeStatus = []int{404}
+	opts.ProbeRange = true
+	opts.OutputFormat = "json"
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+
+	var entries []struct {
+		Path         string `json:"path"`
+		Status       int    `json:"status"`
+		AcceptsRange bool   `json:"accepts_range"`
+		FullSize     int64  `json:"full_size"`
+	}
+	if err := json.Unmarshal([]byte(readOutput(t, opts.OutputFile)), &entries); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("entries = %+v, want backup.zip and app", entries)
+	}
+	for _, e := range entries {
+		switch strings.TrimPrefix(e.Path, "/") {
+		case "backup.zip":
+			if !e.AcceptsRange || e.FullSize != int64(len(file)) || e.Status != 200 {
+				t.Errorf("backup.zip = %+v, want status 200 with a 206 range probe of size %d", e, len(file))
+			}
+		case "app":
+			if e.AcceptsRange || e.FullSize != 0 {
+				t.Errorf("app = %+v, want no range support", e)
+			}
+		}
+	}
+}
+
+func TestSkipFile(t *testing.T) {
+	var mu sync.Mutex
+	var requested []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.URL.Path)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	skip := filepath.Join(t.TempDir(), "gobuster.txt")
+	if err := os.WriteFile(skip, []byte("/admin (Status: 301) [Size: 178]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	opts := testOpts(t, srv.URL, writeWordlist(t, []string{"admin", "backup"}))
+	opts.Crawl = false
+	opts.SkipFile = skip
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(requested, ",") != "/backup" {
+		t.Errorf("requested %v, want only /backup", requested)
+	}
+}
+
+func TestCrawlScope(t *testing.T) {
+	var mu sync.Mutex
+	requested := make(map[string]bool)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested[r.URL.Path] = true
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/index":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(`<a href="/app/profile">p</a><a href="/app/logout">out</a><a href="/blog/post">b</a>`))
+		case "/app/profile":
+			_, _ = w.Write([]byte(`<a href="/app/settings">s</a>`))
+		case "/app/settings":
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer srv.Close()
+
+	opts := testOpts(t, srv.URL, writeWordlist(t, []string{"index"}))
+	opts.Crawl = true
+	opts.CrawlDepth = 2
+	opts.ExcludeStatus = []int{404}
+	opts.ScopeInclude = []string{"^/app/"}
+	opts.ScopeExclude = []string{"logout"}
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range []string{"/app/profile", "/app/settings"} {
+		if !requested[p] {
+			t.Errorf("in-scope %s was not scanned", p)
+		}
+	}
+	for _, p := range []string{"/app/logout", "/blog/post"} {
+		if requested[p] {
+			t.Errorf("out-of-scope %s was requested", p)
+		}
+	}
+}
+
+func TestSummaryFile(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/admin":
+			http.Redirect(w, r, "/admin/", http.StatusMovedPermanently)
+		case "/login.php":
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer srv.Close()
+
+	opts := testOpts(t, srv.URL, writeWordlist(t, []string{"admin", "login.php", "nope"}))
+	opts.Crawl = false
+	opts.ExcludeStatus = []int{404}
+	opts.SummaryFile = filepath.Join(t.TempDir(), "summary.json")
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(opts.SummaryFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Targets []output.TargetSummary `json:"targets"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, data)
+	}
+	if len(doc.Targets) != 1 {
+		t.Fatalf("got %d targets, want 1", len(doc.Targets))
+	}
+	got := doc.Targets[0]
+	if got.Target != srv.URL || got.TotalRequests != 3 || got.Found != 2 || got.Filtered != 1 {
+		t.Errorf("summary = %+v", got)
+	}
+	if got.StatusCodes[404] != 1 || got.StatusCodes[200] != 1 {
+		t.Errorf("status codes = %v", got.StatusCodes)
+	}
+	if len(got.Directories) != 1 || got.Directories[0] != "admin" {
+		t.Errorf("directories = %v, want [admin]", got.Directories)
+	}
+}
+
+func TestTimeLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		fmt.Fprint(w, "page")
+	}))
+	defer srv.Close()
+
+	words := make([]string, 200)
+	for i := range words {
+		words[i] = fmt.Sprintf("page%d.php", i)
+	}
+	opts := testOpts(t, srv.URL, writeWordlist(t, words))
+	opts.Crawl = false
+	opts.TimeLimit = 300 * time.Millisecond
+	opts.ResumeFile = filepath.Join(t.TempDir(), "scan.state")
+
+	start := time.Now()
+	outcome, err := Run(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 1500*time.Millisecond {
+		t.Errorf("scan took %s, want it to stop near the 300ms limit", elapsed)
+	}
+	if outcome != OutcomeAborted {
+		t.Errorf("outcome = %v, want OutcomeAborted", outcome)
+	}
+
+	lines := strings.Count(readOutput(t, opts.OutputFile), ".php")
+	if lines == 0 || lines >= len(words) {
+		t.Errorf("got %d results, want a partial set", lines)
+	}
+	if _, err := os.Stat(opts.ResumeFile); err != nil {
+		t.Errorf("resume file should be kept after --time-limit: %v", err)
+	}
+}
+
+func TestSkipTargetKey(t *testing.T) {
+	skip := make(chan struct{})
+	var firstHits atomic.Int64
+	first := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if firstHits.Add(1) == 1 {
+			go func() { skip <- struct{}{} }()
+		}
+		time.Sleep(20 * time.Millisecond)
+	}))
+	defer first.Close()
+	var secondHits atomic.Int64
+	second := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secondHits.Add(1)
+	}))
+	defer second.Close()
+
+	words := make([]string, 100)
+	for i := range words {
+		words[i] = fmt.Sprintf("page%d.php", i)
+	}
+	opts := testOpts(t, "", writeWordlist(t, words))
+	opts.Crawl = false
+	tracker := &outcomeTracker{}
+	pipe := pipeline{newWriter: newTargetWriter, outcome: tracker, detached: true, skip: skip}
+	targets := []target{{URL: first.URL}, {URL: second.URL}}
+
+	if err := runTargets(context.Background(), opts, targets, pipe); err != nil {
+		t.Fatal(err)
+	}
+	if n := firstHits.Load(); n >= int64(len(words)) {
+		t.Errorf("skipped target got all %d requests", n)
+	}
+	if n := secondHits.Load(); n != int64(len(words)) {
+		t.Errorf("next target got %d requests, want %d", n, len(words))
+	}
+	if tracker.outcome(context.Background()) == OutcomeAborted {
+		t.Error("skipping a target with 's' should not make the outcome aborted")
+	}
+}
+
+func TestSkipTargetKeyDuringCalibration(t *testing.T) {
+	skip := make(chan struct{})
+	var firstHits atomic.Int64
+	first := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The first request is a calibration probe; press 's' the way the
+		// keyboard reader does, dropping it if nobody listens.
+		if firstHits.Add(1) == 1 {
+			handleKey('s', nil, nil, skip, true)
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(404)
+	}))
+	defer first.Close()
+	second := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(404)
+	}))
+	defer second.Close()
+
+	words := make([]string, 100)
+	for i := range words {
+		words[i] = fmt.Sprintf("page%d.php", i)
+	}
+	opts := testOpts(t, "", writeWordlist(t, words))
+	opts.Crawl = false
+	opts.SmartFilter = true
+	pipe := pipeline{newWriter: newTargetWriter, outcome: &outcomeTracker{}, detached: true, skip: skip}
+	targets := []target{{URL: first.URL}, {URL: second.URL}}
+
+	if err := runTargets(context.Background(), opts, targets, pipe); err != nil {
+		t.Fatal(err)
+	}
+	if n := firstHits.Load(); n >= int64(len(words)) {
+		t.Errorf("target skipped during calibration still got %d requests", n)
+	}
+}
+
+func TestReplayProxy(t *testing.T) {
+	var direct atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		direct.Add(1)
+		if r.URL.Path != "/admin" {
+			w.WriteHeader(404)
+		}
+	}))
+	defer srv.Close()
+
+	var mu sync.Mutex
+	var replayed []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		replayed = append(replayed, r.URL.String())
+		mu.Unlock()
+	}))
+	defer proxy.Close()
+
+	opts := testOpts(t, srv.URL, writeWordlist(t, []string{"admin", "backup", "secret"}))
+	opts.Crawl = false
+	opts.ExcludeStatus = []int{404}
+	opts.ReplayProxy = proxy.URL
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := srv.URL + "/admin"; strings.Join(replayed, ",") != want {
+		t.Errorf("replayed %v, want only %s", replayed, want)
+	}
+	if n := direct.Load(); n != 3 {
+		t.Errorf("target got %d direct requests, want 3", n)
+	}
+}
+
+func TestFetchFaviconHash(t *testing.T) {
+	icon := []byte{0, 0, 1, 0}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/favicon.ico" {
+			w.WriteHeader(404)
+			return
+		}
+		_, _ = w.Write(icon)
+	}))
+	defer srv.Close()
+
+	// A base path is ignored: the icon is always fetched from the host root.
+	opts := testOpts(t, srv.URL+"/app/", writeWordlist(t, []string{"x"}))
+	req, err := scanner.NewRequester(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := faviconURL(opts.URL); got != srv.URL+"/favicon.ico" {
+		t.Errorf("faviconURL = %q, want %q", got, srv.URL+"/favicon.ico")
+	}
+	hash, ok := fetchFaviconHash(context.Background(), req, faviconURL(opts.URL))
+	if !ok || hash != -216455174 {
+		t.Errorf("fetchFaviconHash = %d, %v; want -216455174, true", hash, ok)
+	}
+
+	if _, ok := fetchFaviconHash(context.Background(), req, srv.URL+"/missing/favicon.ico"); ok {
+		t.Error("a 404 favicon should not be hashed")
+	}
+}
+
+func TestThrottlerSharedAcrossTargets(t *testing.T) {
+	var mu sync.Mutex
+	var limitedAt time.Time
+	var sinceLimited []time.Duration
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if strings.HasPrefix(r.URL.Path, "/a/") {
+			limitedAt = time.Now()
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		sinceLimited = append(sinceLimited, time.Since(limitedAt))
+	}))
+	defer srv.Close()
+
+	list := filepath.Join(t.TempDir(), "targets.txt")
+	if err := os.WriteFile(list, []byte(srv.URL+"/a/\n"+srv.URL+"/b/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	opts := testOpts(t, "", writeWordlist(t, []string{"page.php"}))
+	opts.URLsFile = list
+	opts.Crawl = false
+	opts.Threads = 1
+	opts.AdaptiveThrottle = true
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sinceLimited) != 1 {
+		t.Fatalf("second target got %d requests, want 1", len(sinceLimited))
+	}
+	// The 429 on the first target backed the host off to 500ms; the
+	// second target on the same host must start from that delay.
+	if d := sinceLimited[0]; d < 400*time.Millisecond {
+		t.Errorf("second target's request came %s after the 429, want the shared back-off", d)
+	}
+}
+
+func TestAggregateProgressAcrossTargets(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	opts := testOpts(t, "", writeWordlist(t, []string{"admin", "login.php", "backup"}))
+	opts.Crawl = false
+	agg := output.NewAggregate(2)
+	pipe := pipeline{newWriter: newTargetWriter, detached: true, aggregate: agg}
+	targets := []target{{URL: srv.URL + "/a/"}, {URL: srv.URL + "/b/"}}
+
+	if err := runTargets(context.Background(), opts, targets, pipe); err != nil {
+		t.Fatal(err)
+	}
+	if got := agg.TargetsDone(); got != 2 {
+		t.Errorf("TargetsDone() = %d, want 2", got)
+	}
+	if got := agg.Requests(); got != 6 {
+		t.Errorf("Requests() = %d, want 6 (3 words on 2 targets)", got)
+	}
+}
+
+func TestSlowThreshold(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/report":
+			time.Sleep(300 * time.Millisecond)
+			fmt.Fprint(w, "heavy report")
+		case "/index":
+			fmt.Fprint(w, "index")
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer srv.Close()
+
+	opts := testOpts(t, srv.URL, writeWordlist(t, []string{"report", "index"}))
+	opts.ExcludeStatus = []int{404}
+	opts.OutputFormat = "json"
+	opts.SlowThreshold = 150 * time.Millisecond
+
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+
+	var entries []struct {
+		Path string `json:"path"`
+		Slow bool   `json:"slow"`
+	}
+	if err := json.Unmarshal([]byte(readOutput(t, opts.OutputFile)), &entries); err != nil {
+		t.Fatal(err)
+	}
+	slow := make(map[string]bool)
+	for _, e := range entries {
+		slow[strings.TrimPrefix(e.Path, "/")] = e.Slow
+	}
+	if len(slow) != 2 {
+		t.Fatalf("got %d results, want 2: %v", len(slow), slow)
+	}
+	if !slow["report"] {
+		t.Error("/report should be flagged slow")
+	}
+	if slow["index"] {
+		t.Error("/index should not be flagged slow")
+	}
+}
+
+func TestOutputDir(t *testing.T) {
+	var urls []string
+	for _, page := range []string{"admin", "login"} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/"+page {
+				w.WriteHeader(404)
+				return
+			}
+			fmt.Fprint(w, page)
+		}))
+		defer srv.Close()
+		urls = append(urls, srv.URL)
+	}
+	urlsFile := filepath.Join(t.TempDir(), "urls.txt")
+	if err := os.WriteFile(urlsFile, []byte(strings.Join(urls, "\n")), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := testOpts(t, "", writeWordlist(t, []string{"admin", "login"}))
+	opts.URLsFile = urlsFile
+	opts.OutputFile = ""
+	opts.OutputDir = filepath.Join(t.TempDir(), "results")
+	opts.OutputFormat = "json"
+	opts.ExcludeStatus = []int{404}
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(opts.OutputDir, "*"))
+	if err != nil || len(files) != 2 {
+		t.Fatalf("output dir holds %v (err %v), want two files", files, err)
+	}
+	for i, page := range []string{"admin", "login"} {
+		u, _ := url.Parse(urls[i])
+		name := filepath.Join(opts.OutputDir, "127.0.0.1_"+u.Port()+".json")
+		var entries []struct {
+			Path string `json:"path"`
+		}
+		if err := json.Unmarshal([]byte(readOutput(t, name)), &entries); err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 || strings.TrimPrefix(entries[0].Path, "/") != page {
+			t.Errorf("%s holds %+v, want only %s", name, entries, page)
+		}
+	}
+}
+
+func TestOutputPath(t *testing.T) {
+	tests := []struct {
+		url, format, want string
+	}{
+		{"https://example.com", "json", "example.com_443.json"},
+		{"http://example.com", "text", "example.com_80.txt"},
+		{"http://10.0.0.5:8080/app/", "csv", "10.0.0.5_8080_app.csv"},
+		{"https://[::1]:8443", "md", "__1_8443.md"},
+	}
+	for _, tt := range tests {
+		opts := &config.Options{URL: tt.url, OutputFormat: tt.format, OutputDir: "out"}
+		if got, want := outputPath(opts), filepath.Join("out", tt.want); got != want {
+			t.Errorf("outputPath(%s) = %q, want %q", tt.url, got, want)
+		}
+	}
+	if got := outputPath(&config.Options{URL: "https://example.com", OutputFile: "res.txt"}); got != "res.txt" {
+		t.Errorf("outputPath without --output-dir = %q, want the --output file", got)
+	}
+}
+
+func TestAssignOutputFiles(t *testing.T) {
+	opts := &config.Options{OutputDir: "out"}
+	targets := []target{
+		{URL: "http://h/a-b"},
+		{URL: "http://h/a_b"},
+		{URL: "http://h/a_b?x=1"},
+		{URL: "http://h/a_b_2"},
+		{URL: "http://H/A_B"},
+	}
+	assignOutputFiles(opts, targets)
+	want := []string{"h_80_a-b.txt", "h_80_a_b.txt", "h_80_a_b_2.txt", "h_80_a_b_2_2.txt", "H_80_A_B_3.txt"}
+	for i, tt := range targets {
+		if got := tt.options(opts); got.OutputFile != filepath.Join("out", want[i]) || outputPath(got) != got.OutputFile {
+			t.Errorf("target %s: output file %q, want %q", tt.URL, got.OutputFile, filepath.Join("out", want[i]))
+		}
+	}
+}
+
+func TestShareCalibration(t *testing.T) {
+	const probes = 5
+	var counts [2]atomic.Int32
+	var urls []string
+	for i := range counts {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/admin" {
+				fmt.Fprint(w, "admin panel")
+				return
+			}
+			counts[i].Add(1)
+			fmt.Fprint(w, "Sorry, we could not find that page. Try the search box above.")
+		}))
+		defer srv.Close()
+		urls = append(urls, srv.URL)
+	}
+	urlsFile := filepath.Join(t.TempDir(), "urls.txt")
+	if err := os.WriteFile(urlsFile, []byte(strings.Join(urls, "\n")), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := testOpts(t, "", writeWordlist(t, []string{"admin", "missing"}))
+	opts.URLsFile = urlsFile
+	opts.SmartFilter = true
+	opts.SmartFilterProbes = probes
+	opts.ShareCalibration = true
+	opts.OutputFormat = "json"
+	opts.OutputFile = ""
+	opts.OutputDir = t.TempDir()
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+
+	// Each server also gets the "missing" wordlist entry.
+	if got := counts[0].Load(); got != probes+1 {
+		t.Errorf("first target got %d unknown-path requests, want %d calibration probes + 1", got, probes)
+	}
+	if got := counts[1].Load(); got != 2 {
+		t.Errorf("second target got %d unknown-path requests, want 1 check probe + 1", got)
+	}
+
+	// The reused filter still hides the second target's soft-404s.
+	second := *opts
+	second.URL = urls[1]
+	var entries []struct {
+		Path string `json:"path"`
+	}
+	if err := json.Unmarshal([]byte(readOutput(t, outputPath(&second))), &entries); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || strings.TrimPrefix(entries[0].Path, "/") != "admin" {
+		t.Errorf("second target reported %+v, want only admin", entries)
+	}
+}
@@ -0,0 +1,68 @@
+package runner
+
+import (
+	"context"
+
+	"github.com/maxvaer/dirfuzz/internal/config"
+	"github.com/maxvaer/dirfuzz/internal/output"
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+// Scan runs the scan pipeline for every target in opts and streams the
+// results that pass all filters on the returned channel. It is the entry
+// point for embedding dirfuzz in other Go programs: nothing is written to
+// stdout or stderr, and the terminal and signal handlers are left alone.
+//
+// The channel is closed once all targets are done or ctx is cancelled.
+// A target that fails (e.g. unreachable host) is skipped and the scan
+// moves on to the next one. opts is not modified; OutputFile,
+// OutputFormat, and SortBy are ignored.
+func Scan(ctx context.Context, opts *config.Options) (<-chan scanner.ScanResult, error) {
+	scanOpts := *opts
+	scanOpts.Silent = true
+	scanOpts.Verbose = false
+
+	targets, err := resolveTargets(&scanOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(chan scanner.ScanResult)
+	pipe := pipeline{
+		newWriter: func(*config.Options) (output.Writer, error) {
+			return &chanWriter{ctx: ctx, ch: results}, nil
+		},
+		detached: true,
+	}
+
+	go func() {
+		defer close(results)
+		for _, target := range targets {
+			if err := runSingleTarget(ctx, target.options(&scanOpts), pipe); err != nil && ctx.Err() != nil {
+				return
+			}
+		}
+	}()
+	return results, nil
+}
+
+// chanWriter is an output.Writer that forwards results to a channel.
+type chanWriter struct {
+	ctx context.Context
+	ch  chan<- scanner.ScanResult
+}
+
+func (w *chanWriter) WriteHeader() error { return nil }
+
+func (w *chanWriter) WriteResult(result *scanner.ScanResult) error {
+	select {
+	case w.ch <- *result:
+		return nil
+	case <-w.ctx.Done():
+		return w.ctx.Err()
+	}
+}
+
+func (w *chanWriter) WriteFooter(output.Stats) error { return nil }
+
+func (w *chanWriter) Close() error { return nil }
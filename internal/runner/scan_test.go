@@ -0,0 +1,92 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestScan(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/admin", "/login":
+			fmt.Fprint(w, "page "+r.URL.Path)
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer srv.Close()
+
+	wordlist := writeWordlist(t, []string{"admin", "login", "missing", "nothing"})
+	opts := testOpts(t, srv.URL, wordlist)
+	opts.ExcludeStatus = []int{404}
+
+	results, err := Scan(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var found []string
+	for r := range results {
+		if r.StatusCode != 200 {
+			t.Errorf("filtered result %s (%d) was streamed", r.Path, r.StatusCode)
+		}
+		found = append(found, r.Path)
+	}
+	sort.Strings(found)
+	if len(found) != 2 || found[0] != "admin" || found[1] != "login" {
+		t.Errorf("got results %v, want [admin login]", found)
+	}
+
+	// Scan must not write the CLI output file.
+	if _, err := os.Stat(opts.OutputFile); !os.IsNotExist(err) {
+		t.Errorf("expected no output file, stat err = %v", err)
+	}
+}
+
+func TestScanCancel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+
+	words := make([]string, 500)
+	for i := range words {
+		words[i] = fmt.Sprintf("path%d", i)
+	}
+	opts := testOpts(t, srv.URL, writeWordlist(t, words))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	results, err := Scan(ctx, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Read one result, then stop consuming and cancel.
+	<-results
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		for range results {
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("results channel not closed after cancel")
+	}
+}
+
+func TestScanNoTargets(t *testing.T) {
+	opts := testOpts(t, "", writeWordlist(t, []string{"a"}))
+	if _, err := Scan(context.Background(), opts); err == nil {
+		t.Fatal("expected error without targets")
+	}
+}
@@ -10,10 +10,10 @@ import (
 )
 
 // startStdinToggle starts a goroutine that reads single keypresses from
-// stdin and toggles the pauser on Enter or Space. It returns a cleanup
-// function that restores the terminal state. If stdin is not a terminal,
-// it returns a nil pauser and a no-op cleanup.
-func startStdinToggle(quiet bool) (pauser *scanner.Pauser, cleanup func()) {
+// stdin and hands them to handleKey. It returns a cleanup function that
+// restores the terminal state. If stdin is not a terminal, it returns a nil
+// pauser and a no-op cleanup.
+func startStdinToggle(quiet bool, scaler *scanner.Scaler, skip chan<- struct{}) (pauser *scanner.Pauser, cleanup func()) {
 	fd := int(os.Stdin.Fd())
 
 	if !term.IsTerminal(fd) {
@@ -62,19 +62,48 @@ func startStdinToggle(quiet bool) (pauser *scanner.Pauser, cleanup func()) {
 				return
 			}
 
-			// Enter (CR or LF) or Space: toggle pause.
-			if key == '\r' || key == '\n' || key == ' ' {
-				nowPaused := pauser.Toggle()
-				if !quiet {
-					if nowPaused {
-						fmt.Fprintf(os.Stderr, "\r\033[K[*] Scan PAUSED — press Enter or Space to resume\n")
-					} else {
-						fmt.Fprintf(os.Stderr, "\r\033[K[*] Scan RESUMED\n")
-					}
-				}
-			}
+			handleKey(key, pauser, scaler, skip, quiet)
 		}
 	}()
 
 	return pauser, cleanup
 }
+
+// threadStep is how many workers one + or - keypress adds or removes.
+const threadStep = 5
+
+// handleKey acts on one keypress: Enter (CR or LF) or Space toggles the
+// pauser, + and - change the worker count by threadStep, and 's' asks the
+// current target's scan to stop so the run moves on to the next one. The
+// skip request is dropped if skip is nil (single target) or no scan is
+// listening.
+func handleKey(key byte, pauser *scanner.Pauser, scaler *scanner.Scaler, skip chan<- struct{}, quiet bool) {
+	switch key {
+	case '\r', '\n', ' ':
+		nowPaused := pauser.Toggle()
+		if !quiet {
+			if nowPaused {
+				fmt.Fprintf(os.Stderr, "\r\033[K[*] Scan PAUSED — press Enter or Space to resume\n")
+			} else {
+				fmt.Fprintf(os.Stderr, "\r\033[K[*] Scan RESUMED\n")
+			}
+		}
+	case '+', '=', '-':
+		delta := threadStep
+		if key == '-' {
+			delta = -threadStep
+		}
+		threads := scaler.Add(delta)
+		if !quiet {
+			fmt.Fprintf(os.Stderr, "\r\033[K[*] Threads: %d\n", threads)
+		}
+	case 's', 'S':
+		if skip == nil {
+			return
+		}
+		select {
+		case skip <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package runner
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/maxvaer/dirfuzz/internal/config"
+)
+
+// target is one URL to scan, with any headers specific to it.
+type target struct {
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// outputFile is the target's --output-dir file, set by
+	// assignOutputFiles so that no two targets share one.
+	outputFile string
+}
+
+// options returns a copy of opts for scanning t: the URL is replaced and
+// t's headers are layered over the global -H headers, winning on
+// case-insensitive name clashes.
+func (t target) options(opts *config.Options) *config.Options {
+	targetOpts := *opts
+	targetOpts.URL = t.URL
+	if t.outputFile != "" {
+		targetOpts.OutputFile = t.outputFile
+	}
+	if len(t.Headers) > 0 {
+		headers := make(map[string]string, len(opts.Headers)+len(t.Headers))
+		for k, v := range opts.Headers {
+			headers[k] = v
+		}
+		for k, v := range t.Headers {
+			for existing := range headers {
+				if http.CanonicalHeaderKey(existing) == http.CanonicalHeaderKey(k) {
+					delete(headers, existing)
+				}
+			}
+			headers[k] = v
+		}
+		targetOpts.Headers = headers
+	}
+	return &targetOpts
+}
+
+// isJSONLTargets reports whether the -l file holds JSON Lines targets,
+// either forced with --targets-json or by a .jsonl/.ndjson extension.
+func isJSONLTargets(opts *config.Options) bool {
+	if opts.TargetsJSON {
+		return true
+	}
+	switch strings.ToLower(filepath.Ext(opts.URLsFile)) {
+	case ".jsonl", ".ndjson":
+		return true
+	}
+	return false
+}
+
+// loadTargetsFile reads -l: one URL per line, or with JSON Lines one
+// {"url": ..., "headers": {...}} object per line. Blank lines and lines
+// starting with # are skipped in both formats.
+func loadTargetsFile(opts *config.Options) ([]target, error) {
+	f, err := os.Open(opts.URLsFile)
+	if err != nil {
+		return nil, fmt.Errorf("opening URLs file: %w", err)
+	}
+	defer f.Close()
+
+	jsonl := isJSONLTargets(opts)
+	var targets []target
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNo := 0
+	for sc.Scan() {
+		lineNo++
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		t := target{URL: line}
+		if jsonl {
+			t = target{}
+			if err := json.Unmarshal([]byte(line), &t); err != nil {
+				return nil, fmt.Errorf("%s line %d: %w", opts.URLsFile, lineNo, err)
+			}
+			if t.URL == "" {
+				return nil, fmt.Errorf("%s line %d: missing \"url\"", opts.URLsFile, lineNo)
+			}
+		}
+		t.URL = normalizeTargetURL(t.URL)
+		targets = append(targets, t)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("reading URLs file: %w", err)
+	}
+	return targets, nil
+}
+
+// normalizeTargetURL defaults scheme-less targets to http.
+func normalizeTargetURL(raw string) string {
+	if !strings.HasPrefix(raw, "http://") && !strings.HasPrefix(raw, "https://") {
+		return "http://" + raw
+	}
+	return raw
+}
@@ -0,0 +1,118 @@
+package runner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/maxvaer/dirfuzz/internal/config"
+)
+
+func writeTargetsFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadTargetsFile_JSONL(t *testing.T) {
+	path := writeTargetsFile(t, "targets.jsonl", `{"url": "https://a.example", "headers": {"Cookie": "sid=a"}}
+
+# comment
+{"url": "b.example"}
+`)
+	targets, err := loadTargetsFile(&config.Options{URLsFile: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(targets) != 2 {
+		t.Fatalf("got %d targets, want 2", len(targets))
+	}
+	if targets[0].URL != "https://a.example" || targets[0].Headers["Cookie"] != "sid=a" {
+		t.Errorf("first target = %+v", targets[0])
+	}
+	if targets[1].URL != "http://b.example" || len(targets[1].Headers) != 0 {
+		t.Errorf("second target = %+v", targets[1])
+	}
+}
+
+func TestLoadTargetsFile_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"invalid json", "{\"url\": \"https://a.example\"}\n{not json}\n"},
+		{"missing url", `{"headers": {"Cookie": "x"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTargetsFile(t, "targets.txt", tt.content)
+			if _, err := loadTargetsFile(&config.Options{URLsFile: path, TargetsJSON: true}); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestTargetOptions_MergesHeaders(t *testing.T) {
+	opts := &config.Options{
+		URL:     "https://global.example",
+		Headers: map[string]string{"authorization": "Bearer global", "X-Team": "red"},
+	}
+	tgt := target{URL: "https://a.example", Headers: map[string]string{"Authorization": "Bearer a"}}
+
+	got := tgt.options(opts)
+	if got.URL != "https://a.example" {
+		t.Errorf("URL = %q", got.URL)
+	}
+	want := map[string]string{"Authorization": "Bearer a", "X-Team": "red"}
+	if len(got.Headers) != len(want) {
+		t.Errorf("Headers = %v, want %v", got.Headers, want)
+	}
+	for k, v := range want {
+		if got.Headers[k] != v {
+			t.Errorf("Headers[%q] = %q, want %q", k, got.Headers[k], v)
+		}
+	}
+	if opts.Headers["authorization"] != "Bearer global" || opts.URL != "https://global.example" {
+		t.Error("options must not modify the shared opts")
+	}
+}
+
+func TestRunJSONLTargetsApplyHeaders(t *testing.T) {
+	var mu sync.Mutex
+	seen := make(map[string]string) // target host -> Cookie
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen[r.Host] = r.Header.Get("Cookie")
+		mu.Unlock()
+		w.WriteHeader(404)
+	})
+	srvA := httptest.NewServer(handler)
+	defer srvA.Close()
+	srvB := httptest.NewServer(handler)
+	defer srvB.Close()
+
+	path := writeTargetsFile(t, "targets.jsonl",
+		`{"url": "`+srvA.URL+`", "headers": {"Cookie": "sid=a"}}`+"\n"+
+			`{"url": "`+srvB.URL+`", "headers": {"Cookie": "sid=b"}}`+"\n")
+
+	opts := testOpts(t, "", writeWordlist(t, []string{"admin"}))
+	opts.URLsFile = path
+	opts.Crawl = false
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+
+	hostA := srvA.Listener.Addr().String()
+	hostB := srvB.Listener.Addr().String()
+	if seen[hostA] != "sid=a" || seen[hostB] != "sid=b" {
+		t.Errorf("cookies by host = %v, want %s=sid=a and %s=sid=b", seen, hostA, hostB)
+	}
+}
@@ -0,0 +1,60 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/maxvaer/dirfuzz/internal/config"
+	"github.com/maxvaer/dirfuzz/internal/output"
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+// verboseLogger prints one line per response to stderr for --verbose,
+// including filtered responses and the filter that caught them. A nil
+// logger discards everything.
+type verboseLogger struct {
+	w io.Writer
+}
+
+// newVerboseLogger returns a logger if --verbose is set, nil otherwise.
+func newVerboseLogger(opts *config.Options) *verboseLogger {
+	if !opts.Verbose {
+		return nil
+	}
+	return &verboseLogger{w: os.Stderr}
+}
+
+// log writes result's line, clearing the progress bar around it.
+func (l *verboseLogger) log(progress *output.Progress, result *scanner.ScanResult) {
+	if l == nil {
+		return
+	}
+	method := result.Method
+	if method == "" {
+		method = "GET"
+	}
+	target := "/" + strings.TrimLeft(result.Path, "/")
+	if result.Host != "" {
+		target = "[" + result.Host + "] " + target
+	}
+
+	var outcome string
+	switch {
+	case result.Error != nil:
+		outcome = fmt.Sprintf("error (%s): %v", result.ErrorCategory, result.Error)
+	case result.Filtered:
+		outcome = fmt.Sprintf("%d, %d bytes, filtered by %s", result.StatusCode, result.ContentLength, result.FilterReason)
+	default:
+		outcome = fmt.Sprintf("%d, %d bytes, shown", result.StatusCode, result.ContentLength)
+	}
+
+	if result.Proto != "" {
+		outcome += " (" + result.Proto + ")"
+	}
+
+	progress.ClearLine()
+	fmt.Fprintf(l.w, "[v] %s %s -> %s\n", method, target, outcome)
+	progress.Redraw()
+}
@@ -0,0 +1,54 @@
+package scanner
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"strings"
+)
+
+// acceptEncoding is advertised on every request. Only encodings that
+// decodeBody understands are listed.
+const acceptEncoding = "gzip, deflate"
+
+// decodeBody decompresses body according to the Content-Encoding header so
+// that size, hash, and word/line metrics are computed over the real
+// content. Unknown encodings, or bodies that fail to decode, are returned
+// unchanged. A stream cut short (e.g. by --max-body-size) keeps whatever
+// decoded before the cut. With limit > 0, at most limit+1 decoded bytes are
+// produced so the caller can detect and trim an oversized body.
+func decodeBody(contentEncoding string, body []byte, limit int64) []byte {
+	if len(body) == 0 {
+		return body
+	}
+	var r io.Reader
+	switch strings.ToLower(strings.TrimSpace(contentEncoding)) {
+	case "gzip", "x-gzip":
+		zr, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return body
+		}
+		r = zr
+	case "deflate":
+		// "deflate" is zlib-wrapped per RFC 9110, but some servers send
+		// raw DEFLATE data — try zlib first and fall back.
+		if zr, err := zlib.NewReader(bytes.NewReader(body)); err == nil {
+			r = zr
+		} else {
+			r = flate.NewReader(bytes.NewReader(body))
+		}
+	default:
+		return body
+	}
+	if limit > 0 {
+		r = io.LimitReader(r, limit+1)
+	}
+	decoded, err := io.ReadAll(r)
+	if err != nil && (!errors.Is(err, io.ErrUnexpectedEOF) || len(decoded) == 0) {
+		return body
+	}
+	return decoded
+}
@@ -0,0 +1,126 @@
+package scanner
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/maxvaer/dirfuzz/internal/config"
+)
+
+const plainBody = "<html>\n<body>\nthe quick brown fox jumps over the lazy dog\n</body>\n</html>"
+
+func compress(t *testing.T, encoding string, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch encoding {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "deflate":
+		w = zlib.NewWriter(&buf)
+	case "raw-deflate":
+		w, _ = flate.NewWriter(&buf, flate.DefaultCompression)
+	}
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestRequester_DecodesCompressedBodies(t *testing.T) {
+	plain := &Response{}
+	{
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.WriteString(w, plainBody)
+		}))
+		defer srv.Close()
+		req := newTestRequester(t, &config.Options{URL: srv.URL})
+		var err error
+		if plain, err = req.Do(context.Background(), "GET", "/", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, enc := range []string{"gzip", "deflate", "raw-deflate"} {
+		t.Run(enc, func(t *testing.T) {
+			var gotAccept string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotAccept = r.Header.Get("Accept-Encoding")
+				header := enc
+				if enc == "raw-deflate" {
+					header = "deflate"
+				}
+				w.Header().Set("Content-Encoding", header)
+				_, _ = w.Write(compress(t, enc, []byte(plainBody)))
+			}))
+			defer srv.Close()
+
+			req := newTestRequester(t, &config.Options{URL: srv.URL})
+			resp, err := req.Do(context.Background(), "GET", "/", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.Contains(gotAccept, "gzip") || !strings.Contains(gotAccept, "deflate") {
+				t.Errorf("Accept-Encoding = %q, want gzip and deflate", gotAccept)
+			}
+			if string(resp.Body) != plainBody {
+				t.Errorf("body not decoded: %q", resp.Body)
+			}
+			if resp.ContentLength != plain.ContentLength {
+				t.Errorf("ContentLength = %d, want decoded length %d", resp.ContentLength, plain.ContentLength)
+			}
+			if resp.WordCount != plain.WordCount || resp.LineCount != plain.LineCount {
+				t.Errorf("words/lines = %d/%d, want %d/%d",
+					resp.WordCount, resp.LineCount, plain.WordCount, plain.LineCount)
+			}
+			if resp.BodyHash != plain.BodyHash {
+				t.Error("expected body hash to match plaintext hash")
+			}
+		})
+	}
+}
+
+func TestDecodeBody_InvalidDataUnchanged(t *testing.T) {
+	raw := []byte("not actually gzip")
+	if got := decodeBody("gzip", raw, 0); !bytes.Equal(got, raw) {
+		t.Errorf("expected undecodable body to be returned unchanged, got %q", got)
+	}
+	if got := decodeBody("br", raw, 0); !bytes.Equal(got, raw) {
+		t.Errorf("expected unknown encoding to be returned unchanged, got %q", got)
+	}
+}
+
+func TestRequester_MaxBodySizeCompressed(t *testing.T) {
+	// Highly compressible: the encoded body fits under the cap, the
+	// decoded one does not.
+	body := compress(t, "gzip", bytes.Repeat([]byte("a"), 1<<20))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	req := newTestRequester(t, &config.Options{URL: srv.URL, MaxBodySize: 4096})
+	resp, err := req.Do(context.Background(), "GET", "/", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Truncated || resp.ContentLength != 4096 {
+		t.Errorf("Truncated = %v, size = %d; want decoded body cut at 4096", resp.Truncated, resp.ContentLength)
+	}
+	if !bytes.Equal(resp.Body, bytes.Repeat([]byte("a"), 4096)) {
+		t.Error("expected the decoded prefix, not compressed bytes")
+	}
+}
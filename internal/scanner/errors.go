@@ -0,0 +1,71 @@
+package scanner
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+)
+
+// ErrorCategory classifies a failed request by what went wrong, so a dead
+// host (DNS, refused) can be told apart from an overloaded or throttling
+// one (timeouts, resets).
+type ErrorCategory string
+
+const (
+	ErrorDNS     ErrorCategory = "dns"
+	ErrorRefused ErrorCategory = "refused"
+	ErrorTLS     ErrorCategory = "tls"
+	ErrorTimeout ErrorCategory = "timeout"
+	ErrorReset   ErrorCategory = "reset"
+	ErrorOther   ErrorCategory = "other"
+)
+
+// ErrorCategories lists every category in footer order.
+var ErrorCategories = []ErrorCategory{ErrorDNS, ErrorRefused, ErrorTLS, ErrorTimeout, ErrorReset, ErrorOther}
+
+// CategorizeError returns the category of a request error, or "" for nil.
+func CategorizeError(err error) ErrorCategory {
+	if err == nil {
+		return ""
+	}
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &dnsErr):
+		return ErrorDNS
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return ErrorRefused
+	case isTLSError(err):
+		return ErrorTLS
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorTimeout
+	case errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.EPIPE),
+		errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return ErrorReset
+	}
+	return ErrorOther
+}
+
+// isTLSError reports whether err comes from the TLS handshake: a rejected
+// certificate, a TLS alert from the server, or a non-TLS reply.
+func isTLSError(err error) bool {
+	var verifyErr *tls.CertificateVerificationError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	var recordErr tls.RecordHeaderError
+	var alertErr tls.AlertError
+	switch {
+	case errors.As(err, &verifyErr), errors.As(err, &authorityErr), errors.As(err, &hostnameErr),
+		errors.As(err, &invalidErr), errors.As(err, &recordErr), errors.As(err, &alertErr):
+		return true
+	}
+	// Alerts received from the server aren't exported as a type, and
+	// net/http replaces the record header error of a plain HTTP reply.
+	msg := err.Error()
+	return strings.Contains(msg, "tls: ") || strings.Contains(msg, "HTTP response to HTTPS client")
+}
@@ -0,0 +1,90 @@
+package scanner
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/maxvaer/dirfuzz/internal/config"
+)
+
+func TestCategorizeError(t *testing.T) {
+	opErr := func(op string, err error) error {
+		return &url.Error{Op: "Get", URL: "http://target/admin", Err: &net.OpError{Op: op, Net: "tcp", Err: err}}
+	}
+	tests := []struct {
+		name string
+		err  error
+		want ErrorCategory
+	}{
+		{"nil", nil, ""},
+		{"no such host", &url.Error{Op: "Get", Err: &net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", Name: "nope.invalid", IsNotFound: true}}}, ErrorDNS},
+		{"dns timeout", &net.DNSError{Err: "i/o timeout", IsTimeout: true}, ErrorDNS},
+		{"connection refused", opErr("dial", os.NewSyscallError("connect", syscall.ECONNREFUSED)), ErrorRefused},
+		{"unknown authority", &url.Error{Op: "Get", Err: x509.UnknownAuthorityError{}}, ErrorTLS},
+		{"tls alert", opErr("remote error", errors.New("tls: handshake failure")), ErrorTLS},
+		{"dial timeout", opErr("dial", os.ErrDeadlineExceeded), ErrorTimeout},
+		{"client timeout", &url.Error{Op: "Get", Err: timeoutError{}}, ErrorTimeout},
+		{"connection reset", opErr("read", os.NewSyscallError("read", syscall.ECONNRESET)), ErrorReset},
+		{"closed mid-response", &url.Error{Op: "Get", Err: io.EOF}, ErrorReset},
+		{"unexpected EOF", fmt.Errorf("reading body: %w", io.ErrUnexpectedEOF), ErrorReset},
+		{"anything else", errors.New("stopped after 10 redirects"), ErrorOther},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CategorizeError(tt.err); got != tt.want {
+				t.Errorf("CategorizeError(%v) = %q, want %q", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+// timeoutError mimics the error http.Client returns when its Timeout fires.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "Client.Timeout exceeded while awaiting headers" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestCategorizeError_RealRequests(t *testing.T) {
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closedURL := closed.URL
+	closed.Close()
+
+	plain := httptest.NewServer(http.NotFoundHandler())
+	defer plain.Close()
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer slow.Close()
+
+	tests := []struct {
+		name string
+		url  string
+		want ErrorCategory
+	}{
+		{"closed port", closedURL, ErrorRefused},
+		{"https to a plain HTTP server", "https://" + plain.Listener.Addr().String(), ErrorTLS},
+		{"slow server", slow.URL, ErrorTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newTestRequester(t, &config.Options{URL: tt.url, Timeout: 50 * time.Millisecond})
+			_, err := req.Do(context.Background(), "GET", "admin", "")
+			if got := CategorizeError(err); got != tt.want {
+				t.Errorf("CategorizeError(%v) = %q, want %q", err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package scanner
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseJitter converts a --delay-jitter spec into an absolute duration.
+// The spec is either a duration ("200ms") or a percentage of the base
+// delay ("25%"). An empty spec means no jitter.
+func ParseJitter(spec string, base time.Duration) (time.Duration, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return 0, nil
+	}
+	if pct, ok := strings.CutSuffix(spec, "%"); ok {
+		p, err := strconv.ParseFloat(pct, 64)
+		if err != nil || p < 0 {
+			return 0, fmt.Errorf("invalid percentage %q", spec)
+		}
+		return time.Duration(float64(base) * p / 100), nil
+	}
+	d, err := time.ParseDuration(spec)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q", spec)
+	}
+	return d, nil
+}
+
+// jitterDelay returns delay shifted by a uniformly random amount in
+// [-jitter, +jitter], clamped so it is never negative.
+func jitterDelay(delay, jitter time.Duration) time.Duration {
+	if jitter <= 0 {
+		return delay
+	}
+	d := delay + time.Duration(rand.Int64N(int64(2*jitter)+1)) - jitter
+	if d < 0 {
+		return 0
+	}
+	return d
+}
@@ -0,0 +1,80 @@
+package scanner
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseJitter(t *testing.T) {
+	tests := []struct {
+		spec    string
+		base    time.Duration
+		want    time.Duration
+		wantErr bool
+	}{
+		{"", time.Second, 0, false},
+		{"200ms", time.Second, 200 * time.Millisecond, false},
+		{"25%", time.Second, 250 * time.Millisecond, false},
+		{"50%", 0, 0, false},
+		{"abc", time.Second, 0, true},
+		{"-1s", time.Second, 0, true},
+		{"-5%", time.Second, 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseJitter(tt.spec, tt.base)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseJitter(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseJitter(%q, %s) = %s, want %s", tt.spec, tt.base, got, tt.want)
+		}
+	}
+}
+
+func TestJitterDelay_Distribution(t *testing.T) {
+	const (
+		delay   = 100 * time.Millisecond
+		jitter  = 40 * time.Millisecond
+		samples = 10000
+	)
+
+	var below, above int
+	lo, hi := delay, delay
+	for i := 0; i < samples; i++ {
+		d := jitterDelay(delay, jitter)
+		if d < delay-jitter || d > delay+jitter {
+			t.Fatalf("sample %s outside [%s, %s]", d, delay-jitter, delay+jitter)
+		}
+		lo = min(lo, d)
+		hi = max(hi, d)
+		if d < delay {
+			below++
+		} else if d > delay {
+			above++
+		}
+	}
+
+	// Samples should reach close to both ends of the range.
+	if lo > delay-jitter*9/10 || hi < delay+jitter*9/10 {
+		t.Errorf("samples span [%s, %s], want close to [%s, %s]", lo, hi, delay-jitter, delay+jitter)
+	}
+	// And split roughly evenly around the base delay.
+	if below < samples*4/10 || above < samples*4/10 {
+		t.Errorf("uneven distribution: %d below, %d above base delay", below, above)
+	}
+}
+
+func TestJitterDelay_NeverNegative(t *testing.T) {
+	for i := 0; i < 10000; i++ {
+		if d := jitterDelay(10*time.Millisecond, 50*time.Millisecond); d < 0 {
+			t.Fatalf("got negative delay %s", d)
+		}
+	}
+}
+
+func TestJitterDelay_Disabled(t *testing.T) {
+	if d := jitterDelay(time.Second, 0); d != time.Second {
+		t.Errorf("jitterDelay with no jitter = %s, want 1s", d)
+	}
+}
@@ -0,0 +1,120 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"golang.org/x/net/proxy"
+)
+
+// configureProxy routes transport through the proxy at rawURL. HTTP(S)
+// proxies use the transport's Proxy hook; socks5:// and socks5h:// proxies
+// replace DialContext with a SOCKS5 dialer, authenticating with the
+// URL's userinfo if present.
+func configureProxy(transport *http.Transport, dialer *net.Dialer, rawURL string) error {
+	proxyURL, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid proxy URL %q: %w", rawURL, err)
+	}
+
+	switch proxyURL.Scheme {
+	case "socks5", "socks5h":
+		dial, err := socks5Dialer(proxyURL, dialer)
+		if err != nil {
+			return err
+		}
+		transport.DialContext = dial
+	default:
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	return nil
+}
+
+// socks5Dialer returns a DialContext func that tunnels through the SOCKS5
+// proxy at u, using forward to reach the proxy itself.
+func socks5Dialer(u *url.URL, forward *net.Dialer) (func(ctx context.Context, network, addr string) (net.Conn, error), error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: missing host", u.Redacted())
+	}
+	var auth *proxy.Auth
+	if u.User != nil {
+		password, _ := u.User.Password()
+		auth = &proxy.Auth{User: u.User.Username(), Password: password}
+	}
+	d, err := proxy.SOCKS5("tcp", u.Host, auth, forward)
+	if err != nil {
+		return nil, fmt.Errorf("socks5 proxy %q: %w", u.Redacted(), err)
+	}
+	cd, ok := d.(proxy.ContextDialer)
+	if !ok {
+		return nil, fmt.Errorf("socks5 proxy %q: dialer does not support contexts", u.Redacted())
+	}
+	return cd.DialContext, nil
+}
+
+// proxyRotator is a RoundTripper that spreads requests round-robin over
+// one transport per proxy (--proxy-file).
+type proxyRotator struct {
+	transports []*http.Transport
+	next       atomic.Uint64
+}
+
+// newProxyRotator clones base once per proxy URL and points each clone at
+// its proxy.
+func newProxyRotator(base *http.Transport, dialer *net.Dialer, proxies []string) (*proxyRotator, error) {
+	r := &proxyRotator{transports: make([]*http.Transport, len(proxies))}
+	for i, p := range proxies {
+		t := base.Clone()
+		if err := configureProxy(t, dialer, p); err != nil {
+			return nil, err
+		}
+		r.transports[i] = t
+	}
+	return r, nil
+}
+
+func (r *proxyRotator) RoundTrip(req *http.Request) (*http.Response, error) {
+	t := r.transports[(r.next.Add(1)-1)%uint64(len(r.transports))]
+	return t.RoundTrip(req)
+}
+
+// CloseIdleConnections lets http.Client.CloseIdleConnections reach every
+// underlying transport.
+func (r *proxyRotator) CloseIdleConnections() {
+	for _, t := range r.transports {
+		t.CloseIdleConnections()
+	}
+}
+
+// loadProxyFile reads one proxy URL per line, skipping blank lines and
+// # comments.
+func loadProxyFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening proxy file: %w", err)
+	}
+	defer f.Close()
+
+	var proxies []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			proxies = append(proxies, line)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("reading proxy file: %w", err)
+	}
+	if len(proxies) == 0 {
+		return nil, fmt.Errorf("proxy file %s contains no proxies", path)
+	}
+	return proxies, nil
+}
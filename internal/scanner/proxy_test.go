@@ -0,0 +1,196 @@
+package scanner
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/maxvaer/dirfuzz/internal/config"
+)
+
+// socks5Server is a minimal SOCKS5 server (CONNECT only) that requires
+// username/password authentication and counts tunnelled connections.
+type socks5Server struct {
+	ln          net.Listener
+	user, pass  string
+	connections atomic.Int32
+}
+
+func startSOCKS5(t *testing.T, user, pass string) *socks5Server {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &socks5Server{ln: ln, user: user, pass: pass}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.handle(conn)
+		}
+	}()
+	return s
+}
+
+func (s *socks5Server) handle(conn net.Conn) {
+	defer conn.Close()
+
+	// Greeting: VER NMETHODS METHODS...; insist on username/password (0x02).
+	hdr := make([]byte, 2)
+	if _, err := io.ReadFull(conn, hdr); err != nil {
+		return
+	}
+	if _, err := io.ReadFull(conn, make([]byte, hdr[1])); err != nil {
+		return
+	}
+	conn.Write([]byte{5, 2})
+
+	// RFC 1929 sub-negotiation: VER ULEN UNAME PLEN PASSWD.
+	ver := make([]byte, 2)
+	if _, err := io.ReadFull(conn, ver); err != nil {
+		return
+	}
+	uname := make([]byte, ver[1])
+	io.ReadFull(conn, uname)
+	plen := make([]byte, 1)
+	io.ReadFull(conn, plen)
+	passwd := make([]byte, plen[0])
+	io.ReadFull(conn, passwd)
+	if string(uname) != s.user || string(passwd) != s.pass {
+		conn.Write([]byte{1, 1})
+		return
+	}
+	conn.Write([]byte{1, 0})
+
+	// Request: VER CMD RSV ATYP DST.ADDR DST.PORT.
+	req := make([]byte, 4)
+	if _, err := io.ReadFull(conn, req); err != nil {
+		return
+	}
+	var host string
+	switch req[3] {
+	case 1:
+		ip := make([]byte, 4)
+		io.ReadFull(conn, ip)
+		host = net.IP(ip).String()
+	case 3:
+		n := make([]byte, 1)
+		io.ReadFull(conn, n)
+		name := make([]byte, n[0])
+		io.ReadFull(conn, name)
+		host = string(name)
+	default:
+		return
+	}
+	portBuf := make([]byte, 2)
+	io.ReadFull(conn, portBuf)
+	port := binary.BigEndian.Uint16(portBuf)
+
+	upstream, err := net.Dial("tcp", net.JoinHostPort(host, strconv.Itoa(int(port))))
+	if err != nil {
+		conn.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0})
+		return
+	}
+	defer upstream.Close()
+	s.connections.Add(1)
+	conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+
+	go io.Copy(upstream, conn)
+	io.Copy(conn, upstream)
+}
+
+func TestRequester_SOCKS5Proxy(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("via socks"))
+	}))
+	defer srv.Close()
+	socks := startSOCKS5(t, "alice", "s3cret")
+
+	req := newTestRequester(t, &config.Options{
+		URL:   srv.URL,
+		Proxy: "socks5://alice:s3cret@" + socks.ln.Addr().String(),
+	})
+	resp, err := req.Do(context.Background(), "GET", "/", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(resp.Body) != "via socks" {
+		t.Errorf("body = %q, want %q", resp.Body, "via socks")
+	}
+	if n := socks.connections.Load(); n != 1 {
+		t.Errorf("proxied connections = %d, want 1", n)
+	}
+}
+
+func TestRequester_SOCKS5ProxyBadCredentials(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	socks := startSOCKS5(t, "alice", "s3cret")
+
+	req := newTestRequester(t, &config.Options{
+		URL:   srv.URL,
+		Proxy: "socks5://alice:wrong@" + socks.ln.Addr().String(),
+	})
+	if _, err := req.Do(context.Background(), "GET", "/", ""); err == nil {
+		t.Fatal("expected error with wrong proxy credentials")
+	}
+	if n := socks.connections.Load(); n != 0 {
+		t.Errorf("proxied connections = %d, want 0", n)
+	}
+}
+
+// countingProxy starts an HTTP forward proxy that answers every request
+// itself and counts how many it saw.
+func countingProxy(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(200)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &hits
+}
+
+func TestRequester_ProxyFileRotation(t *testing.T) {
+	p1, hits1 := countingProxy(t)
+	p2, hits2 := countingProxy(t)
+
+	proxyFile := filepath.Join(t.TempDir(), "proxies.txt")
+	content := "# rotation pool\n" + p1.URL + "\n\n" + p2.URL + "\n"
+	if err := os.WriteFile(proxyFile, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	req := newTestRequester(t, &config.Options{URL: "http://target.invalid", ProxyFile: proxyFile})
+	for i := 0; i < 10; i++ {
+		if _, err := req.Do(context.Background(), "GET", "/", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if hits1.Load() != 5 || hits2.Load() != 5 {
+		t.Errorf("proxy hits = %d/%d, want 5/5", hits1.Load(), hits2.Load())
+	}
+}
+
+func TestLoadProxyFile_Empty(t *testing.T) {
+	proxyFile := filepath.Join(t.TempDir(), "proxies.txt")
+	if err := os.WriteFile(proxyFile, []byte("# nothing\n\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadProxyFile(proxyFile); err == nil {
+		t.Error("expected error for proxy file without proxies")
+	}
+}
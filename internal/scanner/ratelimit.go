@@ -0,0 +1,52 @@
+package scanner
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter caps the global request rate across all workers. It is a
+// token bucket with a burst of one: each caller reserves the next free
+// slot and sleeps until it arrives, so the rate stays accurate no matter
+// how many goroutines share it.
+type RateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// NewRateLimiter creates a limiter allowing at most rps requests per second.
+// Returns nil if rps is not positive (unlimited).
+func NewRateLimiter(rps int) *RateLimiter {
+	if rps <= 0 {
+		return nil
+	}
+	return &RateLimiter{interval: time.Second / time.Duration(rps)}
+}
+
+// Wait blocks until the caller may send a request. Returns the context's
+// error if it is cancelled first.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	slot := l.next
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	wait := time.Until(slot)
+	if wait <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
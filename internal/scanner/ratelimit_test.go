@@ -0,0 +1,64 @@
+package scanner
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewRateLimiterUnlimited(t *testing.T) {
+	if NewRateLimiter(0) != nil {
+		t.Fatal("expected nil limiter for rate 0")
+	}
+}
+
+func TestRateLimiterCapsRate(t *testing.T) {
+	const rps = 100
+	const window = 500 * time.Millisecond
+
+	l := NewRateLimiter(rps)
+	ctx, cancel := context.WithTimeout(context.Background(), window)
+	defer cancel()
+
+	var count atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < 25; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for l.Wait(ctx) == nil {
+				count.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// ~50 requests expected in a 500ms window at 100 req/s.
+	got := count.Load()
+	if got < 40 || got > 60 {
+		t.Fatalf("expected ~50 requests in %s at %d req/s, got %d", window, rps, got)
+	}
+}
+
+func TestRateLimiterRespectsCancel(t *testing.T) {
+	l := NewRateLimiter(1)
+	_ = l.Wait(context.Background()) // consume the first slot
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- l.Wait(ctx) }()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("expected context error after cancel")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Wait did not return after cancel")
+	}
+}
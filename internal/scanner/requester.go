@@ -3,12 +3,16 @@ package scanner
 import (
 	"context"
 	"crypto/md5"
-	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"net/http/cookiejar"
 	"net/url"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -26,6 +30,35 @@ type Response struct {
 	URL           string
 	RedirectURL   string
 	Duration      time.Duration
+	RetryAfter    time.Duration     // parsed Retry-After header, 0 if absent
+	RedirectChain []string          // "status location" per hop (--trace-redirects)
+	TLSSubject    string            // leaf certificate common name (HTTPS only)
+	TLSSANs       []string          // leaf certificate DNS and IP SANs (HTTPS only)
+	Headers       map[string]string // response headers selected with --show-headers
+	Truncated     bool              // body exceeded --max-body-size and was cut
+	Allow         []string          // methods listed in the Allow header
+	WebSocket     bool              // 101 answer to a --detect-ws upgrade
+	ContentType   string            // Content-Type header
+	Proto         string            // negotiated protocol, e.g. "HTTP/2.0"
+	RangeTotal    int64             // complete length from Content-Range (0 if absent or "*")
+}
+
+// Recorder receives every completed HTTP exchange, e.g. to write a HAR
+// log. body is the decoded response body. Implementations must be safe
+// for concurrent use.
+type Recorder interface {
+	Record(req *http.Request, resp *http.Response, body []byte, start time.Time, elapsed time.Duration)
+}
+
+// QueryMarker in a target URL's query string (e.g. ?id=FUZZ) switches from
+// path fuzzing to fuzzing that query parameter.
+const QueryMarker = "FUZZ"
+
+// IsQueryFuzz reports whether rawURL fuzzes a query parameter rather than
+// the path.
+func IsQueryFuzz(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && strings.Contains(u.RawQuery, QueryMarker)
 }
 
 // Requester wraps an HTTP client for directory fuzzing.
@@ -34,7 +67,17 @@ type Requester struct {
 	baseURL   *url.URL
 	headers   map[string]string
 	userAgent string
+	host      string // default Host header override (--host)
 	timeout   time.Duration
+	recorder  Recorder
+
+	traceRedirects int      // max redirect hops followed manually (0 = off)
+	showHeaders    []string // canonical names of response headers to capture
+	maxBodySize    int64    // bytes read per response body (0 = no cap)
+	query          *url.URL // target URL holding QueryMarker (nil = path fuzzing)
+	spoofer        *ipSpoofer
+	detectWS       bool // send WebSocket upgrade headers with every request
+	rawPaths       bool // send paths as-is (--no-urlencode)
 }
 
 // NewRequester creates a Requester from the provided options.
@@ -46,31 +89,71 @@ func NewRequester(opts *config.Options) (*Requester, error) {
 	if base.Scheme == "" {
 		base.Scheme = "http"
 	}
+	var query *url.URL
+	if strings.Contains(base.RawQuery, QueryMarker) {
+		q := *base
+		q.Fragment = ""
+		query = &q
+		base.RawQuery = ""
+	}
 	base.Path = strings.TrimRight(base.Path, "/")
 
+	dialer := &net.Dialer{
+		Timeout: opts.Timeout,
+	}
+	if len(opts.Resolvers) > 0 {
+		dialer.Resolver = newResolver(opts.Resolvers, opts.Timeout)
+	}
+
+	tlsConfig, err := buildTLSConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// A custom TLS config or dialer turns off Go's automatic HTTP/2, so
+	// requests use HTTP/1.1 unless --http2 asks for it. WebSocket upgrades
+	// need HTTP/1.1.
 	transport := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		DialContext: (&net.Dialer{
-			Timeout: opts.Timeout,
-		}).DialContext,
+		TLSClientConfig:     tlsConfig,
+		DialContext:         dialer.DialContext,
 		MaxIdleConnsPerHost: opts.Threads,
 		MaxIdleConns:        opts.Threads,
+		ForceAttemptHTTP2:   opts.HTTP2 && !opts.HTTP1 && !opts.DetectWS,
 	}
 
-	if opts.Proxy != "" {
-		proxyURL, err := url.Parse(opts.Proxy)
+	var rt http.RoundTripper = transport
+	switch {
+	case opts.ProxyFile != "":
+		proxies, err := loadProxyFile(opts.ProxyFile)
 		if err != nil {
-			return nil, fmt.Errorf("invalid proxy URL %q: %w", opts.Proxy, err)
+			return nil, err
+		}
+		rt, err = newProxyRotator(transport, dialer, proxies)
+		if err != nil {
+			return nil, err
+		}
+	case opts.Proxy != "":
+		if err := configureProxy(transport, dialer, opts.Proxy); err != nil {
+			return nil, err
 		}
-		transport.Proxy = http.ProxyURL(proxyURL)
 	}
 
 	client := &http.Client{
-		Transport: transport,
+		Transport: rt,
 		Timeout:   opts.Timeout,
 	}
 
-	if !opts.FollowRedirects {
+	// A shared jar makes the scan stateful: every worker sees cookies set
+	// by any earlier response, so results can depend on request order.
+	if opts.CookieJar {
+		jar, err := cookiejar.New(nil)
+		if err != nil {
+			return nil, fmt.Errorf("creating cookie jar: %w", err)
+		}
+		client.Jar = jar
+	}
+
+	if !opts.FollowRedirects || opts.TraceRedirects > 0 {
 		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
 			return http.ErrUseLastResponse
 		}
@@ -81,48 +164,170 @@ func NewRequester(opts *config.Options) (*Requester, error) {
 		ua = "dirfuzz/1.0"
 	}
 
+	headers, err := buildHeaders(opts)
+	if err != nil {
+		return nil, err
+	}
+	spoofer, err := newIPSpoofer(opts.SpoofIP, opts.Headers)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Requester{
 		client:    client,
 		baseURL:   base,
-		headers:   opts.Headers,
+		headers:   headers,
 		userAgent: ua,
+		host:      opts.HostHeader,
 		timeout:   opts.Timeout,
+
+		traceRedirects: opts.TraceRedirects,
+		showHeaders:    canonicalHeaders(opts.ShowHeaders),
+		maxBodySize:    opts.MaxBodySize,
+		query:          query,
+		spoofer:        spoofer,
+		detectWS:       opts.DetectWS,
+		rawPaths:       opts.NoURLEncode,
 	}, nil
 }
 
+// WithRawPaths returns a copy of r that sends paths as-is, for hand-made
+// paths whose special characters are the point (403 bypass variants).
+func (r *Requester) WithRawPaths() *Requester {
+	c := *r
+	c.rawPaths = true
+	return &c
+}
+
+// WithHeader returns a copy of r that also sends the header name: value,
+// for one-off probes such as --probe-range.
+func (r *Requester) WithHeader(name, value string) *Requester {
+	c := *r
+	c.headers = make(map[string]string, len(r.headers)+1)
+	for k, v := range r.headers {
+		c.headers[k] = v
+	}
+	c.headers[name] = value
+	return &c
+}
+
+// SetRecorder makes every subsequent request get reported to rec. It must
+// be called before the requester is shared between goroutines.
+func (r *Requester) SetRecorder(rec Recorder) {
+	r.recorder = rec
+}
+
+// canonicalHeaders canonicalizes and deduplicates header names, dropping
+// empty entries.
+func canonicalHeaders(names []string) []string {
+	var out []string
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		out = append(out, name)
+	}
+	return out
+}
+
+// buildHeaders returns the default headers sent with every request: the
+// explicit -H headers plus an Authorization header derived from
+// --basic-auth or --bearer. An explicit -H Authorization always wins.
+func buildHeaders(opts *config.Options) (map[string]string, error) {
+	headers := make(map[string]string, len(opts.Headers)+1)
+	hasAuth := false
+	for k, v := range opts.Headers {
+		headers[k] = v
+		if http.CanonicalHeaderKey(k) == "Authorization" {
+			hasAuth = true
+		}
+	}
+	if hasAuth {
+		return headers, nil
+	}
+
+	switch {
+	case opts.BasicAuth != "":
+		if !strings.Contains(opts.BasicAuth, ":") {
+			return nil, fmt.Errorf("invalid basic auth %q, expected 'user:pass'", opts.BasicAuth)
+		}
+		headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(opts.BasicAuth))
+	case opts.BearerToken != "":
+		headers["Authorization"] = "Bearer " + opts.BearerToken
+	}
+	return headers, nil
+}
+
 // Do sends an HTTP request for the given path and returns the parsed response.
-// method defaults to GET if empty. host overrides the Host header if non-empty.
+// Unless the requester sends raw paths, each segment of path is
+// percent-encoded first (see escapePath).
+// When the target URL has a QueryMarker in its query, path is substituted
+// (query-escaped) for the marker instead and the URL path stays fixed.
+// method defaults to GET if empty. host overrides the Host header if non-empty;
+// otherwise the --host value is used, if set. With --trace-redirects, Do
+// follows redirects itself and returns the final hop, recording the earlier
+// hops in RedirectChain.
 func (r *Requester) Do(ctx context.Context, method, path, host string) (*Response, error) {
 	if method == "" {
 		method = http.MethodGet
 	}
-	targetURL := r.baseURL.String() + "/" + strings.TrimLeft(path, "/")
-
-	req, err := http.NewRequestWithContext(ctx, method, targetURL, nil)
-	if err != nil {
-		return nil, err
+	escaped := path
+	if !r.rawPaths {
+		escaped = escapePath(path)
 	}
-
-	req.Header.Set("User-Agent", r.userAgent)
-	for k, v := range r.headers {
-		req.Header.Set(k, v)
+	targetURL := r.baseURL.String() + "/" + strings.TrimLeft(escaped, "/")
+	if r.query != nil {
+		u := *r.query
+		u.RawQuery = strings.ReplaceAll(u.RawQuery, QueryMarker, url.QueryEscape(path))
+		targetURL = u.String()
 	}
-	if host != "" {
-		req.Host = host
+	if host == "" {
+		host = r.host
 	}
 
-	start := time.Now()
-	resp, err := r.client.Do(req)
+	resp, body, truncated, elapsed, err := r.roundTrip(ctx, method, targetURL, host, false)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("reading response body for %s: %w", path, err)
+	var chain []string
+	hopURL := targetURL
+	for hops := 0; hops < r.traceRedirects && isRedirect(resp.StatusCode); hops++ {
+		location := resp.Header.Get("Location")
+		if location == "" {
+			break
+		}
+		next, err := url.Parse(hopURL)
+		if err == nil {
+			next, err = next.Parse(location)
+		}
+		if err != nil {
+			break
+		}
+		chain = append(chain, fmt.Sprintf("%d %s", resp.StatusCode, location))
+
+		// Like a browser, switch to GET after a 303. Like net/http, keep
+		// the Host override and the user's headers (credentials included)
+		// only while the chain stays on the original server.
+		if resp.StatusCode == http.StatusSeeOther {
+			method = http.MethodGet
+		}
+		crossHost := next.Host != r.baseURL.Host
+		if crossHost {
+			host = ""
+		}
+		hopURL = next.String()
+
+		var hopElapsed time.Duration
+		resp, body, truncated, hopElapsed, err = r.roundTrip(ctx, method, hopURL, host, crossHost)
+		if err != nil {
+			return nil, err
+		}
+		elapsed += hopElapsed
 	}
-	elapsed := time.Since(start)
 
 	bodyStr := string(body)
 	wordCount := len(strings.Fields(bodyStr))
@@ -140,11 +345,211 @@ func (r *Requester) Do(ctx context.Context, method, path, host string) (*Respons
 		LineCount:     lineCount,
 		URL:           targetURL,
 		Duration:      elapsed,
+		RetryAfter:    parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		RedirectChain: chain,
+		Truncated:     truncated,
+		ContentType:   resp.Header.Get("Content-Type"),
+		Proto:         resp.Proto,
 	}
 
-	if resp.StatusCode >= 300 && resp.StatusCode < 400 {
+	if isRedirect(resp.StatusCode) {
 		result.RedirectURL = resp.Header.Get("Location")
 	}
+	if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+		result.TLSSubject, result.TLSSANs = certInfo(resp.TLS.PeerCertificates[0])
+	}
+	result.Headers = r.selectHeaders(resp.Header)
+	result.Allow = parseAllow(resp.Header.Values("Allow"))
+	result.RangeTotal = parseContentRange(resp.Header.Get("Content-Range"))
+	result.WebSocket = resp.StatusCode == http.StatusSwitchingProtocols &&
+		strings.EqualFold(resp.Header.Get("Upgrade"), "websocket")
 
 	return result, nil
 }
+
+// Fetch GETs rawURL, an absolute URL that need not be under the target,
+// through the requester's client: proxy, resolver, TLS settings, -H and
+// auth headers all apply, and --host too when rawURL is on the target's
+// host. It fails on a non-200 status or a body cut at --max-body-size.
+func (r *Requester) Fetch(ctx context.Context, rawURL string) ([]byte, error) {
+	host := ""
+	if u, err := url.Parse(rawURL); err == nil && u.Host == r.baseURL.Host {
+		host = r.host
+	}
+	resp, body, truncated, _, err := r.roundTrip(ctx, http.MethodGet, rawURL, host, false)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	if truncated {
+		return nil, fmt.Errorf("body larger than --max-body-size (%d bytes)", r.maxBodySize)
+	}
+	return body, nil
+}
+
+// roundTrip sends a single request and returns the response (with its body
+// already read and closed), the decoded body, whether the body was cut at
+// --max-body-size, and the elapsed time. A crossHost request (a redirect
+// hop to another server) goes without the -H, auth and --spoof-ip headers.
+func (r *Requester) roundTrip(ctx context.Context, method, targetURL, host string, crossHost bool) (*http.Response, []byte, bool, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, method, targetURL, nil)
+	if err != nil {
+		return nil, nil, false, 0, err
+	}
+
+	req.Header.Set("User-Agent", r.userAgent)
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	if r.detectWS {
+		setUpgradeHeaders(req)
+	}
+	if !crossHost {
+		for k, v := range r.headers {
+			req.Header.Set(k, v)
+		}
+		if r.spoofer != nil {
+			r.spoofer.apply(req)
+		}
+	}
+	if host != "" {
+		req.Host = host
+	}
+
+	start := time.Now()
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, nil, false, 0, err
+	}
+	defer resp.Body.Close()
+
+	// After 101 Switching Protocols the body is the upgraded connection,
+	// which never ends; there is nothing to read.
+	if resp.StatusCode == http.StatusSwitchingProtocols {
+		elapsed := time.Since(start)
+		if r.recorder != nil {
+			r.recorder.Record(req, resp, nil, start, elapsed)
+		}
+		return resp, nil, false, elapsed, nil
+	}
+
+	// Read one byte past the cap so an exactly-sized body isn't flagged.
+	var bodyReader io.Reader = resp.Body
+	if r.maxBodySize > 0 {
+		bodyReader = io.LimitReader(resp.Body, r.maxBodySize+1)
+	}
+	body, err := io.ReadAll(bodyReader)
+	if err != nil {
+		return nil, nil, false, 0, fmt.Errorf("reading response body for %s: %w", targetURL, err)
+	}
+	elapsed := time.Since(start)
+	truncated := r.maxBodySize > 0 && int64(len(body)) > r.maxBodySize
+	body = decodeBody(resp.Header.Get("Content-Encoding"), body, r.maxBodySize)
+	if r.maxBodySize > 0 && int64(len(body)) > r.maxBodySize {
+		body = body[:r.maxBodySize]
+		truncated = true
+	}
+	if r.recorder != nil {
+		r.recorder.Record(req, resp, body, start, elapsed)
+	}
+	return resp, body, truncated, elapsed, nil
+}
+
+// selectHeaders returns the --show-headers headers present in h. Repeated
+// headers are joined with ", ". Only the configured headers are kept so
+// results stay small.
+func (r *Requester) selectHeaders(h http.Header) map[string]string {
+	var selected map[string]string
+	for _, name := range r.showHeaders {
+		values := h.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+		if selected == nil {
+			selected = make(map[string]string, len(r.showHeaders))
+		}
+		selected[name] = strings.Join(values, ", ")
+	}
+	return selected
+}
+
+// certInfo returns the common name and subject alternative names (DNS
+// names, then IPs) of cert.
+func certInfo(cert *x509.Certificate) (string, []string) {
+	sans := make([]string, 0, len(cert.DNSNames)+len(cert.IPAddresses))
+	sans = append(sans, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	return cert.Subject.CommonName, sans
+}
+
+func isRedirect(status int) bool {
+	return status >= 300 && status < 400
+}
+
+// parseAllow splits Allow header values into upper-case method names,
+// dropping empty entries and duplicates.
+func parseAllow(values []string) []string {
+	var methods []string
+	for _, v := range values {
+		for _, m := range strings.Split(v, ",") {
+			m = strings.ToUpper(strings.TrimSpace(m))
+			if m != "" && !slices.Contains(methods, m) {
+				methods = append(methods, m)
+			}
+		}
+	}
+	return methods
+}
+
+// parseContentRange returns the complete length from a Content-Range
+// header such as "bytes 0-0/1234", or 0 if it is missing or unknown ("*").
+func parseContentRange(v string) int64 {
+	_, total, ok := strings.Cut(v, "/")
+	if !ok {
+		return 0
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(total), 10, 64)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// escapePath percent-encodes the characters of each segment of path that
+// are not allowed in a URL path segment, such as spaces, '#' and '?', so a
+// wordlist entry can't end the path early or smuggle in a query. Existing
+// %XX escapes, dots, and RFC 3986 sub-delimiters (";", "=", "&", ...) are
+// kept, so pre-encoded entries and path parameters pass through unchanged.
+func escapePath(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		switch {
+		case c == '/' || isPathChar(c):
+			b.WriteByte(c)
+		case c == '%' && i+2 < len(path) && isHex(path[i+1]) && isHex(path[i+2]):
+			b.WriteByte(c)
+		default:
+			b.WriteByte('%')
+			b.WriteByte("0123456789ABCDEF"[c>>4])
+			b.WriteByte("0123456789ABCDEF"[c&15])
+		}
+	}
+	return b.String()
+}
+
+// isPathChar reports whether c may appear unescaped in a path segment:
+// unreserved, sub-delims, ':' and '@'.
+func isPathChar(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return true
+	}
+	return strings.IndexByte("-._~!$&'()*+,;=:@", c) >= 0
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
@@ -0,0 +1,645 @@
+package scanner
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/maxvaer/dirfuzz/internal/config"
+)
+
+// captureHeader starts a server that records the named request header.
+func captureHeader(t *testing.T, name string) (*httptest.Server, *string) {
+	t.Helper()
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get(name)
+		w.WriteHeader(200)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &got
+}
+
+func newTestRequester(t *testing.T, opts *config.Options) *Requester {
+	t.Helper()
+	if opts.Timeout == 0 {
+		opts.Timeout = 5 * time.Second
+	}
+	if opts.Threads == 0 {
+		opts.Threads = 1
+	}
+	req, err := NewRequester(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+func TestRequester_BasicAuth(t *testing.T) {
+	srv, got := captureHeader(t, "Authorization")
+	req := newTestRequester(t, &config.Options{URL: srv.URL, BasicAuth: "user:pass"})
+
+	if _, err := req.Do(context.Background(), "GET", "/", ""); err != nil {
+		t.Fatal(err)
+	}
+	// base64("user:pass") == "dXNlcjpwYXNz"
+	if *got != "Basic dXNlcjpwYXNz" {
+		t.Errorf("Authorization = %q, want %q", *got, "Basic dXNlcjpwYXNz")
+	}
+}
+
+func TestRequester_Bearer(t *testing.T) {
+	srv, got := captureHeader(t, "Authorization")
+	req := newTestRequester(t, &config.Options{URL: srv.URL, BearerToken: "abc123"})
+
+	if _, err := req.Do(context.Background(), "GET", "/", ""); err != nil {
+		t.Fatal(err)
+	}
+	if *got != "Bearer abc123" {
+		t.Errorf("Authorization = %q, want %q", *got, "Bearer abc123")
+	}
+}
+
+func TestRequester_HeaderOverridesAuthFlags(t *testing.T) {
+	srv, got := captureHeader(t, "Authorization")
+	req := newTestRequester(t, &config.Options{
+		URL:       srv.URL,
+		BasicAuth: "user:pass",
+		Headers:   map[string]string{"authorization": "Token explicit"},
+	})
+
+	if _, err := req.Do(context.Background(), "GET", "/", ""); err != nil {
+		t.Fatal(err)
+	}
+	if *got != "Token explicit" {
+		t.Errorf("Authorization = %q, want explicit -H value", *got)
+	}
+}
+
+func TestRequester_InvalidBasicAuth(t *testing.T) {
+	_, err := NewRequester(&config.Options{URL: "http://example.com", BasicAuth: "nocolon"})
+	if err == nil {
+		t.Fatal("expected error for basic auth without colon")
+	}
+}
+
+func TestRequester_HostOverride(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Host
+		w.WriteHeader(200)
+	}))
+	defer srv.Close()
+
+	req := newTestRequester(t, &config.Options{URL: srv.URL, HostHeader: "www.target.com"})
+
+	if _, err := req.Do(context.Background(), "GET", "/admin", ""); err != nil {
+		t.Fatal(err)
+	}
+	if got != "www.target.com" {
+		t.Errorf("Host = %q, want www.target.com", got)
+	}
+
+	// A per-item host (vhost fuzzing) still takes precedence.
+	if _, err := req.Do(context.Background(), "GET", "/", "dev.target.com"); err != nil {
+		t.Fatal(err)
+	}
+	if got != "dev.target.com" {
+		t.Errorf("Host = %q, want dev.target.com", got)
+	}
+}
+
+func TestRequester_RetryAfter(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "3")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+	req := newTestRequester(t, &config.Options{URL: srv.URL})
+
+	resp, err := req.Do(context.Background(), "GET", "/", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.RetryAfter != 3*time.Second {
+		t.Errorf("RetryAfter = %s, want 3s", resp.RetryAfter)
+	}
+}
+
+func TestRequester_TraceRedirects(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/a", http.StatusMovedPermanently)
+		case "/a":
+			http.Redirect(w, r, "/b", http.StatusFound)
+		case "/b":
+			http.Redirect(w, r, "/final", http.StatusTemporaryRedirect)
+		case "/final":
+			w.Write([]byte("landed"))
+		}
+	}))
+	defer srv.Close()
+	req := newTestRequester(t, &config.Options{URL: srv.URL, TraceRedirects: 5})
+
+	resp, err := req.Do(context.Background(), "GET", "old", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != 200 || string(resp.Body) != "landed" {
+		t.Errorf("final = %d %q, want 200 \"landed\"", resp.StatusCode, resp.Body)
+	}
+	want := []string{"301 /a", "302 /b", "307 /final"}
+	if strings.Join(resp.RedirectChain, ",") != strings.Join(want, ",") {
+		t.Errorf("RedirectChain = %v, want %v", resp.RedirectChain, want)
+	}
+	if resp.RedirectURL != "" {
+		t.Errorf("RedirectURL = %q, want empty for a non-redirect final hop", resp.RedirectURL)
+	}
+	if resp.URL != srv.URL+"/old" {
+		t.Errorf("URL = %q, want the scanned path", resp.URL)
+	}
+}
+
+func TestRequester_TraceRedirectsCrossHostDropsCredentials(t *testing.T) {
+	var gotAuth, gotCustom, gotSpoof string
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotCustom = r.Header.Get("X-Api-Key")
+		gotSpoof = r.Header.Get("X-Forwarded-For")
+		w.Write([]byte("elsewhere"))
+	}))
+	defer other.Close()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			t.Error("first hop did not get the Authorization header")
+		}
+		http.Redirect(w, r, other.URL+"/landing", http.StatusFound)
+	}))
+	defer srv.Close()
+	req := newTestRequester(t, &config.Options{
+		URL:            srv.URL,
+		TraceRedirects: 5,
+		BearerToken:    "secret",
+		Headers:        map[string]string{"X-Api-Key": "key"},
+		SpoofIP:        "random",
+	})
+
+	resp, err := req.Do(context.Background(), "GET", "old", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(resp.Body) != "elsewhere" {
+		t.Fatalf("final body = %q, want the other server's page", resp.Body)
+	}
+	if gotAuth != "" || gotCustom != "" || gotSpoof != "" {
+		t.Errorf("cross-host hop got Authorization=%q X-Api-Key=%q X-Forwarded-For=%q, want none", gotAuth, gotCustom, gotSpoof)
+	}
+}
+
+func TestRequester_TraceRedirectsLoopStopsAtLimit(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if r.URL.Path == "/ping" {
+			http.Redirect(w, r, "/pong", http.StatusFound)
+		} else {
+			http.Redirect(w, r, "/ping", http.StatusFound)
+		}
+	}))
+	defer srv.Close()
+	req := newTestRequester(t, &config.Options{URL: srv.URL, TraceRedirects: 3})
+
+	resp, err := req.Do(context.Background(), "GET", "ping", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.RedirectChain) != 3 {
+		t.Errorf("RedirectChain has %d hops, want 3: %v", len(resp.RedirectChain), resp.RedirectChain)
+	}
+	if hits.Load() != 4 {
+		t.Errorf("server saw %d requests, want 4 (initial + 3 hops)", hits.Load())
+	}
+	if resp.StatusCode != http.StatusFound || resp.RedirectURL != "/ping" {
+		t.Errorf("final = %d -> %q, want 302 -> /ping", resp.StatusCode, resp.RedirectURL)
+	}
+}
+
+// selfSignedCert returns a TLS certificate for the given common name and
+// DNS SANs, also valid for 127.0.0.1.
+func selfSignedCert(t *testing.T, cn string, dnsNames ...string) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		DNSNames:     dnsNames,
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestRequester_TLSCertInfoAndSNI(t *testing.T) {
+	var sni string
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sni = r.TLS.ServerName
+	}))
+	srv.TLS = &tls.Config{Certificates: []tls.Certificate{selfSignedCert(t, "dirfuzz.test", "www.dirfuzz.test")}}
+	srv.StartTLS()
+	defer srv.Close()
+
+	req := newTestRequester(t, &config.Options{URL: srv.URL, SNI: "www.dirfuzz.test"})
+	resp, err := req.Do(context.Background(), "GET", "/", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.TLSSubject != "dirfuzz.test" {
+		t.Errorf("TLSSubject = %q, want %q", resp.TLSSubject, "dirfuzz.test")
+	}
+	if want := "www.dirfuzz.test,127.0.0.1"; strings.Join(resp.TLSSANs, ",") != want {
+		t.Errorf("TLSSANs = %v, want %s", resp.TLSSANs, want)
+	}
+	if sni != "www.dirfuzz.test" {
+		t.Errorf("server saw SNI %q, want %q", sni, "www.dirfuzz.test")
+	}
+}
+
+func TestRequester_NoTLSInfoOverHTTP(t *testing.T) {
+	srv, _ := captureHeader(t, "Host")
+	req := newTestRequester(t, &config.Options{URL: srv.URL})
+
+	resp, err := req.Do(context.Background(), "GET", "/", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.TLSSubject != "" || resp.TLSSANs != nil {
+		t.Errorf("plain HTTP response has TLS info: %q %v", resp.TLSSubject, resp.TLSSANs)
+	}
+}
+
+func TestRequester_ShowHeaders(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "nginx/1.25")
+		w.Header().Set("X-Powered-By", "PHP/8.2")
+		w.Header().Add("Set-Cookie", "a=1")
+		w.Header().Add("Set-Cookie", "b=2")
+		w.Header().Set("X-Secret", "hidden")
+	}))
+	defer srv.Close()
+
+	req := newTestRequester(t, &config.Options{
+		URL:         srv.URL,
+		ShowHeaders: []string{"server", " X-Powered-By", "Set-Cookie", "X-Missing"},
+	})
+	resp, err := req.Do(context.Background(), "GET", "/", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"Server":       "nginx/1.25",
+		"X-Powered-By": "PHP/8.2",
+		"Set-Cookie":   "a=1, b=2",
+	}
+	if len(resp.Headers) != len(want) {
+		t.Errorf("Headers = %v, want %v", resp.Headers, want)
+	}
+	for name, value := range want {
+		if resp.Headers[name] != value {
+			t.Errorf("Headers[%q] = %q, want %q", name, resp.Headers[name], value)
+		}
+	}
+	if _, ok := resp.Headers["X-Secret"]; ok {
+		t.Error("unconfigured header X-Secret should be dropped")
+	}
+}
+
+func TestRequester_NoHeadersByDefault(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "nginx")
+	}))
+	defer srv.Close()
+
+	req := newTestRequester(t, &config.Options{URL: srv.URL})
+	resp, err := req.Do(context.Background(), "GET", "/", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Headers != nil {
+		t.Errorf("Headers = %v, want nil without --show-headers", resp.Headers)
+	}
+}
+
+func TestRequester_CookieJar(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Path: "/"})
+			return
+		}
+		if c, err := r.Cookie("session"); err != nil || c.Value != "abc" {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer srv.Close()
+
+	for _, tt := range []struct {
+		jar  bool
+		want int
+	}{
+		{false, http.StatusForbidden},
+		{true, http.StatusOK},
+	} {
+		req := newTestRequester(t, &config.Options{URL: srv.URL, CookieJar: tt.jar})
+		if _, err := req.Do(context.Background(), "GET", "/", ""); err != nil {
+			t.Fatal(err)
+		}
+		for _, path := range []string{"admin", "api/users"} {
+			resp, err := req.Do(context.Background(), "GET", path, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.want {
+				t.Errorf("jar=%v: /%s status = %d, want %d", tt.jar, path, resp.StatusCode, tt.want)
+			}
+		}
+	}
+}
+
+func TestRequester_MaxBodySize(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Stream well past the cap so the limit, not the server, ends the read.
+		line := strings.Repeat("x", 9) + "\n"
+		for i := 0; i < 10000; i++ {
+			if _, err := io.WriteString(w, line); err != nil {
+				return
+			}
+		}
+	}))
+	defer srv.Close()
+
+	req := newTestRequester(t, &config.Options{URL: srv.URL, MaxBodySize: 1000})
+	resp, err := req.Do(context.Background(), "GET", "/", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Truncated {
+		t.Error("expected Truncated for a body over --max-body-size")
+	}
+	if resp.ContentLength != 1000 {
+		t.Errorf("ContentLength = %d, want 1000", resp.ContentLength)
+	}
+	if resp.WordCount != 100 || resp.BodyHash != md5.Sum(resp.Body) {
+		t.Errorf("metrics not computed over the truncated body: words = %d", resp.WordCount)
+	}
+}
+
+func TestRequester_MaxBodySizeExact(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, strings.Repeat("a", 1000))
+	}))
+	defer srv.Close()
+
+	req := newTestRequester(t, &config.Options{URL: srv.URL, MaxBodySize: 1000})
+	resp, err := req.Do(context.Background(), "GET", "/", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Truncated || resp.ContentLength != 1000 {
+		t.Errorf("Truncated = %v, size = %d; want a body of exactly the cap kept whole",
+			resp.Truncated, resp.ContentLength)
+	}
+}
+
+func TestRequester_QueryFuzz(t *testing.T) {
+	var gotPath, gotQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery = r.URL.Path, r.URL.RawQuery
+	}))
+	defer srv.Close()
+
+	req := newTestRequester(t, &config.Options{URL: srv.URL + "/api/item?id=FUZZ&v=1"})
+	for _, tt := range []struct{ value, wantQuery string }{
+		{"42", "id=42&v=1"},
+		{"a b&c=d", "id=a+b%26c%3Dd&v=1"},
+		{"../etc/passwd", "id=..%2Fetc%2Fpasswd&v=1"},
+	} {
+		resp, err := req.Do(context.Background(), "GET", tt.value, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gotPath != "/api/item" || gotQuery != tt.wantQuery {
+			t.Errorf("value %q: server got %s?%s, want /api/item?%s", tt.value, gotPath, gotQuery, tt.wantQuery)
+		}
+		if want := srv.URL + "/api/item?" + tt.wantQuery; resp.URL != want {
+			t.Errorf("URL = %q, want %q", resp.URL, want)
+		}
+	}
+}
+
+func TestRequester_URLEncoding(t *testing.T) {
+	var gotPath, gotRawPath, gotQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotRawPath, gotQuery = r.URL.Path, r.URL.EscapedPath(), r.URL.RawQuery
+	}))
+	defer srv.Close()
+
+	req := newTestRequester(t, &config.Options{URL: srv.URL})
+	for _, tt := range []struct{ entry, wantPath, wantRaw string }{
+		{"my files/report.pdf", "/my files/report.pdf", "/my%20files/report.pdf"},
+		{"notes#1.txt", "/notes#1.txt", "/notes%231.txt"},
+		{"faq?.html", "/faq?.html", "/faq%3F.html"},
+		{"café", "/café", "/caf%C3%A9"},
+		{"admin%2ephp", "/admin.php", "/admin%2ephp"},
+		{"..;/admin", "/..;/admin", "/..;/admin"},
+		{"index.php.bak", "/index.php.bak", "/index.php.bak"},
+	} {
+		resp, err := req.Do(context.Background(), "GET", tt.entry, "")
+		if err != nil {
+			t.Fatalf("%q: %v", tt.entry, err)
+		}
+		if gotPath != tt.wantPath || gotRawPath != tt.wantRaw || gotQuery != "" {
+			t.Errorf("%q: server got path %q (raw %q, query %q), want %q (raw %q)", tt.entry, gotPath, gotRawPath, gotQuery, tt.wantPath, tt.wantRaw)
+		}
+		if want := srv.URL + tt.wantRaw; resp.URL != want {
+			t.Errorf("%q: URL = %q, want %q", tt.entry, resp.URL, want)
+		}
+	}
+
+	raw := newTestRequester(t, &config.Options{URL: srv.URL, NoURLEncode: true})
+	if _, err := raw.Do(context.Background(), "GET", "faq?x=1", ""); err != nil {
+		t.Fatal(err)
+	}
+	if gotPath != "/faq" || gotQuery != "x=1" {
+		t.Errorf("--no-urlencode: server got %q?%q, want /faq?x=1", gotPath, gotQuery)
+	}
+}
+
+func TestIsQueryFuzz(t *testing.T) {
+	for url, want := range map[string]bool{
+		"https://x/api?id=FUZZ":   true,
+		"https://x/FUZZ?id=1":     false,
+		"https://x/api":           false,
+		"https://x/api?id=1#FUZZ": false,
+	} {
+		if got := IsQueryFuzz(url); got != want {
+			t.Errorf("IsQueryFuzz(%q) = %v, want %v", url, got, want)
+		}
+	}
+}
+
+func TestRequester_SpoofIP(t *testing.T) {
+	var mu sync.Mutex
+	var got []http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		got = append(got, r.Header.Clone())
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	for _, mode := range []string{SpoofRandom, SpoofSequential} {
+		got = nil
+		req := newTestRequester(t, &config.Options{URL: srv.URL, SpoofIP: mode})
+		for i := 0; i < 5; i++ {
+			if _, err := req.Do(context.Background(), "GET", "/", ""); err != nil {
+				t.Fatal(err)
+			}
+		}
+		seen := make(map[string]bool)
+		for _, h := range got {
+			ip := h.Get("X-Forwarded-For")
+			if net.ParseIP(ip) == nil {
+				t.Fatalf("%s: X-Forwarded-For = %q, want an IP", mode, ip)
+			}
+			if h.Get("X-Real-IP") != ip || h.Get("X-Originating-IP") != ip {
+				t.Errorf("%s: spoof headers disagree: %v", mode, h)
+			}
+			seen[ip] = true
+		}
+		if len(seen) < 2 {
+			t.Errorf("%s: spoofed IP did not vary across requests: %v", mode, seen)
+		}
+	}
+	if got[0].Get("X-Forwarded-For") != "10.0.0.1" || got[1].Get("X-Forwarded-For") != "10.0.0.2" {
+		t.Errorf("sequential mode should count up from 10.0.0.1, got %s, %s",
+			got[0].Get("X-Forwarded-For"), got[1].Get("X-Forwarded-For"))
+	}
+}
+
+func TestRequester_SpoofIPExplicitHeaderWins(t *testing.T) {
+	srv, got := captureHeader(t, "X-Forwarded-For")
+	req := newTestRequester(t, &config.Options{
+		URL:     srv.URL,
+		SpoofIP: SpoofRandom,
+		Headers: map[string]string{"x-forwarded-for": "127.0.0.1"},
+	})
+	if _, err := req.Do(context.Background(), "GET", "/", ""); err != nil {
+		t.Fatal(err)
+	}
+	if *got != "127.0.0.1" {
+		t.Errorf("X-Forwarded-For = %q, want the explicit -H value", *got)
+	}
+}
+
+func TestParseAllow(t *testing.T) {
+	got := parseAllow([]string{"GET, post,", " HEAD", "GET"})
+	if strings.Join(got, ",") != "GET,POST,HEAD" {
+		t.Errorf("parseAllow = %v, want [GET POST HEAD]", got)
+	}
+	if parseAllow(nil) != nil {
+		t.Error("expected nil without an Allow header")
+	}
+}
+
+func TestParseContentRange(t *testing.T) {
+	tests := map[string]int64{
+		"bytes 0-0/1234": 1234,
+		"bytes 0-0/*":    0,
+		"bytes */1234":   1234,
+		"":               0,
+		"bytes 0-0/-5":   0,
+	}
+	for in, want := range tests {
+		if got := parseContentRange(in); got != want {
+			t.Errorf("parseContentRange(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
+
+func TestRequester_DetectWS(t *testing.T) {
+	var mu sync.Mutex
+	var conns []net.Conn
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ws" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") || r.Header.Get("Sec-WebSocket-Key") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\n")
+		_ = rw.Flush()
+		// Leave the socket open like a real WebSocket server would.
+		mu.Lock()
+		conns = append(conns, conn)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+	defer func() {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, c := range conns {
+			c.Close()
+		}
+	}()
+
+	for _, tt := range []struct {
+		detect bool
+		path   string
+		status int
+		ws     bool
+	}{
+		{true, "ws", http.StatusSwitchingProtocols, true},
+		{true, "chat", http.StatusNotFound, false},
+		{false, "ws", http.StatusBadRequest, false},
+	} {
+		req := newTestRequester(t, &config.Options{URL: srv.URL, DetectWS: tt.detect})
+		resp, err := req.Do(context.Background(), "GET", tt.path, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != tt.status || resp.WebSocket != tt.ws {
+			t.Errorf("detect=%v /%s: status %d websocket %v, want %d %v",
+				tt.detect, tt.path, resp.StatusCode, resp.WebSocket, tt.status, tt.ws)
+		}
+	}
+}
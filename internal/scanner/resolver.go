@@ -0,0 +1,36 @@
+package scanner
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+// newResolver returns a resolver that sends all DNS queries to the given
+// servers instead of the system configuration, rotating through them
+// round-robin. Addresses without a port default to :53.
+func newResolver(servers []string, timeout time.Duration) *net.Resolver {
+	addrs := make([]string, len(servers))
+	for i, s := range servers {
+		addrs[i] = resolverAddr(s)
+	}
+
+	var next atomic.Uint64
+	dialer := &net.Dialer{Timeout: timeout}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			addr := addrs[(next.Add(1)-1)%uint64(len(addrs))]
+			return dialer.DialContext(ctx, network, addr)
+		},
+	}
+}
+
+// resolverAddr appends the default DNS port if s has none.
+func resolverAddr(s string) string {
+	if _, _, err := net.SplitHostPort(s); err == nil {
+		return s
+	}
+	return net.JoinHostPort(s, "53")
+}
@@ -0,0 +1,141 @@
+package scanner
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/maxvaer/dirfuzz/internal/config"
+)
+
+// fakeDNS is a minimal UDP DNS server that answers every A query with
+// 127.0.0.1 and every other query with an empty answer.
+type fakeDNS struct {
+	conn    net.PacketConn
+	queries atomic.Int32
+}
+
+func startFakeDNS(t *testing.T) *fakeDNS {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &fakeDNS{conn: conn}
+	t.Cleanup(func() { conn.Close() })
+	go d.serve()
+	return d
+}
+
+func (d *fakeDNS) addr() string { return d.conn.LocalAddr().String() }
+
+func (d *fakeDNS) serve() {
+	buf := make([]byte, 512)
+	for {
+		n, from, err := d.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		if n < 12 {
+			continue
+		}
+		d.queries.Add(1)
+
+		// Walk the question name to find where QTYPE starts.
+		end := 12
+		for end < n && buf[end] != 0 {
+			end += int(buf[end]) + 1
+		}
+		end++ // zero-length root label
+		if end+4 > n {
+			continue
+		}
+		qtype := binary.BigEndian.Uint16(buf[end : end+2])
+		question := buf[12 : end+4]
+
+		resp := make([]byte, 12, 64)
+		copy(resp[:2], buf[:2])                      // ID
+		binary.BigEndian.PutUint16(resp[2:], 0x8180) // standard response, no error
+		binary.BigEndian.PutUint16(resp[4:], 1)      // QDCOUNT
+		resp = append(resp, question...)
+		if qtype == 1 { // A
+			binary.BigEndian.PutUint16(resp[6:], 1) // ANCOUNT
+			resp = append(resp,
+				0xc0, 0x0c, // pointer to question name
+				0x00, 0x01, // TYPE A
+				0x00, 0x01, // CLASS IN
+				0x00, 0x00, 0x00, 0x3c, // TTL
+				0x00, 0x04, // RDLENGTH
+				127, 0, 0, 1,
+			)
+		}
+		_, _ = d.conn.WriteTo(resp, from)
+	}
+}
+
+func TestResolverAddr(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"10.0.0.53", "10.0.0.53:53"},
+		{"10.0.0.53:5353", "10.0.0.53:5353"},
+		{"::1", "[::1]:53"},
+		{"[::1]:53", "[::1]:53"},
+	}
+	for _, tt := range tests {
+		if got := resolverAddr(tt.in); got != tt.want {
+			t.Errorf("resolverAddr(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRequester_CustomResolver(t *testing.T) {
+	dns := startFakeDNS(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+
+	req, err := NewRequester(&config.Options{
+		URL:       "http://internal.dirfuzz.test:" + u.Port(),
+		Timeout:   5 * time.Second,
+		Threads:   1,
+		Resolvers: []string{dns.addr()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := req.Do(context.Background(), "GET", "/", "")
+	if err != nil {
+		t.Fatalf("request through custom resolver failed: %v", err)
+	}
+	if resp.StatusCode != 200 {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+	if dns.queries.Load() == 0 {
+		t.Error("expected lookups to go through the configured resolver")
+	}
+}
+
+func TestResolver_RoundRobin(t *testing.T) {
+	a := startFakeDNS(t)
+	b := startFakeDNS(t)
+	r := newResolver([]string{a.addr(), b.addr()}, 5*time.Second)
+
+	for i := 0; i < 4; i++ {
+		if _, err := r.LookupIPAddr(context.Background(), "rr.dirfuzz.test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if a.queries.Load() == 0 || b.queries.Load() == 0 {
+		t.Errorf("expected both resolvers to be used, got %d and %d queries",
+			a.queries.Load(), b.queries.Load())
+	}
+}
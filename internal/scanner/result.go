@@ -4,6 +4,7 @@ import "time"
 
 // ScanResult holds the outcome of a single path probe.
 type ScanResult struct {
+	Index         int    // position of the work item in its pass
 	Method        string // HTTP method used
 	Host          string // Host header override (vhost fuzzing)
 	Path          string
@@ -15,8 +16,24 @@ type ScanResult struct {
 	WordCount     int
 	LineCount     int
 	RedirectURL   string
+	RedirectChain []string          // earlier hops as "status location" (--trace-redirects)
+	TLSSubject    string            // certificate common name (HTTPS only)
+	TLSSANs       []string          // certificate subject alternative names (HTTPS only)
+	Headers       map[string]string // selected response headers (--show-headers)
+	Title         string            // HTML <title> (--extract-title)
+	BodyFile      string            // where the body was saved (--save-bodies)
+	Truncated     bool              // body cut at --max-body-size; metrics cover the prefix
+	Allow         []string          // methods from an OPTIONS probe's Allow header (--probe-methods)
+	AcceptsRange  bool              // answered a Range probe with 206 Partial Content (--probe-range)
+	FullSize      int64             // complete length from that 206's Content-Range (0 = unknown)
+	WebSocket     bool              // accepted a WebSocket upgrade (--detect-ws)
+	ContentType   string            // Content-Type response header
+	Proto         string            // negotiated protocol, e.g. "HTTP/1.1"
 	Duration      time.Duration
+	Slow          bool // took longer than --slow-threshold
+	Listing       bool // body is an open directory listing (--detect-listings)
 	Error         error
+	ErrorCategory ErrorCategory // kind of Error (see CategorizeError)
 	Filtered      bool
 	FilterReason  string
 }
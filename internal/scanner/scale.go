@@ -0,0 +1,67 @@
+package scanner
+
+import (
+	"context"
+	"sync"
+)
+
+// Scaler holds the target worker count of a running scan so it can be
+// changed on the fly (the + and - keys). Pools spawn workers when the
+// target rises and park the ones above it when it falls. It is safe for
+// concurrent use.
+type Scaler struct {
+	mu      sync.Mutex
+	target  int
+	changed chan struct{} // closed and replaced on every change
+}
+
+// NewScaler creates a Scaler targeting threads workers.
+func NewScaler(threads int) *Scaler {
+	return &Scaler{target: max(threads, 1), changed: make(chan struct{})}
+}
+
+// Target returns the current target worker count.
+func (s *Scaler) Target() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.target
+}
+
+// Add changes the target by delta, never going below one worker, and
+// returns the new target.
+func (s *Scaler) Add(delta int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n := max(s.target+delta, 1); n != s.target {
+		s.target = n
+		close(s.changed)
+		s.changed = make(chan struct{})
+	}
+	return s.target
+}
+
+// watch returns the target together with a channel that is closed on the
+// next change.
+func (s *Scaler) watch() (int, <-chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.target, s.changed
+}
+
+// park blocks worker id while it is above the target. It returns false if
+// the worker should exit instead: ctx was cancelled or done was closed.
+func (s *Scaler) park(ctx context.Context, id int, done <-chan struct{}) bool {
+	for {
+		target, changed := s.watch()
+		if id < target {
+			return true
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return false
+		case <-done:
+			return false
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/maxvaer/dirfuzz/internal/config"
+)
+
+func TestScalerAdd(t *testing.T) {
+	s := NewScaler(5)
+	if got := s.Add(5); got != 10 {
+		t.Errorf("Add(5) = %d, want 10", got)
+	}
+	if got := s.Add(-20); got != 1 {
+		t.Errorf("Add(-20) = %d, want at least one worker", got)
+	}
+	if got := NewScaler(0).Target(); got != 1 {
+		t.Errorf("NewScaler(0) target = %d, want 1", got)
+	}
+}
+
+// concurrencyServer records the peak number of requests in flight since
+// the last reset.
+func concurrencyServer(t *testing.T) (*httptest.Server, func() int64) {
+	t.Helper()
+	var inFlight, peak atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+		}
+		time.Sleep(10 * time.Millisecond)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() int64 { return peak.Swap(inFlight.Load()) }
+}
+
+func workItems(n int) []WorkItem {
+	items := make([]WorkItem, n)
+	for i := range items {
+		items[i] = WorkItem{Method: "GET", Path: fmt.Sprintf("p%d", i)}
+	}
+	return items
+}
+
+func TestRunWorkerPool_Scaler(t *testing.T) {
+	srv, resetPeak := concurrencyServer(t)
+	req := newTestRequester(t, &config.Options{URL: srv.URL, Threads: 8})
+	scaler := NewScaler(2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	results := RunWorkerPool(ctx, req, workItems(5000), WorkerConfig{
+		Throttler: NewThrottler(0, false, true),
+		Scaler:    scaler,
+	})
+	drained := make(chan struct{})
+	go func() {
+		for range results {
+		}
+		close(drained)
+	}()
+
+	window := func() int64 {
+		resetPeak()
+		time.Sleep(150 * time.Millisecond)
+		return resetPeak()
+	}
+
+	if peak := window(); peak != 2 {
+		t.Errorf("peak with 2 workers = %d", peak)
+	}
+	scaler.Add(4)
+	time.Sleep(20 * time.Millisecond)
+	if peak := window(); peak != 6 {
+		t.Errorf("peak after scaling up to 6 = %d", peak)
+	}
+	scaler.Add(-5)
+	time.Sleep(50 * time.Millisecond) // let in-flight requests finish
+	if peak := window(); peak != 1 {
+		t.Errorf("peak after scaling down to 1 = %d", peak)
+	}
+
+	cancel()
+	select {
+	case <-drained:
+	case <-time.After(2 * time.Second):
+		t.Fatal("pool did not stop after cancel")
+	}
+}
+
+func TestRunWorkerPool_ScalerParkedWorkersExit(t *testing.T) {
+	srv, _ := concurrencyServer(t)
+	req := newTestRequester(t, &config.Options{URL: srv.URL, Threads: 4})
+	scaler := NewScaler(4)
+	results := RunWorkerPool(context.Background(), req, workItems(20), WorkerConfig{
+		Throttler: NewThrottler(0, false, true),
+		Scaler:    scaler,
+	})
+	scaler.Add(-3)
+
+	done := make(chan int)
+	go func() {
+		n := 0
+		for range results {
+			n++
+		}
+		done <- n
+	}()
+	select {
+	case n := <-done:
+		if n != 20 {
+			t.Errorf("got %d results, want 20", n)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("results channel was not closed; parked workers did not exit")
+	}
+}
@@ -0,0 +1,68 @@
+package scanner
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"sync/atomic"
+)
+
+// Spoof modes accepted by --spoof-ip.
+const (
+	SpoofRandom     = "random"
+	SpoofSequential = "sequential"
+)
+
+// spoofHeaders carry the spoofed client address. Proxies and apps that
+// trust them may apply IP-based access rules to that address instead.
+var spoofHeaders = []string{"X-Forwarded-For", "X-Real-IP", "X-Originating-IP"}
+
+// ipSpoofer hands out a source address per request for spoofHeaders.
+type ipSpoofer struct {
+	sequential bool
+	next       atomic.Uint32
+	skip       map[string]bool // headers set explicitly with -H
+}
+
+// newIPSpoofer returns a spoofer for mode, or nil if mode is empty.
+// Headers already present in explicit are left alone so -H always wins.
+func newIPSpoofer(mode string, explicit map[string]string) (*ipSpoofer, error) {
+	switch mode {
+	case "":
+		return nil, nil
+	case SpoofRandom, SpoofSequential:
+	default:
+		return nil, fmt.Errorf("invalid spoof mode %q (want %s or %s)", mode, SpoofRandom, SpoofSequential)
+	}
+	s := &ipSpoofer{
+		sequential: mode == SpoofSequential,
+		skip:       make(map[string]bool),
+	}
+	for k := range explicit {
+		s.skip[http.CanonicalHeaderKey(k)] = true
+	}
+	return s, nil
+}
+
+// ip returns the next address: 10.0.0.1, 10.0.0.2, ... in sequential
+// mode, or a random address otherwise.
+func (s *ipSpoofer) ip() string {
+	var n uint32
+	if s.sequential {
+		n = 10<<24 | (s.next.Add(1) & 0x00ffffff)
+	} else {
+		n = rand.Uint32()
+	}
+	return net.IPv4(byte(n>>24), byte(n>>16), byte(n>>8), byte(n)).String()
+}
+
+// apply sets the spoof headers on req, all to the same address.
+func (s *ipSpoofer) apply(req *http.Request) {
+	ip := s.ip()
+	for _, h := range spoofHeaders {
+		if !s.skip[http.CanonicalHeaderKey(h)] {
+			req.Header.Set(h, ip)
+		}
+	}
+}
@@ -2,11 +2,18 @@ package scanner
 
 import (
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
 
+// maxRetryAfter caps how long a single Retry-After hint can stall the scan.
+const maxRetryAfter = 5 * time.Minute
+
 // Throttler provides adaptive rate limiting. When it detects 429 or
 // connection-reset responses, it exponentially backs off. When responses
 // are healthy, it gradually recovers to the original delay.
@@ -15,7 +22,8 @@ type Throttler struct {
 	baseDelay    time.Duration
 	currentDelay time.Duration
 	maxDelay     time.Duration
-	consecutive  int // consecutive throttle signals
+	consecutive  int       // consecutive throttle signals
+	retryUntil   time.Time // no requests before this (from Retry-After)
 	enabled      bool
 	quiet        bool
 }
@@ -31,19 +39,72 @@ func NewThrottler(baseDelay time.Duration, enabled, quiet bool) *Throttler {
 	}
 }
 
+// ThrottlerRegistry hands out one Throttler per host, so every target and
+// phase that visits a host shares its back-off state instead of starting
+// over at the base delay. It is safe for concurrent use.
+type ThrottlerRegistry struct {
+	mu         sync.Mutex
+	throttlers map[string]*Throttler
+	baseDelay  time.Duration
+	enabled    bool
+	quiet      bool
+}
+
+// NewThrottlerRegistry creates a registry whose throttlers are configured
+// as NewThrottler(baseDelay, enabled, quiet).
+func NewThrottlerRegistry(baseDelay time.Duration, enabled, quiet bool) *ThrottlerRegistry {
+	return &ThrottlerRegistry{
+		throttlers: make(map[string]*Throttler),
+		baseDelay:  baseDelay,
+		enabled:    enabled,
+		quiet:      quiet,
+	}
+}
+
+// Get returns the throttler for the host of targetURL, creating it on
+// first use. Ports and schemes are ignored, since rate limits usually
+// apply per host.
+func (r *ThrottlerRegistry) Get(targetURL string) *Throttler {
+	host := targetURL
+	if u, err := url.Parse(targetURL); err == nil && u.Hostname() != "" {
+		host = u.Hostname()
+	}
+	host = strings.ToLower(host)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.throttlers[host]
+	if !ok {
+		t = NewThrottler(r.baseDelay, r.enabled, r.quiet)
+		r.throttlers[host] = t
+	}
+	return t
+}
+
 // Delay returns the current per-request delay. Workers should call this
-// before each request.
+// before each request. While a Retry-After window is open the delay is
+// stretched to cover the rest of it.
 func (t *Throttler) Delay() time.Duration {
 	if !t.enabled {
 		return t.baseDelay
 	}
 	t.mu.Lock()
 	defer t.mu.Unlock()
+	if wait := time.Until(t.retryUntil); wait > t.currentDelay {
+		return wait
+	}
 	return t.currentDelay
 }
 
 // RecordStatus updates the throttler based on a response status code.
 func (t *Throttler) RecordStatus(statusCode int) {
+	t.RecordStatusWithRetryAfter(statusCode, 0)
+}
+
+// RecordStatusWithRetryAfter is RecordStatus plus the server's Retry-After
+// hint (0 if absent). On a 429 or 503 with a hint, no worker sends its next
+// request until at least retryAfter has passed (capped at 5 minutes).
+func (t *Throttler) RecordStatusWithRetryAfter(statusCode int, retryAfter time.Duration) {
 	if !t.enabled {
 		return
 	}
@@ -53,6 +114,17 @@ func (t *Throttler) RecordStatus(statusCode int) {
 
 	if statusCode == 429 || statusCode == 503 {
 		t.consecutive++
+		if retryAfter > 0 {
+			if retryAfter > maxRetryAfter {
+				retryAfter = maxRetryAfter
+			}
+			if until := time.Now().Add(retryAfter); until.After(t.retryUntil) {
+				t.retryUntil = until
+				if !t.quiet {
+					fmt.Fprintf(os.Stderr, "\n[!] Server sent Retry-After (HTTP %d) — pausing for %s\n", statusCode, retryAfter)
+				}
+			}
+		}
 		// Exponential back-off: double the delay, up to maxDelay.
 		newDelay := t.currentDelay * 2
 		if newDelay < 500*time.Millisecond {
@@ -110,3 +182,25 @@ func (t *Throttler) RecordError() {
 		}
 	}
 }
+
+// parseRetryAfter converts a Retry-After header value, either delay-seconds
+// or an HTTP-date, into a wait duration relative to now. It returns 0 for
+// empty, malformed, or past values.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs <= 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if d := at.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
@@ -0,0 +1,85 @@
+package scanner
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{"empty", "", 0},
+		{"seconds", "120", 120 * time.Second},
+		{"seconds with spaces", " 7 ", 7 * time.Second},
+		{"zero seconds", "0", 0},
+		{"negative seconds", "-5", 0},
+		{"http date", now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{"http date in past", now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"garbage", "soon", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRetryAfter(tt.value, now); got != tt.want {
+				t.Errorf("parseRetryAfter(%q) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestThrottler_RetryAfterExtendsDelay(t *testing.T) {
+	th := NewThrottler(0, true, true)
+	th.RecordStatusWithRetryAfter(429, 10*time.Second)
+
+	if d := th.Delay(); d < 9*time.Second || d > 10*time.Second {
+		t.Errorf("Delay() = %s, want ~10s", d)
+	}
+}
+
+func TestThrottler_RetryAfterIgnoredOnSuccess(t *testing.T) {
+	th := NewThrottler(0, true, true)
+	th.RecordStatusWithRetryAfter(200, 10*time.Second)
+
+	if d := th.Delay(); d != 0 {
+		t.Errorf("Delay() = %s, want 0", d)
+	}
+}
+
+func TestThrottler_RetryAfterCapped(t *testing.T) {
+	th := NewThrottler(0, true, true)
+	th.RecordStatusWithRetryAfter(503, time.Hour)
+
+	if d := th.Delay(); d > maxRetryAfter {
+		t.Errorf("Delay() = %s, want at most %s", d, maxRetryAfter)
+	}
+}
+
+func TestThrottler_RetryAfterDisabled(t *testing.T) {
+	th := NewThrottler(0, false, true)
+	th.RecordStatusWithRetryAfter(429, 10*time.Second)
+
+	if d := th.Delay(); d != 0 {
+		t.Errorf("Delay() = %s, want 0 when adaptive throttling is off", d)
+	}
+}
+
+func TestThrottlerRegistry(t *testing.T) {
+	r := NewThrottlerRegistry(0, true, true)
+	a := r.Get("http://Example.com/app/")
+	if b := r.Get("https://example.com:8443/other"); b != a {
+		t.Error("targets on the same host should share a throttler")
+	}
+	if c := r.Get("http://other.example.com/"); c == a {
+		t.Error("different hosts should get separate throttlers")
+	}
+
+	a.RecordStatus(429)
+	if d := r.Get("http://example.com/").Delay(); d < 500*time.Millisecond {
+		t.Errorf("shared Delay() = %s, want the back-off to carry over", d)
+	}
+}
@@ -0,0 +1,73 @@
+package scanner
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/maxvaer/dirfuzz/internal/config"
+)
+
+// buildTLSConfig returns the client TLS configuration. Certificates are
+// not verified unless --tls-verify or --ca-cert is set: most pentest
+// targets use self-signed or mismatched certificates. --client-cert and
+// --client-key add a certificate for mutual TLS.
+func buildTLSConfig(opts *config.Options) (*tls.Config, error) {
+	cfg := &tls.Config{
+		InsecureSkipVerify: !opts.TLSVerify && opts.CACert == "",
+		ServerName:         serverName(opts),
+	}
+	if opts.CACert != "" {
+		pem, err := os.ReadFile(opts.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA bundle %s contains no PEM certificates", opts.CACert)
+		}
+		cfg.RootCAs = pool
+	}
+	if opts.ClientCert != "" || opts.ClientKey != "" {
+		if opts.ClientCert == "" || opts.ClientKey == "" {
+			return nil, fmt.Errorf("client certificate and key must be provided together")
+		}
+		cert, err := tls.LoadX509KeyPair(opts.ClientCert, opts.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	if opts.HTTP1 {
+		cfg.NextProtos = []string{"http/1.1"}
+	}
+	return cfg, nil
+}
+
+// serverName returns the TLS server name (SNI) to send. --sni wins. An
+// IP-literal target (e.g. from --cidr) with a --host override sends that
+// host name, since servers that pick a certificate or site by SNI know
+// the name, not the address; many reject the handshake otherwise.
+// Otherwise it is left empty: the transport then uses the URL host, and
+// crypto/tls sends no SNI at all for an IP literal, as RFC 6066 requires.
+func serverName(opts *config.Options) string {
+	if opts.SNI != "" || opts.HostHeader == "" {
+		return opts.SNI
+	}
+	u, err := url.Parse(opts.URL)
+	if err != nil || net.ParseIP(u.Hostname()) == nil {
+		return ""
+	}
+	host := opts.HostHeader
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if net.ParseIP(strings.Trim(host, "[]")) != nil {
+		return ""
+	}
+	return host
+}
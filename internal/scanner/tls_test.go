@@ -0,0 +1,202 @@
+package scanner
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/maxvaer/dirfuzz/internal/config"
+)
+
+// selfSignedServer starts a TLS server using a fresh self-signed certificate
+// and returns it along with the path of that certificate in PEM form.
+func selfSignedServer(t *testing.T) (*httptest.Server, string) {
+	t.Helper()
+	cert := selfSignedCert(t, "dirfuzz.test")
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.TLS = &tls.Config{Certificates: []tls.Certificate{cert}}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]})
+	if err := os.WriteFile(caFile, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return srv, caFile
+}
+
+func TestRequester_TLSDefaultSkipsVerification(t *testing.T) {
+	srv, _ := selfSignedServer(t)
+	req := newTestRequester(t, &config.Options{URL: srv.URL})
+
+	if _, err := req.Do(context.Background(), "GET", "/", ""); err != nil {
+		t.Fatalf("default mode should accept self-signed certificates: %v", err)
+	}
+}
+
+func TestRequester_TLSVerifyRejectsSelfSigned(t *testing.T) {
+	srv, _ := selfSignedServer(t)
+	req := newTestRequester(t, &config.Options{URL: srv.URL, TLSVerify: true})
+
+	if _, err := req.Do(context.Background(), "GET", "/", ""); err == nil {
+		t.Fatal("expected certificate verification error")
+	}
+}
+
+func TestRequester_TLSVerifyWithCACert(t *testing.T) {
+	srv, caFile := selfSignedServer(t)
+	req := newTestRequester(t, &config.Options{URL: srv.URL, TLSVerify: true, CACert: caFile})
+
+	if _, err := req.Do(context.Background(), "GET", "/", ""); err != nil {
+		t.Fatalf("trusted CA should verify: %v", err)
+	}
+}
+
+func TestBuildTLSConfig_InvalidCABundle(t *testing.T) {
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, []byte("not a certificate"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := buildTLSConfig(&config.Options{CACert: caFile}); err == nil {
+		t.Error("expected error for bundle without certificates")
+	}
+}
+
+// writeKeyPair writes cert and its ECDSA key as PEM files and returns
+// their paths.
+func writeKeyPair(t *testing.T, cert tls.Certificate) (certFile, keyFile string) {
+	t.Helper()
+	dir := t.TempDir()
+	certFile = filepath.Join(dir, "client.pem")
+	keyFile = filepath.Join(dir, "client-key.pem")
+
+	keyDER, err := x509.MarshalECPrivateKey(cert.PrivateKey.(*ecdsa.PrivateKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if err := os.WriteFile(certFile, certPEM, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, keyPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestRequester_ClientCertificate(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	srv.StartTLS()
+	defer srv.Close()
+
+	certFile, keyFile := writeKeyPair(t, selfSignedCert(t, "dirfuzz-client"))
+	withCert := newTestRequester(t, &config.Options{URL: srv.URL, ClientCert: certFile, ClientKey: keyFile})
+	if _, err := withCert.Do(context.Background(), "GET", "/", ""); err != nil {
+		t.Fatalf("request with client certificate failed: %v", err)
+	}
+
+	withoutCert := newTestRequester(t, &config.Options{URL: srv.URL})
+	if _, err := withoutCert.Do(context.Background(), "GET", "/", ""); err == nil {
+		t.Fatal("request without client certificate should fail")
+	}
+}
+
+func TestBuildTLSConfig_ClientCertErrors(t *testing.T) {
+	certFile, _ := writeKeyPair(t, selfSignedCert(t, "dirfuzz-client"))
+	tests := []struct {
+		name string
+		opts config.Options
+	}{
+		{"cert without key", config.Options{ClientCert: certFile}},
+		{"key without cert", config.Options{ClientKey: certFile}},
+		{"missing key file", config.Options{ClientCert: certFile, ClientKey: filepath.Join(t.TempDir(), "missing.pem")}},
+	}
+	for _, tt := range tests {
+		if _, err := buildTLSConfig(&tt.opts); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+func TestRequester_HTTP1(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	tests := []struct {
+		name      string
+		opts      config.Options
+		wantProto string
+	}{
+		{"HTTP/1.1 by default", config.Options{URL: srv.URL}, "HTTP/1.1"},
+		{"--http2", config.Options{URL: srv.URL, HTTP2: true}, "HTTP/2.0"},
+		{"--http1", config.Options{URL: srv.URL, HTTP1: true}, "HTTP/1.1"},
+		{"--detect-ws", config.Options{URL: srv.URL, HTTP2: true, DetectWS: true}, "HTTP/1.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newTestRequester(t, &tt.opts)
+			resp, err := req.Do(context.Background(), "GET", "/", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.Proto != tt.wantProto {
+				t.Errorf("Proto = %q, want %q", resp.Proto, tt.wantProto)
+			}
+		})
+	}
+}
+
+func TestRequester_IPLiteralSNI(t *testing.T) {
+	var sni atomic.Value
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sni.Store(r.TLS.ServerName)
+	}))
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		opts    config.Options
+		wantSNI string
+	}{
+		{"IP literal sends no SNI", config.Options{URL: srv.URL}, ""},
+		{"IP literal with --host", config.Options{URL: srv.URL, HostHeader: "www.target.com"}, "www.target.com"},
+		{"--host port is dropped", config.Options{URL: srv.URL, HostHeader: "www.target.com:8443"}, "www.target.com"},
+		{"IP --host stays without SNI", config.Options{URL: srv.URL, HostHeader: "10.0.0.5"}, ""},
+		{"--sni wins", config.Options{URL: srv.URL, HostHeader: "www.target.com", SNI: "edge.target.com"}, "edge.target.com"},
+		{"host name URL keeps its own", config.Options{URL: "https://localhost:" + u.Port(), HostHeader: "www.target.com"}, "localhost"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newTestRequester(t, &tt.opts)
+			resp, err := req.Do(context.Background(), "GET", "/", "")
+			if err != nil {
+				t.Fatalf("handshake failed: %v", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("status = %d, want 200", resp.StatusCode)
+			}
+			if got := sni.Load(); got != tt.wantSNI {
+				t.Errorf("SNI = %q, want %q", got, tt.wantSNI)
+			}
+		})
+	}
+}
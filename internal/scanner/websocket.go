@@ -0,0 +1,20 @@
+package scanner
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+)
+
+// setUpgradeHeaders turns req into a WebSocket handshake (--detect-ws).
+// A server that answers 101 Switching Protocols has a WebSocket endpoint
+// at that path; everything else responds as it would to a plain request
+// or rejects the upgrade.
+func setUpgradeHeaders(req *http.Request) {
+	key := make([]byte, 16)
+	_, _ = rand.Read(key)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", base64.StdEncoding.EncodeToString(key))
+}
@@ -8,14 +8,27 @@ import (
 
 // WorkerConfig holds options for the worker pool.
 type WorkerConfig struct {
-	Threads   int
-	Throttler *Throttler
-	KeepBody  bool    // retain response body in ScanResult for body filters
-	Pauser    *Pauser // nil = no pause support
+	Threads     int
+	Throttler   *Throttler
+	RateLimiter *RateLimiter  // nil = no global rate cap
+	Jitter      time.Duration // random ± offset applied to each request's delay
+	KeepBody    bool          // retain response body in ScanResult for body filters
+	Pauser      *Pauser       // nil = no pause support
+	Scaler      *Scaler       // nil = fixed Threads workers
+}
+
+// indexedItem is a WorkItem tagged with its position in the work list.
+type indexedItem struct {
+	WorkItem
+	index int
 }
 
 // RunWorkerPool fans out work items across workers and returns a channel
 // of results. The channel is closed when all items have been processed.
+// Results arrive in completion order; each carries its item's Index.
+//
+// With cfg.Scaler set, the pool starts the scaler's target number of
+// workers instead of cfg.Threads and follows later changes to it.
 func RunWorkerPool(
 	ctx context.Context,
 	req *Requester,
@@ -23,77 +36,132 @@ func RunWorkerPool(
 	cfg WorkerConfig,
 ) <-chan ScanResult {
 	threads := cfg.Threads
-	itemsCh := make(chan WorkItem, threads*2)
+	if cfg.Scaler != nil {
+		threads = cfg.Scaler.Target()
+	}
+	itemsCh := make(chan indexedItem, threads*2)
 	resultsCh := make(chan ScanResult, threads*2)
 
 	var wg sync.WaitGroup
+	fed := make(chan struct{}) // closed once no more items will be sent
 
 	// Producer: feed items into channel.
 	go func() {
+		defer close(fed)
 		defer close(itemsCh)
-		for _, item := range items {
+		for i, item := range items {
 			select {
-			case itemsCh <- item:
+			case itemsCh <- indexedItem{WorkItem: item, index: i}:
 			case <-ctx.Done():
 				return
 			}
 		}
 	}()
 
-	// Workers: consume items, produce results.
-	for i := 0; i < threads; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for item := range itemsCh {
-				if cfg.Pauser != nil {
-					cfg.Pauser.Wait()
-				}
+	// Workers: consume items, produce results. Workers beyond the scaler's
+	// target park before taking their next item.
+	worker := func(id int) {
+		defer wg.Done()
+		for {
+			if cfg.Scaler != nil && !cfg.Scaler.park(ctx, id, fed) {
+				return
+			}
+			item, ok := <-itemsCh
+			if !ok {
+				return
+			}
+			if cfg.Pauser != nil {
+				cfg.Pauser.Wait()
+			}
 
-				delay := cfg.Throttler.Delay()
-				if delay > 0 {
-					select {
-					case <-time.After(delay):
-					case <-ctx.Done():
-						return
-					}
+			delay := jitterDelay(cfg.Throttler.Delay(), cfg.Jitter)
+			if delay > 0 {
+				select {
+				case <-time.After(delay):
+				case <-ctx.Done():
+					return
 				}
+			}
 
-				resp, err := req.Do(ctx, item.Method, item.Path, item.Host)
-				if err != nil {
-					if ctx.Err() != nil {
-						return
-					}
-					cfg.Throttler.RecordError()
-					resultsCh <- ScanResult{
-						Method: item.Method,
-						Host:   item.Host,
-						Path:   item.Path,
-						Error:  err,
-					}
-					continue
+			if cfg.RateLimiter != nil {
+				if err := cfg.RateLimiter.Wait(ctx); err != nil {
+					return
 				}
+			}
 
-				cfg.Throttler.RecordStatus(resp.StatusCode)
-
-				result := ScanResult{
+			resp, err := req.Do(ctx, item.Method, item.Path, item.Host)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				cfg.Throttler.RecordError()
+				resultsCh <- ScanResult{
+					Index:         item.index,
 					Method:        item.Method,
 					Host:          item.Host,
 					Path:          item.Path,
-					URL:           resp.URL,
-					StatusCode:    resp.StatusCode,
-					ContentLength: resp.ContentLength,
-					BodyHash:      resp.BodyHash,
-					WordCount:     resp.WordCount,
-					LineCount:     resp.LineCount,
-					RedirectURL:   resp.RedirectURL,
-					Duration:      resp.Duration,
-				}
-				if cfg.KeepBody {
-					result.Body = resp.Body
+					Error:         err,
+					ErrorCategory: CategorizeError(err),
 				}
+				continue
+			}
+
+			cfg.Throttler.RecordStatusWithRetryAfter(resp.StatusCode, resp.RetryAfter)
+
+			result := ScanResult{
+				Index:         item.index,
+				Method:        item.Method,
+				Host:          item.Host,
+				Path:          item.Path,
+				URL:           resp.URL,
+				StatusCode:    resp.StatusCode,
+				ContentLength: resp.ContentLength,
+				BodyHash:      resp.BodyHash,
+				WordCount:     resp.WordCount,
+				LineCount:     resp.LineCount,
+				RedirectURL:   resp.RedirectURL,
+				RedirectChain: resp.RedirectChain,
+				TLSSubject:    resp.TLSSubject,
+				TLSSANs:       resp.TLSSANs,
+				Headers:       resp.Headers,
+				Truncated:     resp.Truncated,
+				WebSocket:     resp.WebSocket,
+				ContentType:   resp.ContentType,
+				Proto:         resp.Proto,
+				Duration:      resp.Duration,
+			}
+			if cfg.KeepBody {
+				result.Body = resp.Body
+			}
+
+			resultsCh <- result
+		}
+	}
+	spawned := 0
+	for ; spawned < threads; spawned++ {
+		wg.Add(1)
+		go worker(spawned)
+	}
 
-				resultsCh <- result
+	// Scaler: start more workers whenever the target rises. Holding a wg
+	// slot keeps the results channel open while new workers may be added.
+	if cfg.Scaler != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				target, changed := cfg.Scaler.watch()
+				for ; spawned < target; spawned++ {
+					wg.Add(1)
+					go worker(spawned)
+				}
+				select {
+				case <-changed:
+				case <-fed:
+					return
+				case <-ctx.Done():
+					return
+				}
 			}
 		}()
 	}
@@ -0,0 +1,91 @@
+package specimport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+// Placeholder replaces {param} path segments in generated paths.
+const Placeholder = "1"
+
+// openAPIMethods are the operation keys a path item may declare.
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+var paramPattern = regexp.MustCompile(`\{[^}/]+\}`)
+
+type openAPIDoc struct {
+	Swagger  string                                `json:"swagger"`  // 2.x
+	OpenAPI  string                                `json:"openapi"`  // 3.x
+	BasePath string                                `json:"basePath"` // 2.x
+	Servers  []struct{ URL string }                `json:"servers"`  // 3.x
+	Paths    map[string]map[string]json.RawMessage `json:"paths"`
+}
+
+// LoadOpenAPI reads a spec from a local file or an http(s) URL, fetched
+// through req, and returns a work item for every declared path and method.
+func LoadOpenAPI(ctx context.Context, source string, req *scanner.Requester) ([]scanner.WorkItem, error) {
+	var data []byte
+	var err error
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		data, err = req.Fetch(ctx, source)
+	} else {
+		data, err = os.ReadFile(source)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading OpenAPI spec %s: %w", source, err)
+	}
+	return ParseOpenAPI(data)
+}
+
+// ParseOpenAPI parses a Swagger 2 or OpenAPI 3 JSON document into work
+// items, one per path and method, sorted by path. Path parameters such as
+// {id} are replaced with Placeholder, and the spec's base path (basePath
+// or the path of the first server URL) is prepended.
+func ParseOpenAPI(data []byte) ([]scanner.WorkItem, error) {
+	var doc openAPIDoc
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing OpenAPI spec: %w", err)
+	}
+	if doc.Swagger == "" && doc.OpenAPI == "" {
+		return nil, fmt.Errorf("parsing OpenAPI spec: missing swagger/openapi version field")
+	}
+
+	base := doc.BasePath
+	if len(doc.Servers) > 0 {
+		if u, err := url.Parse(doc.Servers[0].URL); err == nil {
+			base = u.Path
+		}
+	}
+	base = strings.Trim(base, "/")
+
+	specPaths := make([]string, 0, len(doc.Paths))
+	for p := range doc.Paths {
+		specPaths = append(specPaths, p)
+	}
+	sort.Strings(specPaths)
+
+	var items []scanner.WorkItem
+	for _, p := range specPaths {
+		path := strings.Trim(paramPattern.ReplaceAllString(p, Placeholder), "/")
+		if base != "" {
+			path = strings.TrimSuffix(base+"/"+path, "/")
+		}
+		if path == "" {
+			continue // the target root itself
+		}
+		for _, m := range openAPIMethods {
+			if _, ok := doc.Paths[p][m]; ok {
+				items = append(items, scanner.WorkItem{Method: strings.ToUpper(m), Path: path})
+			}
+		}
+	}
+	return items, nil
+}
@@ -0,0 +1,109 @@
+package specimport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/maxvaer/dirfuzz/internal/config"
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+const openAPI3Spec = `{
+  "openapi": "3.0.1",
+  "servers": [{"url": "https://api.example.com/v2"}],
+  "paths": {
+    "/users": {
+      "get": {"summary": "list"},
+      "post": {"summary": "create"}
+    },
+    "/users/{userId}/orders/{orderId}": {
+      "parameters": [{"name": "userId", "in": "path"}],
+      "get": {},
+      "delete": {}
+    },
+    "/": {"get": {}}
+  }
+}`
+
+func TestParseOpenAPI3(t *testing.T) {
+	items, err := ParseOpenAPI([]byte(openAPI3Spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []scanner.WorkItem{
+		{Method: "GET", Path: "v2"},
+		{Method: "GET", Path: "v2/users"},
+		{Method: "POST", Path: "v2/users"},
+		{Method: "GET", Path: "v2/users/1/orders/1"},
+		{Method: "DELETE", Path: "v2/users/1/orders/1"},
+	}
+	if !reflect.DeepEqual(items, want) {
+		t.Errorf("got  %v\nwant %v", items, want)
+	}
+}
+
+func TestParseSwagger2(t *testing.T) {
+	spec := `{
+	  "swagger": "2.0",
+	  "basePath": "/api",
+	  "paths": {
+	    "/pets/{id}": {"get": {}, "put": {}},
+	    "/health": {"head": {}}
+	  }
+	}`
+	items, err := ParseOpenAPI([]byte(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []scanner.WorkItem{
+		{Method: "HEAD", Path: "api/health"},
+		{Method: "GET", Path: "api/pets/1"},
+		{Method: "PUT", Path: "api/pets/1"},
+	}
+	if !reflect.DeepEqual(items, want) {
+		t.Errorf("got  %v\nwant %v", items, want)
+	}
+}
+
+func TestParseOpenAPI_Invalid(t *testing.T) {
+	for _, spec := range []string{`not json`, `{"paths": {}}`} {
+		if _, err := ParseOpenAPI([]byte(spec)); err == nil {
+			t.Errorf("expected error for %q", spec)
+		}
+	}
+}
+
+func TestLoadOpenAPI(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "spec.json")
+	if err := os.WriteFile(file, []byte(openAPI3Spec), 0644); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(openAPI3Spec))
+	}))
+	defer srv.Close()
+	req, err := scanner.NewRequester(&config.Options{URL: "http://target.invalid", Timeout: 5 * time.Second, Threads: 1, BearerToken: "token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, source := range []string{file, srv.URL + "/openapi.json"} {
+		items, err := LoadOpenAPI(context.Background(), source, req)
+		if err != nil {
+			t.Fatalf("LoadOpenAPI(%s): %v", source, err)
+		}
+		if len(items) != 5 {
+			t.Errorf("LoadOpenAPI(%s): got %d items, want 5", source, len(items))
+		}
+	}
+}
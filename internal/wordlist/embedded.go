@@ -5,5 +5,14 @@ import _ "embed"
 //go:embed dicc.txt
 var embeddedWordlist string
 
+//go:embed big.txt
+var embeddedBigExtras string
+
+//go:embed api.txt
+var embeddedAPIWordlist string
+
+//go:embed backup.txt
+var embeddedBackupWordlist string
+
 //go:embed vhosts.txt
 var embeddedVHostWordlist string
@@ -3,25 +3,69 @@ package wordlist
 import (
 	"fmt"
 	"os"
+	"slices"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
-// Load returns the list of paths to fuzz. If path is empty, the embedded
-// default wordlist is used. Extensions are expanded via %EXT% placeholders
-// and optionally force-appended to every entry.
-func Load(path string, extensions []string, forceExtensions bool) ([]string, error) {
-	var raw string
-	if path == "" {
-		raw = embeddedWordlist
-	} else {
+// Names lists the built-in wordlists selectable with --wordlist-name.
+var Names = []string{"common", "big", "api", "backup"}
+
+// named returns the raw contents of the built-in wordlist name. "big" is
+// the common list followed by its extra entries.
+func named(name string) (string, error) {
+	switch name {
+	case "common":
+		return embeddedWordlist, nil
+	case "big":
+		return embeddedWordlist + "\n" + embeddedBigExtras, nil
+	case "api":
+		return embeddedAPIWordlist, nil
+	case "backup":
+		return embeddedBackupWordlist, nil
+	}
+	return "", fmt.Errorf("unknown built-in wordlist %q (available: %s)", name, strings.Join(Names, ", "))
+}
+
+// CheckName reports an error if name is not a built-in wordlist.
+func CheckName(name string) error {
+	_, err := named(name)
+	return err
+}
+
+// Load returns the list of paths to fuzz, merged in order from every file
+// in paths with duplicates removed. If paths is empty, the embedded default
+// wordlist is used. Extensions are expanded via %EXT% placeholders and
+// optionally force-appended to every entry.
+func Load(paths []string, extensions []string, forceExtensions bool) ([]string, error) {
+	return LoadNamed("", paths, extensions, forceExtensions)
+}
+
+// LoadNamed is Load with the built-in wordlist name (see Names) in front of
+// the files in paths. An empty name selects "common" when paths is empty
+// and nothing otherwise.
+func LoadNamed(name string, paths []string, extensions []string, forceExtensions bool) ([]string, error) {
+	if name == "" && len(paths) == 0 {
+		name = "common"
+	}
+	var lines []string
+	if name != "" {
+		raw, err := named(name)
+		if err != nil {
+			return nil, err
+		}
+		lines = strings.Split(raw, "\n")
+	}
+	for _, path := range paths {
 		data, err := os.ReadFile(path)
 		if err != nil {
 			return nil, fmt.Errorf("reading wordlist %s: %w", path, err)
 		}
-		raw = string(data)
+		lines = append(lines, strings.Split(string(data), "\n")...)
 	}
 
-	lines := strings.Split(raw, "\n")
+	extensions = normalizeExtensions(extensions)
 	seen := make(map[string]struct{}, len(lines))
 	var result []string
 
@@ -43,7 +87,6 @@ func Load(path string, extensions []string, forceExtensions bool) ([]string, err
 
 		if strings.Contains(line, "%EXT%") {
 			for _, ext := range extensions {
-				ext = strings.TrimPrefix(ext, ".")
 				add(strings.ReplaceAll(line, "%EXT%", ext))
 			}
 			// Also add the bare version without extension placeholder.
@@ -53,7 +96,6 @@ func Load(path string, extensions []string, forceExtensions bool) ([]string, err
 		} else if forceExtensions && len(extensions) > 0 {
 			add(line)
 			for _, ext := range extensions {
-				ext = strings.TrimPrefix(ext, ".")
 				add(line + "." + ext)
 			}
 		} else {
@@ -64,6 +106,192 @@ func Load(path string, extensions []string, forceExtensions bool) ([]string, err
 	return result, nil
 }
 
+// LoadExtensions returns extensions followed by the extensions listed one
+// per line in path (--extensions-file), with leading dots stripped and
+// duplicates removed.
+func LoadExtensions(path string, extensions []string) ([]string, error) {
+	fromFile, err := LoadSimple(path)
+	if err != nil {
+		return nil, err
+	}
+	return normalizeExtensions(append(slices.Clone(extensions), fromFile...)), nil
+}
+
+// normalizeExtensions strips the leading dot from each extension and drops
+// empty entries and duplicates, keeping the first occurrence.
+func normalizeExtensions(extensions []string) []string {
+	seen := make(map[string]struct{}, len(extensions))
+	result := make([]string, 0, len(extensions))
+	for _, ext := range extensions {
+		ext = strings.TrimPrefix(strings.TrimSpace(ext), ".")
+		if ext == "" {
+			continue
+		}
+		if _, ok := seen[ext]; !ok {
+			seen[ext] = struct{}{}
+			result = append(result, ext)
+		}
+	}
+	return result
+}
+
+// LoadTemplated loads name and paths like LoadNamed, then replaces keyword
+// in each entry with every value from valuesPath, producing the
+// cross-product. Entries without the keyword pass through unchanged.
+func LoadTemplated(name string, paths []string, extensions []string, forceExtensions bool, keyword, valuesPath string) ([]string, error) {
+	entries, err := LoadNamed(name, paths, extensions, forceExtensions)
+	if err != nil {
+		return nil, err
+	}
+	values, err := LoadSimple(valuesPath)
+	if err != nil {
+		return nil, err
+	}
+	return expandKeyword(entries, keyword, values), nil
+}
+
+// expandKeyword substitutes every value for keyword in each entry,
+// de-duplicating the result while preserving order.
+func expandKeyword(entries []string, keyword string, values []string) []string {
+	seen := make(map[string]struct{}, len(entries))
+	var result []string
+	add := func(entry string) {
+		if _, ok := seen[entry]; !ok {
+			seen[entry] = struct{}{}
+			result = append(result, entry)
+		}
+	}
+	for _, entry := range entries {
+		if !strings.Contains(entry, keyword) {
+			add(entry)
+			continue
+		}
+		for _, v := range values {
+			add(strings.ReplaceAll(entry, keyword, v))
+		}
+	}
+	return result
+}
+
+// Mutate expands each entry into common case and shape variations:
+// capitalized, uppercase, with a leading dot, and with the trailing slash
+// toggled (e.g. admin → Admin, ADMIN, .admin, admin/). Original entries
+// keep their position and the result is de-duplicated.
+func Mutate(entries []string) []string {
+	seen := make(map[string]struct{}, len(entries)*5)
+	result := make([]string, 0, len(entries)*5)
+	add := func(entry string) {
+		if _, ok := seen[entry]; !ok {
+			seen[entry] = struct{}{}
+			result = append(result, entry)
+		}
+	}
+	for _, entry := range entries {
+		add(entry)
+		add(capitalize(entry))
+		add(strings.ToUpper(entry))
+		if !strings.HasPrefix(entry, ".") {
+			add("." + entry)
+		}
+		if trimmed := strings.TrimSuffix(entry, "/"); trimmed != entry {
+			if trimmed != "" {
+				add(trimmed)
+			}
+		} else {
+			add(entry + "/")
+		}
+	}
+	return result
+}
+
+// PriorityWords is the built-in list of high-value names that --prioritize
+// moves to the front of the wordlist.
+var PriorityWords = []string{
+	"admin", "administrator", "api", "backup", "backups", "config",
+	"configuration", "console", "dashboard", "db", "debug", "dump",
+	".env", ".git", "login", "phpinfo", "private", "secret", "server-status",
+	"upload", "uploads",
+}
+
+// Prioritize moves entries whose name is one of words to the front,
+// keeping the relative order of both the moved and the remaining entries.
+// An entry's name is its first path segment without a trailing slash or
+// extension, compared case-insensitively, so "admin" also pulls "Admin/",
+// "admin.php" and "admin/login" forward.
+func Prioritize(entries []string, words []string) []string {
+	names := make(map[string]struct{}, len(words))
+	for _, w := range words {
+		if w = strings.ToLower(strings.Trim(w, "/ ")); w != "" {
+			names[w] = struct{}{}
+		}
+	}
+	front := make([]string, 0, len(entries))
+	var rest []string
+	for _, entry := range entries {
+		if _, ok := names[entryName(entry)]; ok {
+			front = append(front, entry)
+		} else {
+			rest = append(rest, entry)
+		}
+	}
+	return append(front, rest...)
+}
+
+// entryName returns the lower-case first path segment of entry with any
+// extension removed. A leading dot is kept (".env", ".git").
+func entryName(entry string) string {
+	name := strings.ToLower(strings.TrimLeft(entry, "/"))
+	if i := strings.Index(name, "/"); i >= 0 {
+		name = name[:i]
+	}
+	if i := strings.Index(name[min(1, len(name)):], "."); i >= 0 {
+		name = name[:i+1]
+	}
+	return name
+}
+
+// Shard returns the window of entries starting at offset and holding at
+// most limit entries (0 = through the end), so a wordlist can be split
+// deterministically across machines. Out-of-range windows are clamped: an
+// offset past the end yields no entries.
+func Shard(entries []string, offset, limit int) []string {
+	offset = min(max(offset, 0), len(entries))
+	end := len(entries)
+	if limit > 0 {
+		end = min(offset+limit, end)
+	}
+	return entries[offset:end]
+}
+
+// Wrap puts prefix before and suffix after every entry (--prefix,
+// --suffix), de-duplicating the result while preserving order. A prefix
+// ending in "/" absorbs the leading slash of an entry, so "api/v2/" and
+// "/users" give "api/v2/users". Suffixes go after any extension.
+func Wrap(entries []string, prefix, suffix string) []string {
+	seen := make(map[string]struct{}, len(entries))
+	result := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if strings.HasSuffix(prefix, "/") {
+			entry = strings.TrimLeft(entry, "/")
+		}
+		entry = prefix + entry + suffix
+		if _, ok := seen[entry]; !ok {
+			seen[entry] = struct{}{}
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+// capitalize upper-cases the first letter of s.
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
 // LoadSimple reads a wordlist file and returns de-duplicated entries.
 // No extension expansion or placeholder processing is performed.
 // If path is empty, the embedded default for that context is used.
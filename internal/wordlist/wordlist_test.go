@@ -3,12 +3,13 @@ package wordlist
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 )
 
 func TestLoadEmbedded(t *testing.T) {
-	paths, err := Load("", nil, false)
+	paths, err := Load(nil, nil, false)
 	if err != nil {
 		t.Fatalf("Load embedded: %v", err)
 	}
@@ -26,6 +27,67 @@ func TestLoadEmbedded(t *testing.T) {
 	}
 }
 
+func TestLoadNamed(t *testing.T) {
+	lists := make(map[string][]string)
+	for _, name := range Names {
+		paths, err := LoadNamed(name, nil, nil, false)
+		if err != nil {
+			t.Fatalf("LoadNamed(%q): %v", name, err)
+		}
+		if len(paths) == 0 {
+			t.Fatalf("built-in wordlist %q is empty", name)
+		}
+		for _, other := range Names {
+			if prev, ok := lists[other]; ok && strings.Join(prev, "\n") == strings.Join(paths, "\n") {
+				t.Errorf("built-in wordlists %q and %q are identical", name, other)
+			}
+		}
+		lists[name] = paths
+	}
+
+	def, _ := Load(nil, nil, false)
+	if strings.Join(def, "\n") != strings.Join(lists["common"], "\n") {
+		t.Error("default wordlist should be the common list")
+	}
+	if len(lists["big"]) <= len(lists["common"]) {
+		t.Errorf("big has %d entries, want more than common's %d", len(lists["big"]), len(lists["common"]))
+	}
+	if err := CheckName("huge"); err == nil {
+		t.Error("expected an error for an unknown list")
+	}
+}
+
+func TestLoadNamedWithFiles(t *testing.T) {
+	wl := filepath.Join(t.TempDir(), "extra.txt")
+	if err := os.WriteFile(wl, []byte("custom-entry\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	paths, err := LoadNamed("api", []string{wl}, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if paths[len(paths)-1] != "custom-entry" || !slices.Contains(paths, "graphql") {
+		t.Errorf("want the api list followed by the file, got %d entries ending in %q", len(paths), paths[len(paths)-1])
+	}
+}
+
+func TestLoadExtensions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "exts.txt")
+	if err := os.WriteFile(path, []byte(".php\nphp\n\n  .asp  \n# comment\nhtml\nbak\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	exts, err := LoadExtensions(path, []string{"html", ".js"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(exts, ","); got != "html,js,php,asp,bak" {
+		t.Errorf("LoadExtensions = %q, want html,js,php,asp,bak", got)
+	}
+	if _, err := LoadExtensions(filepath.Join(t.TempDir(), "missing.txt"), nil); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
 func TestLoadWithExtensions(t *testing.T) {
 	dir := t.TempDir()
 	wl := filepath.Join(dir, "test.txt")
@@ -34,7 +96,7 @@ func TestLoadWithExtensions(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	paths, err := Load(wl, []string{"php", "html"}, false)
+	paths, err := Load([]string{wl}, []string{"php", "html"}, false)
 	if err != nil {
 		t.Fatalf("Load: %v", err)
 	}
@@ -67,7 +129,7 @@ func TestLoadForceExtensions(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	paths, err := Load(wl, []string{"php"}, true)
+	paths, err := Load([]string{wl}, []string{"php"}, true)
 	if err != nil {
 		t.Fatalf("Load: %v", err)
 	}
@@ -102,7 +164,7 @@ func TestLoadDeduplication(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	paths, err := Load(wl, nil, false)
+	paths, err := Load([]string{wl}, nil, false)
 	if err != nil {
 		t.Fatalf("Load: %v", err)
 	}
@@ -120,7 +182,7 @@ func TestLoadSkipsComments(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	paths, err := Load(wl, nil, false)
+	paths, err := Load([]string{wl}, nil, false)
 	if err != nil {
 		t.Fatalf("Load: %v", err)
 	}
@@ -129,3 +191,192 @@ func TestLoadSkipsComments(t *testing.T) {
 		t.Errorf("expected 2 entries (comments/blanks skipped), got %d: %v", len(paths), paths)
 	}
 }
+
+func TestLoadMultipleFiles(t *testing.T) {
+	dir := t.TempDir()
+	general := filepath.Join(dir, "general.txt")
+	tech := filepath.Join(dir, "tech.txt")
+	if err := os.WriteFile(general, []byte("admin\nlogin\nindex.%EXT%\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(tech, []byte("login\nconfig.%EXT%\nindex.%EXT%\nwp-admin\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	paths, err := Load([]string{general, tech}, []string{"php"}, false)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	want := []string{"admin", "login", "index.php", "index", "config.php", "config", "wp-admin"}
+	if strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Errorf("got %v, want %v", paths, want)
+	}
+}
+
+func TestLoadMultipleFilesForceExtensions(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.txt")
+	b := filepath.Join(dir, "b.txt")
+	if err := os.WriteFile(a, []byte("admin\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(b, []byte("admin\nbackup\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	paths, err := Load([]string{a, b}, []string{"php"}, true)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	want := []string{"admin", "admin.php", "backup", "backup.php"}
+	if strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Errorf("got %v, want %v", paths, want)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	if _, err := Load([]string{filepath.Join(t.TempDir(), "nope.txt")}, nil, false); err == nil {
+		t.Error("expected error for missing wordlist")
+	}
+}
+
+func TestLoadTemplated(t *testing.T) {
+	dir := t.TempDir()
+	wl := filepath.Join(dir, "routes.txt")
+	values := filepath.Join(dir, "versions.txt")
+	if err := os.WriteFile(wl, []byte("api/FUZZ/v1\nadmin\nFUZZ/FUZZ\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(values, []byte("users\norders\nusers\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	paths, err := LoadTemplated("", []string{wl}, nil, false, "FUZZ", values)
+	if err != nil {
+		t.Fatalf("LoadTemplated: %v", err)
+	}
+
+	want := []string{"api/users/v1", "api/orders/v1", "admin", "users/users", "orders/orders"}
+	if strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Errorf("got %v, want %v", paths, want)
+	}
+}
+
+func TestExpandKeywordPassesThroughBareEntries(t *testing.T) {
+	entries := []string{"admin", "login", "backup.zip"}
+	got := expandKeyword(entries, "FUZZ", []string{"a", "b"})
+	if strings.Join(got, ",") != strings.Join(entries, ",") {
+		t.Errorf("got %v, want entries unchanged %v", got, entries)
+	}
+}
+
+func TestMutate(t *testing.T) {
+	got := Mutate([]string{"admin"})
+	want := []string{"admin", "Admin", "ADMIN", ".admin", "admin/"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Mutate(admin) = %v, want %v", got, want)
+	}
+}
+
+func TestMutateCountsAndDedup(t *testing.T) {
+	tests := []struct {
+		entry string
+		want  []string
+	}{
+		// Already capitalized: capitalized variant is the entry itself.
+		{"Login", []string{"Login", "LOGIN", ".Login", "Login/"}},
+		// Leading dot: no extra dot, capitalize leaves the dot alone.
+		{".git", []string{".git", ".GIT", ".git/"}},
+		// Trailing slash is toggled off.
+		{"api/", []string{"api/", "Api/", "API/", ".api/", "api"}},
+	}
+	for _, tt := range tests {
+		got := Mutate([]string{tt.entry})
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("Mutate(%q) = %v, want %v", tt.entry, got, tt.want)
+		}
+	}
+
+	// Variants shared between entries are only emitted once.
+	got := Mutate([]string{"admin", "ADMIN"})
+	want := []string{"admin", "Admin", "ADMIN", ".admin", "admin/", ".ADMIN", "ADMIN/"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Mutate(admin, ADMIN) = %v, want %v", got, want)
+	}
+}
+
+func TestShard(t *testing.T) {
+	entries := []string{"a", "b", "c", "d", "e", "f", "g"}
+	tests := []struct {
+		offset, limit int
+		want          string
+	}{
+		{0, 0, "a,b,c,d,e,f,g"},
+		{0, 3, "a,b,c"},
+		{3, 3, "d,e,f"},
+		{6, 3, "g"},         // limit clamped to the end
+		{2, 0, "c,d,e,f,g"}, // no limit: through the end
+		{7, 3, ""},          // offset at the end
+		{100, 0, ""},        // offset past the end
+		{-2, 2, "a,b"},      // negative offset treated as 0
+	}
+	for _, tt := range tests {
+		got := strings.Join(Shard(entries, tt.offset, tt.limit), ",")
+		if got != tt.want {
+			t.Errorf("Shard(offset %d, limit %d) = %q, want %q", tt.offset, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestShardCoversWordlist(t *testing.T) {
+	entries := []string{"a", "b", "c", "d", "e", "f", "g"}
+	var all []string
+	for offset := 0; offset < len(entries); offset += 3 {
+		all = append(all, Shard(entries, offset, 3)...)
+	}
+	if strings.Join(all, ",") != strings.Join(entries, ",") {
+		t.Errorf("shards %v don't cover the wordlist exactly once", all)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		name           string
+		entries        []string
+		prefix, suffix string
+		want           []string
+	}{
+		{"prefix", []string{"admin", "login.php"}, "api/v2/", "", []string{"api/v2/admin", "api/v2/login.php"}},
+		{"suffix after extension", []string{"admin", "admin.php"}, "", "~", []string{"admin~", "admin.php~"}},
+		{"both", []string{"users"}, "v1/", ".json", []string{"v1/users.json"}},
+		{"prefix without slash", []string{"admin"}, "old_", "", []string{"old_admin"}},
+		{"no doubled slash, deduped", []string{"admin", "/admin", "login"}, "/api/", "", []string{"/api/admin", "/api/login"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Wrap(tt.entries, tt.prefix, tt.suffix)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Wrap() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrioritize(t *testing.T) {
+	entries := []string{"images", "Admin/", "about.html", "config.php", ".env", "admin/login", "administrators", "backup.tar.gz", "zzz"}
+	got := Prioritize(entries, []string{"admin", "config", ".env", "backup"})
+	want := []string{"Admin/", "config.php", ".env", "admin/login", "backup.tar.gz", "images", "about.html", "administrators", "zzz"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Prioritize() = %v, want %v", got, want)
+	}
+}
+
+func TestPrioritizeBuiltin(t *testing.T) {
+	got := Prioritize([]string{"about", "index.html", "backup", "server-status"}, PriorityWords)
+	want := []string{"backup", "server-status", "about", "index.html"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Prioritize() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,25 @@
+// Package dirfuzz exposes the scan pipeline to other Go programs. The
+// implementation lives in internal packages; this package re-exports the
+// types needed to configure a scan and consume its results.
+package dirfuzz
+
+import (
+	"context"
+
+	"github.com/maxvaer/dirfuzz/internal/config"
+	"github.com/maxvaer/dirfuzz/internal/runner"
+	"github.com/maxvaer/dirfuzz/internal/scanner"
+)
+
+// Options configures a scan. Field semantics match the CLI flags.
+type Options = config.Options
+
+// Result is a single response that passed all filters.
+type Result = scanner.ScanResult
+
+// Scan runs a scan and streams non-filtered results on the returned
+// channel, which is closed when the scan completes or ctx is cancelled.
+// See runner.Scan for details.
+func Scan(ctx context.Context, opts *Options) (<-chan Result, error) {
+	return runner.Scan(ctx, opts)
+}